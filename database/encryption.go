@@ -0,0 +1,220 @@
+package database
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"github.com/openware/pkg/encryptor/types"
+)
+
+const (
+	// EncryptedSerializerName is the default name of the encrypted fields serializer
+	EncryptedSerializerName = "encrypted"
+	// TagBlindIndex marks a field as the blind index of another field
+	TagBlindIndex = "blind_index"
+)
+
+// EncryptedSerializer transparently encrypts and decrypts model fields with an Encryptor.
+//
+// Strings and byte slices are encrypted as is, any other type is encoded to JSON first:
+//
+//	type User struct {
+//		ID    uint
+//		Email string `gorm:"serializer:encrypted"`
+//	}
+type EncryptedSerializer struct {
+	Encryptor types.Encryptor
+	AppName   string
+}
+
+// RegisterEncryptedSerializer registers an EncryptedSerializer under the given name,
+// it can then be used on model fields with the `gorm:"serializer:<name>"` tag
+func RegisterEncryptedSerializer(name string, encryptor types.Encryptor, appName string) {
+	schema.RegisterSerializer(name, EncryptedSerializer{
+		Encryptor: encryptor,
+		AppName:   appName,
+	})
+}
+
+// Scan implements serializer interface
+func (s EncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	fieldValue := reflect.New(field.FieldType)
+
+	if dbValue != nil {
+		var ciphertext string
+		switch v := dbValue.(type) {
+		case []byte:
+			ciphertext = string(v)
+		case string:
+			ciphertext = v
+		default:
+			return fmt.Errorf("failed to decrypt value: %#v", dbValue)
+		}
+
+		if ciphertext != "" {
+			plaintext, err := s.Encryptor.Decrypt(ciphertext, s.AppName)
+			if err != nil {
+				return err
+			}
+
+			switch t := field.FieldType; {
+			case t.Kind() == reflect.String:
+				fieldValue.Elem().SetString(plaintext)
+			case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+				fieldValue.Elem().SetBytes([]byte(plaintext))
+			default:
+				if err := json.Unmarshal([]byte(plaintext), fieldValue.Interface()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return nil
+}
+
+// Value implements serializer interface
+func (s EncryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(fieldValue)
+	if fieldValue == nil || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
+		return nil, nil
+	}
+
+	var plaintext string
+	switch {
+	case rv.Kind() == reflect.String:
+		plaintext = rv.String()
+	case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+		plaintext = string(rv.Bytes())
+	default:
+		data, err := json.Marshal(fieldValue)
+		if err != nil {
+			return nil, err
+		}
+		plaintext = string(data)
+	}
+
+	// Empty values are stored as is, so they stay distinguishable from NULL
+	if plaintext == "" {
+		return "", nil
+	}
+
+	return s.Encryptor.Encrypt(plaintext, s.AppName)
+}
+
+// BlindIndex is a gorm plugin filling blind index columns, which are HMAC of the plaintext
+// value of another field, so encrypted fields can be looked up by equality:
+//
+//	type User struct {
+//		ID         uint
+//		Email      string `gorm:"serializer:encrypted"`
+//		EmailIndex string `gorm:"index" blind_index:"Email"`
+//	}
+//
+//	bi := database.NewBlindIndex(key)
+//	db.Use(bi)
+//	db.Where("email_index = ?", bi.Compute("john@example.com")).First(&user)
+//
+// Indexes are set on Create, Save and Updates with a struct,
+// they have to be set manually with Compute when updating with a map.
+type BlindIndex struct {
+	key []byte
+}
+
+// NewBlindIndex instantiates a blind index plugin using the given HMAC key
+func NewBlindIndex(key []byte) *BlindIndex {
+	return &BlindIndex{key: key}
+}
+
+// Name implements gorm.Plugin interface
+func (bi *BlindIndex) Name() string {
+	return "database:blind_index"
+}
+
+// Initialize implements gorm.Plugin interface
+func (bi *BlindIndex) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register(bi.Name(), bi.beforeCreate); err != nil {
+		return err
+	}
+
+	return db.Callback().Update().Before("gorm:update").Register(bi.Name(), bi.beforeUpdate)
+}
+
+// Compute returns the blind index of the given value
+func (bi *BlindIndex) Compute(value string) string {
+	mac := hmac.New(sha256.New, bi.key)
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (bi *BlindIndex) beforeCreate(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	bi.setIndexes(db, db.Statement.ReflectValue)
+}
+
+func (bi *BlindIndex) beforeUpdate(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	bi.setIndexes(db, db.Statement.ReflectValue)
+
+	// Updates with a struct read the values from the destination instead of the model
+	if db.Statement.Dest != db.Statement.Model {
+		dest := reflect.Indirect(reflect.ValueOf(db.Statement.Dest))
+		if dest.Kind() == reflect.Struct && dest.Type() == db.Statement.Schema.ModelType && dest.CanAddr() {
+			bi.setIndexes(db, dest)
+		}
+	}
+}
+
+func (bi *BlindIndex) setIndexes(db *gorm.DB, rv reflect.Value) {
+	ctx := db.Statement.Context
+
+	for _, field := range db.Statement.Schema.Fields {
+		sourceName, ok := field.Tag.Lookup(TagBlindIndex)
+		if !ok {
+			continue
+		}
+
+		source := db.Statement.Schema.LookUpField(sourceName)
+		if source == nil {
+			db.AddError(fmt.Errorf("blind index source field %q not found", sourceName))
+			return
+		}
+
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < rv.Len(); i++ {
+				bi.setIndex(db, ctx, field, source, reflect.Indirect(rv.Index(i)))
+			}
+		case reflect.Struct:
+			bi.setIndex(db, ctx, field, source, rv)
+		}
+	}
+}
+
+func (bi *BlindIndex) setIndex(db *gorm.DB, ctx context.Context, field, source *schema.Field, rv reflect.Value) {
+	// Serialized fields values are wrapped by ValueOf, read the plain value instead
+	value := source.ReflectValueOf(ctx, rv)
+	if value.IsZero() {
+		return
+	}
+
+	if err := field.Set(ctx, rv, bi.Compute(fmt.Sprint(reflect.Indirect(value).Interface()))); err != nil {
+		db.AddError(err)
+	}
+}
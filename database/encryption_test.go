@@ -0,0 +1,91 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gotest.tools/assert"
+
+	"github.com/openware/pkg/database"
+	"github.com/openware/pkg/encryptor/aes"
+)
+
+type encryptedUser struct {
+	ID         uint
+	Email      string            `gorm:"serializer:encrypted"`
+	EmailIndex string            `gorm:"index" blind_index:"Email"`
+	Phone      *string           `gorm:"serializer:encrypted"`
+	Metadata   map[string]string `gorm:"serializer:encrypted"`
+}
+
+func Test_Encryption(t *testing.T) {
+	enc, err := aes.NewAESEncryptor([]byte("1234567890123456"))
+	require.NoError(t, err)
+	database.RegisterEncryptedSerializer(database.EncryptedSerializerName, enc, "opendax")
+
+	db, err := database.Connect(&database.Config{
+		Driver:   database.SqliteDriver,
+		Name:     "encryption",
+		InMemory: true,
+	})
+	require.NoError(t, err)
+
+	bi := database.NewBlindIndex([]byte("changeme"))
+	require.NoError(t, db.Use(bi))
+	require.NoError(t, db.AutoMigrate(&encryptedUser{}))
+
+	phone := "+33612345678"
+	user := encryptedUser{
+		Email:    "john@example.com",
+		Phone:    &phone,
+		Metadata: map[string]string{"country": "FR"},
+	}
+	require.NoError(t, db.Create(&user).Error)
+	assert.Equal(t, bi.Compute("john@example.com"), user.EmailIndex)
+
+	t.Run("Stored encrypted", func(t *testing.T) {
+		var raw struct {
+			Email    string
+			Phone    string
+			Metadata string
+		}
+		require.NoError(t, db.Table("encrypted_users").Where("id = ?", user.ID).Scan(&raw).Error)
+
+		require.NotEqual(t, "john@example.com", raw.Email)
+		require.NotEqual(t, phone, raw.Phone)
+		require.NotContains(t, raw.Metadata, "FR")
+
+		plain, err := enc.Decrypt(raw.Email, "opendax")
+		require.NoError(t, err)
+		assert.Equal(t, "john@example.com", plain)
+	})
+
+	t.Run("Decrypted on read", func(t *testing.T) {
+		var found encryptedUser
+		require.NoError(t, db.Where("email_index = ?", bi.Compute("john@example.com")).First(&found).Error)
+
+		assert.Equal(t, user.ID, found.ID)
+		assert.Equal(t, "john@example.com", found.Email)
+		assert.Equal(t, phone, *found.Phone)
+		assert.Equal(t, "FR", found.Metadata["country"])
+	})
+
+	t.Run("Blind index updated", func(t *testing.T) {
+		require.NoError(t, db.Model(&user).Updates(&encryptedUser{Email: "jane@example.com"}).Error)
+
+		var found encryptedUser
+		require.NoError(t, db.Where("email_index = ?", bi.Compute("jane@example.com")).First(&found).Error)
+		assert.Equal(t, "jane@example.com", found.Email)
+		assert.Equal(t, phone, *found.Phone)
+	})
+
+	t.Run("Nil values", func(t *testing.T) {
+		empty := encryptedUser{Email: "empty@example.com"}
+		require.NoError(t, db.Create(&empty).Error)
+
+		var found encryptedUser
+		require.NoError(t, db.First(&found, empty.ID).Error)
+		require.Nil(t, found.Phone)
+		require.Nil(t, found.Metadata)
+	})
+}
@@ -0,0 +1,137 @@
+package encryptor
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/openware/pkg/encryptor/aes"
+	"github.com/openware/pkg/encryptor/plaintext"
+	"github.com/openware/pkg/encryptor/transit"
+	"github.com/openware/pkg/encryptor/types"
+)
+
+type Driver string
+
+const (
+	PlaintextDriver Driver = "plaintext"
+	AESDriver       Driver = "aes"
+	TransitDriver   Driver = "transit"
+)
+
+// Config for encryptor instantiation, to be loaded with ika
+type Config struct {
+	Driver         Driver            `yaml:"driver" env:"ENCRYPTOR_DRIVER" env-default:"aes" env-description:"Encryptor driver (plaintext, aes, transit)"`
+	AESKey         string            `env:"ENCRYPTOR_AES_KEY" env-description:"AES key, 16, 24 or 32 bytes long"`
+	AESKeyFile     string            `yaml:"aes_key_file" env:"ENCRYPTOR_AES_KEY_FILE" env-description:"Path to a file containing the AES key"`
+	VaultAddr      string            `yaml:"vault_addr" env:"ENCRYPTOR_VAULT_ADDR,KAIGARA_VAULT_ADDR" env-description:"Vault address"`
+	VaultToken     string            `env:"ENCRYPTOR_VAULT_TOKEN,KAIGARA_VAULT_TOKEN" env-description:"Vault token"`
+	AllowPlaintext bool              `yaml:"allow_plaintext" env:"ENCRYPTOR_ALLOW_PLAINTEXT" env-description:"Allow plaintext driver, for development only"`
+	Options        map[string]string `yaml:"options" env:"ENCRYPTOR_OPTIONS" env-description:"Additional options for third-party drivers"`
+}
+
+// Factory builds an Encryptor from the given configuration
+type Factory func(cfg *Config) (types.Encryptor, error)
+
+// ConfigError is returned when the configuration misses values required by a driver
+type ConfigError struct {
+	Driver  Driver
+	Missing []string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("%s encryptor configuration is invalid, missing: %s", e.Driver, strings.Join(e.Missing, ", "))
+}
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[Driver]Factory{
+		PlaintextDriver: newPlaintext,
+		AESDriver:       newAES,
+		TransitDriver:   newTransit,
+	}
+)
+
+// Register makes an encryptor driver available by the provided name.
+// Registering an already registered name replaces the previous factory.
+func Register(driver Driver, factory Factory) {
+	if factory == nil {
+		panic("encryptor: Register factory is nil")
+	}
+
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[driver] = factory
+}
+
+// Drivers returns a sorted list of the registered drivers
+func Drivers() []Driver {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	list := make([]Driver, 0, len(factories))
+	for d := range factories {
+		list = append(list, d)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+
+	return list
+}
+
+// New instantiates the encryptor selected by the configuration driver
+func New(cfg *Config) (types.Encryptor, error) {
+	if cfg == nil || cfg.Driver == "" {
+		return nil, &ConfigError{Missing: []string{"ENCRYPTOR_DRIVER"}}
+	}
+
+	factoriesMu.RLock()
+	factory, ok := factories[cfg.Driver]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported encryptor driver: %s", cfg.Driver)
+	}
+
+	return factory(cfg)
+}
+
+func newPlaintext(cfg *Config) (types.Encryptor, error) {
+	if !cfg.AllowPlaintext {
+		return nil, fmt.Errorf("plaintext encryptor is for development only, set ENCRYPTOR_ALLOW_PLAINTEXT to use it")
+	}
+
+	return plaintext.NewPlaintextEncryptor(), nil
+}
+
+func newAES(cfg *Config) (types.Encryptor, error) {
+	key := cfg.AESKey
+	if key == "" && cfg.AESKeyFile != "" {
+		data, err := os.ReadFile(cfg.AESKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read AES key file: %w", err)
+		}
+		key = strings.TrimSpace(string(data))
+	}
+
+	if key == "" {
+		return nil, &ConfigError{Driver: cfg.Driver, Missing: []string{"ENCRYPTOR_AES_KEY or ENCRYPTOR_AES_KEY_FILE"}}
+	}
+
+	return aes.NewAESEncryptor([]byte(key))
+}
+
+func newTransit(cfg *Config) (types.Encryptor, error) {
+	missing := []string{}
+	if cfg.VaultAddr == "" {
+		missing = append(missing, "ENCRYPTOR_VAULT_ADDR")
+	}
+	if cfg.VaultToken == "" {
+		missing = append(missing, "ENCRYPTOR_VAULT_TOKEN")
+	}
+	if len(missing) != 0 {
+		return nil, &ConfigError{Driver: cfg.Driver, Missing: missing}
+	}
+
+	return transit.NewVaultEncryptor(cfg.VaultAddr, cfg.VaultToken)
+}
@@ -0,0 +1,107 @@
+package encryptor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openware/pkg/encryptor/aes"
+	"github.com/openware/pkg/encryptor/plaintext"
+	"github.com/openware/pkg/encryptor/types"
+	"github.com/openware/pkg/ika"
+	"github.com/stretchr/testify/require"
+	"gotest.tools/assert"
+)
+
+func TestNewAES(t *testing.T) {
+	t.Run("key from env", func(t *testing.T) {
+		t.Setenv("ENCRYPTOR_DRIVER", "aes")
+		t.Setenv("ENCRYPTOR_AES_KEY", "1234567890123456")
+
+		cfg := Config{}
+		require.NoError(t, ika.ReadEnv(&cfg))
+
+		e, err := New(&cfg)
+		require.NoError(t, err)
+		require.IsType(t, &aes.AESEncryptor{}, e)
+
+		cipher, err := e.Encrypt("bonjour", "")
+		require.NoError(t, err)
+
+		plain, err := e.Decrypt(cipher, "")
+		require.NoError(t, err)
+		assert.Equal(t, "bonjour", plain)
+	})
+
+	t.Run("key from file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "aes.key")
+		require.NoError(t, os.WriteFile(path, []byte("1234567890123456\n"), 0600))
+
+		e, err := New(&Config{Driver: AESDriver, AESKeyFile: path})
+		require.NoError(t, err)
+		require.IsType(t, &aes.AESEncryptor{}, e)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		_, err := New(&Config{Driver: AESDriver})
+
+		var cfgErr *ConfigError
+		require.True(t, errors.As(err, &cfgErr))
+		assert.Equal(t, AESDriver, cfgErr.Driver)
+		assert.Equal(t, 1, len(cfgErr.Missing))
+	})
+}
+
+func TestNewTransitMissing(t *testing.T) {
+	_, err := New(&Config{Driver: TransitDriver})
+
+	var cfgErr *ConfigError
+	require.True(t, errors.As(err, &cfgErr))
+	assert.DeepEqual(t, []string{"ENCRYPTOR_VAULT_ADDR", "ENCRYPTOR_VAULT_TOKEN"}, cfgErr.Missing)
+	assert.Equal(t, "transit encryptor configuration is invalid, missing: ENCRYPTOR_VAULT_ADDR, ENCRYPTOR_VAULT_TOKEN", err.Error())
+}
+
+func TestNewPlaintext(t *testing.T) {
+	_, err := New(&Config{Driver: PlaintextDriver})
+	require.Error(t, err)
+
+	e, err := New(&Config{Driver: PlaintextDriver, AllowPlaintext: true})
+	require.NoError(t, err)
+	require.IsType(t, &plaintext.PlaintextEncryptor{}, e)
+}
+
+func TestNewUnsupported(t *testing.T) {
+	_, err := New(&Config{Driver: "rot13"})
+	require.Error(t, err)
+
+	_, err = New(&Config{})
+	require.Error(t, err)
+}
+
+type prefixEncryptor struct {
+	prefix string
+}
+
+func (p *prefixEncryptor) Encrypt(plaintext, appName string) (string, error) {
+	return p.prefix + plaintext, nil
+}
+
+func (p *prefixEncryptor) Decrypt(ciphertext, appName string) (string, error) {
+	return ciphertext[len(p.prefix):], nil
+}
+
+func TestRegister(t *testing.T) {
+	Register("prefix", func(cfg *Config) (types.Encryptor, error) {
+		return &prefixEncryptor{prefix: cfg.Options["prefix"]}, nil
+	})
+
+	require.Contains(t, Drivers(), Driver("prefix"))
+
+	e, err := New(&Config{Driver: "prefix", Options: map[string]string{"prefix": "enc:"}})
+	require.NoError(t, err)
+
+	cipher, err := e.Encrypt("bonjour", "")
+	require.NoError(t, err)
+	assert.Equal(t, "enc:bonjour", cipher)
+}
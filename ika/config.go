@@ -12,8 +12,11 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
 	"gopkg.in/yaml.v3"
 )
 
@@ -102,6 +105,44 @@ func UpdateEnv(cfg interface{}) error {
 	return readEnvVars(cfg, true)
 }
 
+// ParseFunc parses a configuration file into the structure
+type ParseFunc func(r io.Reader, cfg interface{}) error
+
+var (
+	formatsLock sync.RWMutex
+	formats     = map[string]ParseFunc{
+		".yaml": parseYAML,
+		".yml":  parseYAML,
+		".json": parseJSON,
+		".toml": parseTOML,
+		".env":  parseENV,
+	}
+)
+
+// RegisterFormat registers the parser of the configuration files with the given extensions,
+// replacing the parser of a supported format.
+//
+// Example:
+//
+//	ika.RegisterFormat(func(r io.Reader, cfg interface{}) error {
+//		src, err := io.ReadAll(r)
+//		if err != nil {
+//			return err
+//		}
+//		return hclsimple.Decode("config.hcl", src, nil, cfg)
+//	}, ".hcl")
+func RegisterFormat(parse ParseFunc, exts ...string) {
+	formatsLock.Lock()
+	defer formatsLock.Unlock()
+
+	for _, ext := range exts {
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		formats[strings.ToLower(ext)] = parse
+	}
+}
+
 // parseFile parses configuration file according to it's extension
 //
 // Currently following file extensions are supported:
@@ -114,7 +155,7 @@ func UpdateEnv(cfg interface{}) error {
 //
 // - env
 //
-// - edn
+// Other formats may be added with RegisterFormat
 func parseFile(path string, cfg interface{}) error {
 	// open the configuration file
 	f, err := os.OpenFile(path, os.O_RDONLY|os.O_SYNC, 0)
@@ -126,21 +167,26 @@ func parseFile(path string, cfg interface{}) error {
 	defer f.Close()
 
 	// parse the file depending on the file type
-	switch ext := strings.ToLower(filepath.Ext(path)); ext {
-	case ".yaml", ".yml":
-		err = parseYAML(f, cfg)
-	case ".json":
-		err = parseJSON(f, cfg)
-	default:
+	ext := strings.ToLower(filepath.Ext(path))
+	parse := parserOf(ext)
+	if parse == nil {
 		return fmt.Errorf("file format '%s' doesn't supported by the parser", ext)
 	}
-	if err != nil {
+	if err := parse(f, cfg); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// parserOf returns the parser of a file extension, nil if the format is not supported
+func parserOf(ext string) ParseFunc {
+	formatsLock.RLock()
+	defer formatsLock.RUnlock()
+
+	return formats[ext]
+}
+
 // parseYAML parses YAML from reader to data structure
 func parseYAML(r io.Reader, str interface{}) error {
 	return yaml.NewDecoder(r).Decode(str)
@@ -151,10 +197,50 @@ func parseJSON(r io.Reader, str interface{}) error {
 	return json.NewDecoder(r).Decode(str)
 }
 
+// parseTOML parses TOML from reader to data structure
+func parseTOML(r io.Reader, str interface{}) error {
+	_, err := toml.NewDecoder(r).Decode(str)
+	return err
+}
+
+// parseENV parses dotenv variables from reader and sets the fields with the matching env tags.
+// Environment variables are read afterwards by ReadConfig and take precedence
+func parseENV(r io.Reader, str interface{}) error {
+	vars, err := godotenv.Parse(r)
+	if err != nil {
+		return err
+	}
+
+	return setVars(str, vars)
+}
+
+// setVars sets the fields with the env tags matching the variables
+func setVars(cfg interface{}, vars map[string]string) error {
+	metaInfo, err := readStructMetadata(cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, meta := range metaInfo {
+		for _, env := range meta.envList {
+			if value, ok := vars[env]; ok {
+				if err := parseValue(meta.fieldValue, value, meta.separator, meta.layout); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
 // structMeta is a structure metadata entity
 type structMeta struct {
 	envList     []string
 	fieldName   string
+	fieldPath   string
+	fieldKey    string
 	fieldValue  reflect.Value
 	defValue    *string
 	layout      *string
@@ -169,9 +255,77 @@ func (sm *structMeta) isFieldValueZero() bool {
 	return isZero(sm.fieldValue)
 }
 
+// Field is a field of a configuration structure with the settings of its tags,
+// so that other readers, e.g. command line flags, parse its value like ika does
+type Field struct {
+	// Path of the field in the structure, e.g. Database.Host
+	Path string
+	// Key of the field in the configuration files, e.g. database.host
+	Key string
+	// Envs are the environment variables of the env tag
+	Envs []string
+	// Default is the value of the env-default tag, nil without the tag
+	Default *string
+	// Description is the value of the env-description tag
+	Description string
+	// Separator splits the items of lists and maps, DefaultSeparator without env-separator tag
+	Separator string
+	// Required is set by the env-required tag
+	Required bool
+	// Updatable is set by the env-upd tag
+	Updatable bool
+
+	meta structMeta
+}
+
+// Fields returns the fields of a configuration structure, those of the nested structures included
+func Fields(cfg interface{}) ([]Field, error) {
+	metaInfo, err := readStructMetadata(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]Field, len(metaInfo))
+	for i, meta := range metaInfo {
+		fields[i] = Field{
+			Path:        meta.fieldPath,
+			Key:         meta.fieldKey,
+			Envs:        meta.envList,
+			Default:     meta.defValue,
+			Description: meta.description,
+			Separator:   meta.separator,
+			Required:    meta.required,
+			Updatable:   meta.updatable,
+			meta:        meta,
+		}
+	}
+
+	return fields, nil
+}
+
+// Value returns the value of the field in the structure
+func (f Field) Value() reflect.Value {
+	return f.meta.fieldValue
+}
+
+// IsZero reports whether the field is empty
+func (f Field) IsZero() bool {
+	return f.meta.isFieldValueZero()
+}
+
+// Set parses a raw value into the field like an environment variable,
+// lists and maps are split with the field separator and times parsed with its env-layout
+func (f Field) Set(value string) error {
+	return parseValue(f.meta.fieldValue, value, f.meta.separator, f.meta.layout)
+}
+
 // readStructMetadata reads structure metadata (types, tags, etc.)
 func readStructMetadata(cfgRoot interface{}) ([]structMeta, error) {
 	cfgStack := []interface{}{cfgRoot}
+	// paths of the nested structures, to name their fields
+	pathStack := []string{""}
+	// keys of the nested structures in the configuration files
+	keyStack := []string{""}
 	metas := make([]structMeta, 0)
 
 	for i := 0; i < len(cfgStack); i++ {
@@ -204,6 +358,8 @@ func readStructMetadata(cfgRoot interface{}) ([]structMeta, error) {
 				// add structure to parsing stack
 				if fld.Type() != reflect.TypeOf(time.Time{}) {
 					cfgStack = append(cfgStack, fld.Addr().Interface())
+					pathStack = append(pathStack, pathStack[i]+fType.Name+".")
+					keyStack = append(keyStack, keyStack[i]+fieldKey(fType)+".")
 					continue
 				}
 				// process time.Time
@@ -240,6 +396,8 @@ func readStructMetadata(cfgRoot interface{}) ([]structMeta, error) {
 			metas = append(metas, structMeta{
 				envList:     envList,
 				fieldName:   s.Type().Field(idx).Name,
+				fieldPath:   pathStack[i] + s.Type().Field(idx).Name,
+				fieldKey:    keyStack[i] + fieldKey(fType),
 				fieldValue:  s.Field(idx),
 				defValue:    defValue,
 				layout:      layout,
@@ -255,6 +413,14 @@ func readStructMetadata(cfgRoot interface{}) ([]structMeta, error) {
 	return metas, nil
 }
 
+// fieldKey returns the key of a field in the configuration files, its yaml name
+func fieldKey(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("yaml"), ",")[0]; name != "" {
+		return name
+	}
+	return strings.ToLower(field.Name)
+}
+
 // readEnvVars reads environment variables to the provided configuration structure
 func readEnvVars(cfg interface{}, update bool) error {
 	metaInfo, err := readStructMetadata(cfg)
@@ -268,6 +434,11 @@ func readEnvVars(cfg interface{}, update bool) error {
 		}
 	}
 
+	return setEnvVars(metaInfo, update)
+}
+
+// setEnvVars sets the fields from environment variables, or their default
+func setEnvVars(metaInfo []structMeta, update bool) error {
 	for _, meta := range metaInfo {
 		// update only updatable fields
 		if update && !meta.updatable {
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -438,16 +440,16 @@ func TestReadUpdateFunctions(t *testing.T) {
 
 func TestParseFile(t *testing.T) {
 	type configObject struct {
-		One int `yaml:"one" json:"one"`
-		Two int `yaml:"two" json:"two"`
+		One int `yaml:"one" json:"one" toml:"one" env:"ONE"`
+		Two int `yaml:"two" json:"two" toml:"two" env:"TWO"`
 	}
 	type config struct {
-		Number  int64        `yaml:"number" json:"number"`
-		Float   float64      `yaml:"float" json:"float"`
-		String  string       `yaml:"string" json:"string"`
-		Boolean bool         `yaml:"boolean" json:"boolean"`
-		Object  configObject `yaml:"object" json:"object"`
-		Array   []int        `yaml:"array" json:"array"`
+		Number  int64        `yaml:"number" json:"number" toml:"number" env:"NUMBER"`
+		Float   float64      `yaml:"float" json:"float" toml:"float" env:"FLOAT"`
+		String  string       `yaml:"string" json:"string" toml:"string" env:"STRING"`
+		Boolean bool         `yaml:"boolean" json:"boolean" toml:"boolean" env:"BOOLEAN"`
+		Object  configObject `yaml:"object" json:"object" toml:"object"`
+		Array   []int        `yaml:"array" json:"array" toml:"array" env:"ARRAY"`
 	}
 
 	wantConfig := config{
@@ -500,6 +502,56 @@ array: [1, 2, 3]`,
 			wantErr: false,
 		},
 
+		{
+			name: "toml",
+			file: `
+number = 1
+float = 2.3
+string = "test"
+boolean = true
+array = [1, 2, 3]
+
+[object]
+one = 1
+two = 2`,
+			ext:     "toml",
+			want:    &wantConfig,
+			wantErr: false,
+		},
+
+		{
+			name: "env",
+			file: `
+# comment
+NUMBER=1
+FLOAT=2.3
+export STRING="test"
+BOOLEAN=true
+ONE=1
+TWO=2
+ARRAY=1,2,3
+UNKNOWN=ignored`,
+			ext:     "env",
+			want:    &wantConfig,
+			wantErr: false,
+		},
+
+		{
+			name:    "toml parsing error",
+			file:    "number = [",
+			ext:     "toml",
+			want:    nil,
+			wantErr: true,
+		},
+
+		{
+			name:    "env parsing error",
+			file:    "NUMBER=one",
+			ext:     "env",
+			want:    nil,
+			wantErr: true,
+		},
+
 		{
 			name:    "unknown",
 			file:    "-",
@@ -907,3 +959,124 @@ no-env: this
 		})
 	}
 }
+
+func TestReadConfigDotenv(t *testing.T) {
+	type config struct {
+		Host string `env:"DOTENV_HOST" env-default:"localhost"`
+		Port int    `env:"DOTENV_PORT" env-default:"5432"`
+		Name string `env:"DOTENV_NAME" env-default:"postgres"`
+	}
+
+	tmpFile, err := ioutil.TempFile(os.TempDir(), "*.env")
+	if err != nil {
+		t.Fatal("cannot create temporary file:", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err = tmpFile.WriteString("DOTENV_HOST=db\nDOTENV_PORT=6432\n"); err != nil {
+		t.Fatal("failed to write to temporary file:", err)
+	}
+
+	// environment variables take precedence over the file
+	t.Setenv("DOTENV_PORT", "7432")
+
+	var cfg config
+	if err := ReadConfig(tmpFile.Name(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if want := (config{Host: "db", Port: 7432, Name: "postgres"}); cfg != want {
+		t.Errorf("wrong data %v, want %v", cfg, want)
+	}
+	if _, ok := os.LookupEnv("DOTENV_HOST"); ok {
+		t.Error("dotenv variables must not be exported")
+	}
+}
+
+func TestRegisterFormat(t *testing.T) {
+	type config struct {
+		Name string
+	}
+
+	// parse files with a name per line
+	RegisterFormat(func(r io.Reader, cfg interface{}) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		cfg.(*config).Name = strings.TrimSpace(string(data))
+		return nil
+	}, "names", ".LST")
+	defer func() {
+		formatsLock.Lock()
+		delete(formats, ".names")
+		delete(formats, ".lst")
+		formatsLock.Unlock()
+	}()
+
+	for _, ext := range []string{"names", "lst"} {
+		tmpFile, err := ioutil.TempFile(os.TempDir(), "*."+ext)
+		if err != nil {
+			t.Fatal("cannot create temporary file:", err)
+		}
+		defer os.Remove(tmpFile.Name())
+		if _, err = tmpFile.WriteString("peatio\n"); err != nil {
+			t.Fatal("failed to write to temporary file:", err)
+		}
+
+		var cfg config
+		if err := parseFile(tmpFile.Name(), &cfg); err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Name != "peatio" {
+			t.Errorf("wrong data %v for %s", cfg, ext)
+		}
+	}
+}
+
+func TestFields(t *testing.T) {
+	type config struct {
+		Database struct {
+			Hosts []string `yaml:"hosts" env:"DB_HOSTS,HOSTS" env-separator:";" env-description:"Database hosts" env-required:""`
+		} `yaml:"database"`
+		Labels map[string]int `yaml:"labels" env-default:"a:1,b:2" env-upd:""`
+		Since  time.Time      `env-layout:"2006-01-02"`
+	}
+
+	var cfg config
+	fields, err := Fields(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) != 3 {
+		t.Fatalf("wrong number of fields %d", len(fields))
+	}
+
+	hosts := fields[2]
+	if hosts.Path != "Database.Hosts" || hosts.Key != "database.hosts" || !reflect.DeepEqual(hosts.Envs, []string{"DB_HOSTS", "HOSTS"}) ||
+		hosts.Separator != ";" || hosts.Description != "Database hosts" || !hosts.Required || hosts.Updatable || hosts.Default != nil {
+		t.Errorf("wrong field %+v", hosts)
+	}
+	if err := hosts.Set("db1;db2"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg.Database.Hosts, []string{"db1", "db2"}) {
+		t.Errorf("wrong hosts %v", cfg.Database.Hosts)
+	}
+
+	labels := fields[0]
+	if !labels.IsZero() || labels.Default == nil || !labels.Updatable {
+		t.Errorf("wrong field %+v", labels)
+	}
+	if err := labels.Set(*labels.Default); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg.Labels, map[string]int{"a": 1, "b": 2}) || labels.Value().Len() != 2 {
+		t.Errorf("wrong labels %v", cfg.Labels)
+	}
+
+	if err := fields[1].Set("2022-10-01"); err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC); !cfg.Since.Equal(want) {
+		t.Errorf("wrong time %v, want %v", cfg.Since, want)
+	}
+}
@@ -2,4 +2,8 @@ module github.com/openware/pkg/ika
 
 go 1.18
 
-require gopkg.in/yaml.v3 v3.0.1
+require (
+	github.com/BurntSushi/toml v1.2.1
+	github.com/joho/godotenv v1.4.0
+	gopkg.in/yaml.v3 v3.0.1
+)
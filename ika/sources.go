@@ -0,0 +1,261 @@
+package ika
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Source is a layer of configuration read by ReadSources
+type Source interface {
+	// Name identifies the source in the origins of the fields, e.g. "file config.yml"
+	Name() string
+	// Load sets the fields of the structure given by the source
+	Load(cfg interface{}) error
+}
+
+// VaultReader reads the entries of an application scope, it is implemented by vault.Service
+type VaultReader interface {
+	Read(appName, scope string) error
+	GetEntries(appName, scope string) (map[string]interface{}, error)
+}
+
+// SecretReader reads the data of a Kubernetes Secret, it is implemented by kube.K8sClient
+type SecretReader interface {
+	ReadSecret(name, namespace string) (map[string][]byte, error)
+}
+
+// Origins maps the path of the fields, e.g. Database.Host, to the name of the source of their value
+type Origins map[string]string
+
+// String returns the fields and the source of their value, one per line sorted by field
+func (o Origins) String() string {
+	fields := make([]string, 0, len(o))
+	for field := range o {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var b strings.Builder
+	for _, field := range fields {
+		fmt.Fprintf(&b, "%s: %s\n", field, o[field])
+	}
+	return b.String()
+}
+
+// OriginDefault is the origin of the fields set to their env-default value
+const OriginDefault = "default"
+
+// ReadSources reads the configuration from the sources in order, each one overriding the fields set by the previous ones,
+// then sets the fields still empty to their default and calls the Update function of the structure.
+// It returns the source of the value of each field, a field is attributed to the last source changing its value.
+//
+// Example:
+//
+//	origins, err := ika.ReadSources(&cfg,
+//		ika.File("config/base.yml"),
+//		ika.File("config/production.yml"),
+//		ika.Vault(vaultService, "peatio", "secret"),
+//		ika.Env(),
+//		ika.Flags(flag.CommandLine),
+//	)
+//	if err != nil {
+//		...
+//	}
+//	log.Printf("database host from %s", origins["Database.Host"])
+func ReadSources(cfg interface{}, sources ...Source) (Origins, error) {
+	metaInfo, err := readStructMetadata(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	origins := make(Origins)
+	for _, source := range sources {
+		previous := make([]interface{}, len(metaInfo))
+		for i, meta := range metaInfo {
+			previous[i] = snapshot(meta.fieldValue)
+		}
+
+		if err := source.Load(cfg); err != nil {
+			return origins, fmt.Errorf("%s: %w", source.Name(), err)
+		}
+
+		for i, meta := range metaInfo {
+			if !reflect.DeepEqual(previous[i], meta.fieldValue.Interface()) {
+				origins[meta.fieldPath] = source.Name()
+			}
+		}
+	}
+
+	for _, meta := range metaInfo {
+		if !meta.isFieldValueZero() {
+			continue
+		}
+		if meta.required {
+			return origins, fmt.Errorf("field %q is required but the value is not provided", meta.fieldName)
+		}
+		if meta.defValue == nil {
+			continue
+		}
+		if err := parseValue(meta.fieldValue, *meta.defValue, meta.separator, meta.layout); err != nil {
+			return origins, err
+		}
+		origins[meta.fieldPath] = OriginDefault
+	}
+
+	if updater, ok := cfg.(Updater); ok {
+		if err := updater.Update(); err != nil {
+			return origins, err
+		}
+	}
+
+	return origins, nil
+}
+
+// snapshot returns a copy of the value of a field, so that decoders updating maps and slices in place don't change it
+func snapshot(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			return v.Interface()
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return c.Interface()
+	case reflect.Slice:
+		if v.IsNil() {
+			return v.Interface()
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(c, v)
+		return c.Interface()
+	default:
+		return v.Interface()
+	}
+}
+
+// sourceFunc is a source loaded by a function
+type sourceFunc struct {
+	name string
+	load func(cfg interface{}) error
+}
+
+func (s sourceFunc) Name() string {
+	return s.name
+}
+
+func (s sourceFunc) Load(cfg interface{}) error {
+	return s.load(cfg)
+}
+
+// NewSource creates a source loaded by a function, e.g. to read a remote configuration
+func NewSource(name string, load func(cfg interface{}) error) Source {
+	return sourceFunc{name: name, load: load}
+}
+
+// File reads a configuration file in one of the supported formats, it is skipped if it doesn't exist
+func File(path string) Source {
+	return NewSource("file "+path, func(cfg interface{}) error {
+		return parseFile(path, cfg)
+	})
+}
+
+// Env reads the environment variables of the env tags, without their default
+func Env() Source {
+	return NewSource("env", func(cfg interface{}) error {
+		vars := make(map[string]string)
+		for _, kv := range os.Environ() {
+			if i := strings.Index(kv, "="); i > 0 {
+				vars[kv[:i]] = kv[i+1:]
+			}
+		}
+		return setVars(cfg, vars)
+	})
+}
+
+// Flags reads the flags given on the command line, the flag set must be parsed.
+// Flags are named after the keys of the fields in the configuration files, nested keys joined
+// and underscores replaced with dashes, e.g. -database-host for the host key of the database section
+func Flags(fs *flag.FlagSet) Source {
+	return NewSource("flags", func(cfg interface{}) error {
+		metaInfo, err := readStructMetadata(cfg)
+		if err != nil {
+			return err
+		}
+
+		fields := make(map[string]structMeta, len(metaInfo))
+		for _, meta := range metaInfo {
+			fields[strings.NewReplacer(".", "-", "_", "-").Replace(meta.fieldKey)] = meta
+		}
+
+		fs.Visit(func(f *flag.Flag) {
+			meta, ok := fields[f.Name]
+			if !ok || err != nil {
+				return
+			}
+			if e := parseValue(meta.fieldValue, f.Value.String(), meta.separator, meta.layout); e != nil {
+				err = fmt.Errorf("invalid value %q for flag -%s: %w", f.Value.String(), f.Name, e)
+			}
+		})
+		return err
+	})
+}
+
+// Vault reads the entries of an application scope from Vault, named after the env tags in lower case
+func Vault(reader VaultReader, appName, scope string) Source {
+	return NewSource("vault "+appName+"/"+scope, func(cfg interface{}) error {
+		if err := reader.Read(appName, scope); err != nil {
+			return err
+		}
+		entries, err := reader.GetEntries(appName, scope)
+		if err != nil {
+			return err
+		}
+
+		vars := make(map[string]string, len(entries))
+		for name, value := range entries {
+			vars[strings.ToUpper(name)] = entryValue(value)
+		}
+		return setVars(cfg, vars)
+	})
+}
+
+// Secret reads the data of a Kubernetes Secret, its keys are the env tags like the variables it exports to the pods
+func Secret(reader SecretReader, namespace, name string) Source {
+	return NewSource("secret "+namespace+"/"+name, func(cfg interface{}) error {
+		data, err := reader.ReadSecret(name, namespace)
+		if err != nil {
+			return err
+		}
+
+		vars := make(map[string]string, len(data))
+		for key, value := range data {
+			vars[key] = string(value)
+		}
+		return setVars(cfg, vars)
+	})
+}
+
+// entryValue formats a Vault entry like an environment variable, lists are joined with the default separator
+func entryValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = entryValue(item)
+		}
+		return strings.Join(items, DefaultSeparator)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,135 @@
+package ika
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type layeredConfig struct {
+	Port     int               `yaml:"port" env:"LAYERED_PORT" env-default:"8080"`
+	LogLevel string            `yaml:"log_level" env:"LAYERED_LOG_LEVEL" env-default:"info"`
+	Labels   map[string]string `yaml:"labels"`
+	Database struct {
+		Host     string   `yaml:"host" env:"LAYERED_DATABASE_HOST"`
+		Password string   `yaml:"password" env:"DATABASE_PASS"`
+		Replicas []string `yaml:"replicas" env:"DATABASE_REPLICAS"`
+	} `yaml:"database"`
+	Token string `env:"TOKEN" env-required:"true"`
+}
+
+type fakeVault struct {
+	entries map[string]interface{}
+	err     error
+}
+
+func (v *fakeVault) Read(appName, scope string) error {
+	if appName != "peatio" || scope != "secret" {
+		return errors.New("unexpected scope")
+	}
+	return v.err
+}
+
+func (v *fakeVault) GetEntries(appName, scope string) (map[string]interface{}, error) {
+	return v.entries, nil
+}
+
+type fakeSecrets map[string][]byte
+
+func (s fakeSecrets) ReadSecret(name, namespace string) (map[string][]byte, error) {
+	if name != "peatio" || namespace != "odax" {
+		return nil, errors.New("secret not found")
+	}
+	return s, nil
+}
+
+func TestReadSources(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yml")
+	overlay := filepath.Join(dir, "production.yml")
+	if err := os.WriteFile(base, []byte("port: 3000\nlabels:\n  app: peatio\ndatabase:\n  host: localhost\n  password: changeme\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(overlay, []byte("labels:\n  env: production\ndatabase:\n  host: db.production\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	vault := &fakeVault{entries: map[string]interface{}{"database_pass": "s3cr3t", "database_replicas": []interface{}{"r1", "r2"}}}
+	secrets := fakeSecrets{"TOKEN": []byte("jwt")}
+	t.Setenv("LAYERED_DATABASE_HOST", "db.override")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("port", 0, "Port")
+	fs.String("database-host", "", "Database host")
+	if err := fs.Parse([]string{"-port", "4000"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &layeredConfig{}
+	origins, err := ReadSources(cfg,
+		File(base),
+		File(overlay),
+		File(filepath.Join(dir, "missing.yml")),
+		Vault(vault, "peatio", "secret"),
+		Secret(secrets, "odax", "peatio"),
+		Env(),
+		Flags(fs),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Port != 4000 || cfg.LogLevel != "info" || cfg.Database.Host != "db.override" || cfg.Database.Password != "s3cr3t" || cfg.Token != "jwt" {
+		t.Errorf("wrong data %+v", cfg)
+	}
+	if !reflect.DeepEqual(cfg.Labels, map[string]string{"app": "peatio", "env": "production"}) || !reflect.DeepEqual(cfg.Database.Replicas, []string{"r1", "r2"}) {
+		t.Errorf("wrong data %+v", cfg)
+	}
+
+	want := Origins{
+		"Port":              "flags",
+		"LogLevel":          OriginDefault,
+		"Labels":            "file " + overlay,
+		"Database.Host":     "env",
+		"Database.Password": "vault peatio/secret",
+		"Database.Replicas": "vault peatio/secret",
+		"Token":             "secret odax/peatio",
+	}
+	if !reflect.DeepEqual(origins, want) {
+		t.Errorf("wrong origins %v, want %v", origins, want)
+	}
+	if s := (Origins{"Token": "env", "Port": "flags"}).String(); s != "Port: flags\nToken: env\n" {
+		t.Errorf("wrong string %q", s)
+	}
+}
+
+func TestReadSourcesErrors(t *testing.T) {
+	cfg := &layeredConfig{}
+	_, err := ReadSources(cfg, Vault(&fakeVault{err: errors.New("permission denied")}, "peatio", "secret"))
+	if err == nil || err.Error() != "vault peatio/secret: permission denied" {
+		t.Errorf("wrong error %v", err)
+	}
+
+	_, err = ReadSources(cfg, Secret(fakeSecrets{}, "odax", "barong"))
+	if err == nil || err.Error() != "secret odax/barong: secret not found" {
+		t.Errorf("wrong error %v", err)
+	}
+
+	_, err = ReadSources(cfg, NewSource("static", func(cfg interface{}) error { return nil }))
+	if err == nil || err.Error() != `field "Token" is required but the value is not provided` {
+		t.Errorf("wrong error %v", err)
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("port", "", "Port")
+	if err := fs.Parse([]string{"-port", "http"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = ReadSources(cfg, Flags(fs))
+	if err == nil || err.Error() != `flags: invalid value "http" for flag -port: strconv.ParseInt: parsing "http": invalid syntax` {
+		t.Errorf("wrong error %v", err)
+	}
+}
@@ -0,0 +1,295 @@
+package ika
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultWatchInterval is the default interval between two checks of the watched files
+const DefaultWatchInterval = 5 * time.Second
+
+// Validator is an interface for a configuration checked when it is reloaded,
+// the reload is rolled back if Validate returns an error
+type Validator interface {
+	Validate() error
+}
+
+// Change is an updatable field changed by a reload
+type Change struct {
+	// Field is the path of the field, e.g. Database.Host
+	Field string
+	From  interface{}
+	To    interface{}
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %v -> %v", c.Field, c.From, c.To)
+}
+
+// Watcher reloads the fields of a configuration marked as updatable with the env-upd tag
+// when its configuration file, or the files of a directory, change.
+//
+// Example:
+//
+//	var cfg Config
+//	if err := ika.ReadConfig("config.yml", &cfg); err != nil {
+//		...
+//	}
+//
+//	watcher := ika.NewWatcher("config.yml", &cfg).Dir("config.d")
+//	watcher.Subscribe(func(changes []ika.Change) {
+//		log.Printf("configuration reloaded: %v", changes)
+//	})
+//	go watcher.Run(ctx)
+//
+//	watcher.RLock()
+//	level := cfg.LogLevel
+//	watcher.RUnlock()
+type Watcher struct {
+	path        string
+	dir         string
+	cfg         interface{}
+	interval    time.Duration
+	subscribers []func([]Change)
+	onError     func(error)
+	// mu guards the updatable fields of the configuration
+	mu sync.RWMutex
+	// reloading serializes the reloads
+	reloading sync.Mutex
+}
+
+// fileStamp identifies a version of a watched file
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewWatcher creates a watcher of the configuration file read into cfg, e.g. by ReadConfig
+func NewWatcher(path string, cfg interface{}) *Watcher {
+	return &Watcher{
+		path:     path,
+		cfg:      cfg,
+		interval: DefaultWatchInterval,
+	}
+}
+
+// Dir watches the files of a directory too, they are parsed in the order of their names after the configuration file
+func (w *Watcher) Dir(dir string) *Watcher {
+	w.dir = dir
+	return w
+}
+
+// Interval sets the interval between two checks of the watched files
+func (w *Watcher) Interval(interval time.Duration) *Watcher {
+	w.interval = interval
+	return w
+}
+
+// Subscribe registers a function called with the changed fields after each reload changing them
+func (w *Watcher) Subscribe(fn func([]Change)) *Watcher {
+	w.subscribers = append(w.subscribers, fn)
+	return w
+}
+
+// OnError registers a function called with the errors of the reloads triggered by Run
+func (w *Watcher) OnError(fn func(error)) *Watcher {
+	w.onError = fn
+	return w
+}
+
+// RLock locks the configuration for reading, so that a reload doesn't change it meanwhile
+func (w *Watcher) RLock() {
+	w.mu.RLock()
+}
+
+// RUnlock undoes a RLock call
+func (w *Watcher) RUnlock() {
+	w.mu.RUnlock()
+}
+
+// Run checks the watched files at every interval and reloads the configuration when they change,
+// until the context is done
+func (w *Watcher) Run(ctx context.Context) error {
+	stamps := w.stamps()
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			current := w.stamps()
+			if reflect.DeepEqual(current, stamps) {
+				continue
+			}
+			stamps = current
+
+			if _, err := w.Reload(); err != nil && w.onError != nil {
+				w.onError(err)
+			}
+		}
+	}
+}
+
+// Reload reads the configuration files and the environment variables again,
+// and applies the updatable fields at once.
+// The Validate and Update functions of the configuration are then called with the lock held,
+// and the fields restored if one of them returns an error. Otherwise the subscribers are notified of the changes.
+func (w *Watcher) Reload() ([]Change, error) {
+	w.reloading.Lock()
+	defer w.reloading.Unlock()
+
+	current := reflect.ValueOf(w.cfg)
+	if current.Kind() != reflect.Ptr || current.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("wrong type %v", current.Kind())
+	}
+
+	// read the whole configuration into a new structure
+	fresh := reflect.New(current.Elem().Type())
+	if err := w.parseFiles(fresh.Interface()); err != nil {
+		return nil, err
+	}
+	freshMeta, err := readStructMetadata(fresh.Interface())
+	if err != nil {
+		return nil, err
+	}
+	if err := setEnvVars(freshMeta, false); err != nil {
+		return nil, err
+	}
+
+	currentMeta, err := readStructMetadata(w.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// both structures have the same type, so their fields have the same order
+	var changes []Change
+	var fields []int
+	for i, meta := range freshMeta {
+		if !meta.updatable {
+			continue
+		}
+		from, to := currentMeta[i].fieldValue.Interface(), meta.fieldValue.Interface()
+		if reflect.DeepEqual(from, to) {
+			continue
+		}
+		changes = append(changes, Change{Field: meta.fieldPath, From: from, To: to})
+		fields = append(fields, i)
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	if err := w.apply(currentMeta, freshMeta, fields); err != nil {
+		return nil, err
+	}
+	for _, fn := range w.subscribers {
+		fn(changes)
+	}
+
+	return changes, nil
+}
+
+// apply sets the fields of the configuration to their new value, and restores them on error
+func (w *Watcher) apply(currentMeta, freshMeta []structMeta, fields []int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	previous := make([]reflect.Value, len(fields))
+	for j, i := range fields {
+		previous[j] = reflect.New(currentMeta[i].fieldValue.Type()).Elem()
+		previous[j].Set(currentMeta[i].fieldValue)
+		currentMeta[i].fieldValue.Set(freshMeta[i].fieldValue)
+	}
+
+	err := w.check()
+	if err != nil {
+		for j, i := range fields {
+			currentMeta[i].fieldValue.Set(previous[j])
+		}
+		return fmt.Errorf("configuration reload rolled back: %w", err)
+	}
+
+	return nil
+}
+
+// check validates and updates the configuration
+func (w *Watcher) check() error {
+	if validator, ok := w.cfg.(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return err
+		}
+	}
+	if updater, ok := w.cfg.(Updater); ok {
+		if err := updater.Update(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseFiles parses the configuration file then the files of the directory
+func (w *Watcher) parseFiles(cfg interface{}) error {
+	if w.path != "" {
+		if err := parseFile(w.path, cfg); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range w.dirFiles() {
+		if err := parseFile(path, cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dirFiles returns the files of the directory in a supported format, sorted by name.
+// Hidden files are skipped, like the ..data link of the Kubernetes volumes
+func (w *Watcher) dirFiles() []string {
+	if w.dir == "" {
+		return nil
+	}
+
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return nil
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || parserOf(strings.ToLower(filepath.Ext(name))) == nil {
+			continue
+		}
+		paths = append(paths, filepath.Join(w.dir, name))
+	}
+
+	return paths
+}
+
+// stamps returns the versions of the watched files, following symbolic links
+func (w *Watcher) stamps() map[string]fileStamp {
+	stamps := make(map[string]fileStamp)
+
+	paths := w.dirFiles()
+	if w.path != "" {
+		paths = append(paths, w.path)
+	}
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			stamps[path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+
+	return stamps
+}
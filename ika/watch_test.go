@@ -0,0 +1,175 @@
+package ika
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type watchedConfig struct {
+	Port     int    `yaml:"port" env-default:"8080"`
+	LogLevel string `yaml:"log_level" env-upd:"true" env-default:"info"`
+	Database struct {
+		Host string `yaml:"host"`
+		Pool int    `yaml:"pool" env-upd:"true"`
+	} `yaml:"database"`
+	updates int
+}
+
+func (c *watchedConfig) Validate() error {
+	if c.Database.Pool < 0 {
+		return errors.New("negative pool")
+	}
+	return nil
+}
+
+func (c *watchedConfig) Update() error {
+	c.updates++
+	return nil
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcherReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	writeFile(t, path, "port: 3000\nlog_level: info\ndatabase:\n  host: db\n  pool: 5\n")
+
+	cfg := &watchedConfig{}
+	if err := ReadConfig(path, cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg.updates = 0
+
+	var notified [][]Change
+	watcher := NewWatcher(path, cfg).Subscribe(func(changes []Change) {
+		notified = append(notified, changes)
+	})
+
+	changes, err := watcher.Reload()
+	if err != nil || changes != nil {
+		t.Fatalf("unexpected changes %v, %v", changes, err)
+	}
+
+	writeFile(t, path, "port: 4000\nlog_level: debug\ndatabase:\n  host: other\n  pool: 10\n")
+	changes, err = watcher.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Change{
+		{Field: "LogLevel", From: "info", To: "debug"},
+		{Field: "Database.Pool", From: 5, To: 10},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("unexpected changes %v", changes)
+	}
+	if !reflect.DeepEqual(notified, [][]Change{expected}) {
+		t.Errorf("unexpected notifications %v", notified)
+	}
+	if changes[1].String() != "Database.Pool: 5 -> 10" {
+		t.Errorf("unexpected change %s", changes[1])
+	}
+
+	// Fields without the env-upd tag are kept
+	if cfg.Port != 3000 || cfg.Database.Host != "db" || cfg.LogLevel != "debug" || cfg.Database.Pool != 10 || cfg.updates != 1 {
+		t.Errorf("unexpected configuration %+v", cfg)
+	}
+
+	// Invalid configurations are rolled back
+	writeFile(t, path, "port: 4000\nlog_level: warn\ndatabase:\n  host: other\n  pool: -1\n")
+	_, err = watcher.Reload()
+	if err == nil || err.Error() != "configuration reload rolled back: negative pool" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if cfg.LogLevel != "debug" || cfg.Database.Pool != 10 || cfg.updates != 1 || len(notified) != 1 {
+		t.Errorf("configuration not rolled back %+v", cfg)
+	}
+
+	// Parsing errors are returned
+	writeFile(t, path, "port: [")
+	if _, err := watcher.Reload(); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestWatcherDir(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "config.yml")
+	dir := filepath.Join(root, "config.d")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, path, "log_level: info\ndatabase:\n  pool: 5\n")
+	writeFile(t, filepath.Join(dir, "10-pool.yml"), "database:\n  pool: 20\n")
+	writeFile(t, filepath.Join(dir, "20-log.json"), `{"LogLevel": "error"}`)
+	writeFile(t, filepath.Join(dir, "README.md"), "ignored")
+	writeFile(t, filepath.Join(dir, ".hidden.yml"), "log_level: ignored")
+
+	cfg := &watchedConfig{}
+	if err := ReadConfig(path, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	watcher := NewWatcher(path, cfg).Dir(dir)
+	if _, err := watcher.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.LogLevel != "error" || cfg.Database.Pool != 20 {
+		t.Errorf("unexpected configuration %+v", cfg)
+	}
+}
+
+func TestWatcherRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	writeFile(t, path, "log_level: info\n")
+
+	cfg := &watchedConfig{}
+	if err := ReadConfig(path, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	notified := make(chan []Change, 1)
+	errs := make(chan error, 1)
+	watcher := NewWatcher(path, cfg).
+		Interval(10 * time.Millisecond).
+		Subscribe(func(changes []Change) { notified <- changes }).
+		OnError(func(err error) { errs <- err })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- watcher.Run(ctx) }()
+
+	time.Sleep(30 * time.Millisecond)
+	writeFile(t, path, "log_level: debug\n")
+
+	select {
+	case changes := <-notified:
+		if !reflect.DeepEqual(changes, []Change{{Field: "LogLevel", From: "info", To: "debug"}}) {
+			t.Errorf("unexpected changes %v", changes)
+		}
+	case err := <-errs:
+		t.Fatal(err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("configuration not reloaded")
+	}
+
+	watcher.RLock()
+	level := cfg.LogLevel
+	watcher.RUnlock()
+	if level != "debug" {
+		t.Errorf("unexpected log level %s", level)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package kli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Exit codes returned by ExitCode
+const (
+	ExitFailure     = 1
+	ExitUsage       = 2
+	ExitInterrupted = 130
+)
+
+// Context is passed to the context actions and hooks, it is cancelled on SIGINT or SIGTERM
+type Context struct {
+	context.Context
+	// Command is the command invoked
+	Command *Command
+	// Args are the other arguments given to the command
+	Args []string
+}
+
+// ContextAction represents a function that gets called with the invocation context when the command is executed
+type ContextAction func(ctx *Context) error
+
+// ExitError terminates the application with an exit code when passed to Abort
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// Exit returns an error terminating the application with the exit code
+func Exit(code int, err error) error {
+	return &ExitError{Code: code, Err: err}
+}
+
+// Exitf returns an error formatted according to a format specifier terminating the application with the exit code
+func Exitf(code int, format string, a ...interface{}) error {
+	return Exit(code, fmt.Errorf(format, a...))
+}
+
+// ExitCode returns the exit code of an error returned by Run: 0 without error, the code of an ExitError,
+// ExitInterrupted if the context was cancelled, ExitUsage for invalid flags or arguments and ExitFailure otherwise
+func ExitCode(err error) int {
+	var exitError *ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitError):
+		return exitError.Code
+	case errors.Is(err, context.Canceled):
+		return ExitInterrupted
+	default:
+		return ExitFailure
+	}
+}
+
+// usageError wraps an error caused by invalid flags or arguments
+func usageError(err error) error {
+	return Exit(ExitUsage, err)
+}
+
+// ContextAction - Define an action receiving the invocation context from this command
+func (c *Command) ContextAction(callback ContextAction) *Command {
+	c.contextAction = callback
+	c.actionCallback = nil
+	return c
+}
+
+// Before - Adds a hook called before the action of this command or of its subcommands,
+// the hooks of the parent commands are called first and an error prevents the action
+func (c *Command) Before(hook ContextAction) *Command {
+	c.beforeHooks = append(c.beforeHooks, hook)
+	return c
+}
+
+// After - Adds a hook called after the action of this command or of its subcommands succeeded,
+// the hooks of the subcommands are called first
+func (c *Command) After(hook ContextAction) *Command {
+	c.afterHooks = append(c.afterHooks, hook)
+	return c
+}
+
+// runAction runs the action of the command surrounded by the hooks of the command and its parents
+func (c *Command) runAction(ctx *Context) error {
+	var path []*Command
+	for command := c; command != nil; command = command.parent {
+		path = append([]*Command{command}, path...)
+	}
+
+	for _, command := range path {
+		for _, hook := range command.beforeHooks {
+			if err := hook(ctx); err != nil {
+				return err
+			}
+		}
+	}
+
+	var err error
+	if c.contextAction != nil {
+		err = c.contextAction(ctx)
+	} else {
+		err = c.actionCallback()
+	}
+	if err != nil {
+		return err
+	}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		for _, hook := range path[i].afterHooks {
+			if err := hook(ctx); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ContextAction - Define an action receiving the invocation context from the root command
+func (c *Cli) ContextAction(callback ContextAction) *Cli {
+	c.rootCommand.ContextAction(callback)
+	return c
+}
+
+// Before - Adds a hook called before the action of any command
+func (c *Cli) Before(hook ContextAction) *Cli {
+	c.rootCommand.Before(hook)
+	return c
+}
+
+// After - Adds a hook called after the action of any command succeeded
+func (c *Cli) After(hook ContextAction) *Cli {
+	c.rootCommand.After(hook)
+	return c
+}
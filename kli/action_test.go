@@ -0,0 +1,124 @@
+package kli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestContextAction(t *testing.T) {
+	var calls []string
+	hook := func(name string, err error) ContextAction {
+		return func(ctx *Context) error {
+			calls = append(calls, name)
+			return err
+		}
+	}
+
+	cli := NewCli("test", "description", "0")
+	cli.Before(hook("root before", nil)).After(hook("root after", nil))
+	apps := cli.NewSubCommand("apps", "Applications").
+		Before(hook("apps before", nil)).
+		After(hook("apps after", nil))
+	show := apps.NewSubCommand("show", "Show an application").Arg("app", "Application", 1)
+
+	var invoked *Context
+	show.ContextAction(func(ctx *Context) error {
+		invoked = ctx
+		calls = append(calls, "action")
+		return nil
+	})
+
+	if err := cli.Run("apps", "show", "peatio"); err != nil {
+		t.Fatal(err)
+	}
+	if invoked.Command != show || !reflect.DeepEqual(invoked.Args, []string{"peatio"}) {
+		t.Errorf("unexpected context %+v", invoked)
+	}
+	expected := []string{"root before", "apps before", "action", "apps after", "root after"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("unexpected calls %v", calls)
+	}
+
+	calls = nil
+	show.Before(hook("show before", errors.New("not logged in")))
+	err := cli.Run("apps", "show", "peatio")
+	if err == nil || err.Error() != "not logged in" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(calls, []string{"root before", "apps before", "show before"}) {
+		t.Errorf("unexpected calls %v", calls)
+	}
+}
+
+func TestContextCancellation(t *testing.T) {
+	cli := NewCli("test", "description", "0")
+	cli.ContextAction(func(ctx *Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+			return errors.New("not cancelled")
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := cli.RunContext(ctx, "-help=false")
+	if ExitCode(err) != ExitInterrupted {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	// Run cancels the context on interrupt
+	cli.ContextAction(func(ctx *Context) error {
+		p, err := os.FindProcess(os.Getpid())
+		if err != nil {
+			return err
+		}
+		if err := p.Signal(os.Interrupt); err != nil {
+			t.Skip("interrupt signal not supported")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+			return errors.New("not cancelled")
+		}
+	})
+	if err := cli.Run("-help=false"); ExitCode(err) != ExitInterrupted {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	cli := NewCli("test", "description", "0")
+	cli.NewSubCommand("fail", "Fail").Action(func() error {
+		return Exitf(3, "failed with %s", "code")
+	})
+	cli.NewSubCommand("args", "Arguments").Arg("name", "Name", 1).Action(func() error { return nil })
+
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{nil, 0},
+		{errors.New("failure"), ExitFailure},
+		{cli.Run("-unknown"), ExitUsage},
+		{cli.Run("args"), ExitUsage},
+		{cli.Run("fail"), 3},
+	}
+	for _, test := range tests {
+		if code := ExitCode(test.err); code != test.code {
+			t.Errorf("ExitCode(%v) = %d, expected %d", test.err, code, test.code)
+		}
+	}
+
+	err := cli.Run("fail")
+	if err.Error() != "failed with code" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
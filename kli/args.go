@@ -0,0 +1,98 @@
+package kli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Counts of positional arguments accepted by Arg besides an exact count
+const (
+	// OptionalArg is an argument that may be omitted
+	OptionalArg = 0
+	// VariadicArg is an argument given any number of times, including none
+	VariadicArg = -1
+)
+
+// argSpec describes a positional argument
+type argSpec struct {
+	name        string
+	description string
+	count       int
+}
+
+// usage returns the argument as shown in the usage line
+func (a argSpec) usage() string {
+	switch {
+	case a.count == OptionalArg:
+		return "[" + a.name + "]"
+	case a.count == VariadicArg:
+		return "[" + a.name + "...]"
+	case a.count > 1:
+		return strings.TrimSpace(strings.Repeat("<"+a.name+"> ", a.count))
+	default:
+		return "<" + a.name + ">"
+	}
+}
+
+// Arg - Declares the next positional argument of the command, given count times, or OptionalArg or VariadicArg.
+// The other arguments are then validated before running the action and documented in the help.
+// Only the last argument may be optional or variadic.
+func (c *Command) Arg(name, description string, count int) *Command {
+	if n := len(c.args); n > 0 && c.args[n-1].count <= OptionalArg {
+		panic(fmt.Sprintf("kli: argument %s declared after the optional argument %s", name, c.args[n-1].name))
+	}
+	if count < VariadicArg {
+		panic(fmt.Sprintf("kli: invalid count %d of argument %s", count, name))
+	}
+
+	c.args = append(c.args, argSpec{name: name, description: description, count: count})
+	if len(name) > c.longestArg {
+		c.longestArg = len(name)
+	}
+	return c
+}
+
+// Arg - Declares the next positional argument of the root command
+func (c *Cli) Arg(name, description string, count int) *Cli {
+	c.rootCommand.Arg(name, description, count)
+	return c
+}
+
+// checkArgs validates the other arguments against the declared ones
+func (c *Command) checkArgs(args []string) error {
+	if len(c.args) == 0 {
+		return nil
+	}
+
+	remaining := len(args)
+	for _, spec := range c.args {
+		switch {
+		case spec.count == VariadicArg:
+			return nil
+		case spec.count == OptionalArg:
+			remaining--
+		case remaining < spec.count:
+			return fmt.Errorf("Missing argument <%s>", spec.name)
+		default:
+			remaining -= spec.count
+		}
+	}
+
+	if remaining > 0 {
+		return fmt.Errorf("Too many arguments, unexpected %q", args[len(args)-remaining:])
+	}
+	return nil
+}
+
+// usageLine returns the command usage, e.g. "app deploy [flags] <service> [version]"
+func (c *Command) usageLine() string {
+	parts := []string{c.commandPath}
+	if c.flagCount > 0 {
+		parts = append(parts, "[flags]")
+	}
+	for _, spec := range c.args {
+		parts = append(parts, spec.usage())
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,70 @@
+package kli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestArgs(t *testing.T) {
+	var received []string
+	cli := NewCli("test", "description", "0")
+	deploy := cli.NewSubCommand("deploy", "Deploy a service").
+		Arg("service", "Service to deploy", 1).
+		Arg("version", "Version, latest by default", OptionalArg)
+	deploy.Action(func() error {
+		received = deploy.OtherArgs()
+		return nil
+	})
+
+	if err := cli.Run("deploy", "peatio", "3.0"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(received, []string{"peatio", "3.0"}) {
+		t.Errorf("unexpected arguments %v", received)
+	}
+	if err := cli.Run("deploy", "peatio"); err != nil {
+		t.Fatal(err)
+	}
+
+	err := cli.Run("deploy")
+	if err == nil || err.Error() != "Missing argument <service>" {
+		t.Errorf("unexpected error %v", err)
+	}
+	err = cli.Run("deploy", "peatio", "3.0", "now")
+	if err == nil || err.Error() != `Too many arguments, unexpected ["now"]` {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if usage := deploy.usageLine(); usage != "test deploy [flags] <service> [version]" {
+		t.Errorf("unexpected usage %q", usage)
+	}
+}
+
+func TestArgsCounts(t *testing.T) {
+	c := NewCli("test", "description", "0").NewSubCommand("copy", "Copy files").
+		Arg("source", "Source and destination", 2).
+		Arg("files", "Files to copy", VariadicArg)
+
+	for _, test := range []struct {
+		args  []string
+		valid bool
+	}{
+		{[]string{"a"}, false},
+		{[]string{"a", "b"}, true},
+		{[]string{"a", "b", "c", "d"}, true},
+	} {
+		if err := c.checkArgs(test.args); (err == nil) != test.valid {
+			t.Errorf("checkArgs(%v) = %v", test.args, err)
+		}
+	}
+	if usage := c.usageLine(); usage != "test copy [flags] <source> <source> [files...]" {
+		t.Errorf("unexpected usage %q", usage)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic declaring an argument after a variadic one")
+		}
+	}()
+	c.Arg("other", "Other", 1)
+}
@@ -0,0 +1,292 @@
+package kli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/openware/pkg/ika"
+
+	"gopkg.in/yaml.v3"
+)
+
+// binding is the environment variables and config file key a flag falls back to
+type binding struct {
+	envs []string
+	key  string
+	// separator joins the items of the config file lists, and of its maps as ika key:value pairs,
+	// set for the fields bound by BindConfig only
+	separator string
+}
+
+// FlagEnv binds environment variables to a flag, the first one set is used when the flag is not given
+func (c *Command) FlagEnv(name string, envs ...string) *Command {
+	b := c.binding(name)
+	b.envs = append(b.envs, envs...)
+	return c
+}
+
+// FlagConfig binds a key of the config file to a flag, e.g. database.host,
+// used when the flag is not given nor set in the environment
+func (c *Command) FlagConfig(name, key string) *Command {
+	c.binding(name).key = key
+	return c
+}
+
+func (c *Command) binding(name string) *binding {
+	if c.bindings == nil {
+		c.bindings = make(map[string]*binding)
+	}
+	b, ok := c.bindings[name]
+	if !ok {
+		b = &binding{}
+		c.bindings[name] = b
+	}
+
+	return b
+}
+
+// BindConfig adds a flag for every field of an ika tagged struct, e.g.
+//
+//	type Config struct {
+//		Database struct {
+//			Host string `yaml:"host" env:"DATABASE_HOST" env-default:"localhost" env-description:"Database host"`
+//		} `yaml:"database"`
+//	}
+//
+// adds the -database-host flag falling back to DATABASE_HOST, then to the database.host key of the config file
+// and to localhost. Values are parsed by ika, so lists and maps are split with the env-separator of the field,
+// e.g. -labels a:1,b:2, and the flags are read back by ika.Flags.
+func (c *Command) BindConfig(cfg interface{}) error {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("BindConfig expects a pointer to a struct, got %T", cfg)
+	}
+
+	fields, err := ika.Fields(cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		// Fields with yaml:"-" are not in the config file
+		if strings.Contains("."+field.Key+".", ".-.") {
+			continue
+		}
+
+		if field.Default != nil && field.IsZero() {
+			if err := field.Set(*field.Default); err != nil {
+				return fmt.Errorf("Invalid default value of field %s: %w", field.Path, err)
+			}
+		}
+
+		name := strings.NewReplacer(".", "-", "_", "-").Replace(field.Key)
+		c.flags.Var(&fieldValue{field: field}, name, field.Description)
+		c.flagCount++
+
+		if field.Required {
+			c.Required(name)
+		}
+		if len(field.Envs) > 0 {
+			c.FlagEnv(name, field.Envs...)
+		}
+		c.FlagConfig(name, field.Key)
+		c.binding(name).separator = field.Separator
+	}
+
+	return nil
+}
+
+// fieldValue is a flag setting a struct field bound by BindConfig, parsed by ika
+type fieldValue struct {
+	field ika.Field
+	// raw is the value given, kept for ika.Flags to parse it again
+	raw *string
+}
+
+func (v *fieldValue) String() string {
+	if v.raw != nil {
+		return *v.raw
+	}
+	if !v.field.Value().IsValid() || v.field.IsZero() {
+		return ""
+	}
+	return fmt.Sprint(v.field.Value().Interface())
+}
+
+func (v *fieldValue) Set(s string) error {
+	if err := v.field.Set(s); err != nil {
+		return err
+	}
+	v.raw = &s
+	return nil
+}
+
+// IsBoolFlag allows boolean fields to be set without value, e.g. -debug
+func (v *fieldValue) IsBoolFlag() bool {
+	return v.field.Value().IsValid() && v.field.Value().Kind() == reflect.Bool
+}
+
+// resolveBindings sets the flags not given on the command line
+// from the environment, then from the config file
+func (c *Command) resolveBindings() error {
+	if len(c.bindings) == 0 {
+		return nil
+	}
+
+	given := make(map[string]bool)
+	c.flags.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+
+	for name, b := range c.bindings {
+		if given[name] {
+			continue
+		}
+
+		value, source, ok := lookupEnv(b.envs)
+		if !ok && b.key != "" && c.app != nil {
+			config, err := c.app.config()
+			if err != nil {
+				return err
+			}
+			value, ok = b.configValue(config[b.key])
+			source = "config key " + b.key
+		}
+		if !ok {
+			continue
+		}
+
+		if err := c.flags.Set(name, value); err != nil {
+			return fmt.Errorf("Invalid value %q for flag -%s from %s: %v", value, name, source, err)
+		}
+	}
+
+	return nil
+}
+
+func lookupEnv(envs []string) (string, string, bool) {
+	for _, env := range envs {
+		if value, ok := os.LookupEnv(env); ok {
+			return value, "environment variable " + env, true
+		}
+	}
+
+	return "", "", false
+}
+
+// ConfigFile sets the YAML or JSON config file the flags fall back to, a missing file is ignored
+func (c *Cli) ConfigFile(path string) *Cli {
+	c.configPath = &path
+	return c
+}
+
+// ConfigFileFlag adds a flag to the root command setting the config file the flags fall back to
+func (c *Cli) ConfigFileFlag(name, description, path string) *Cli {
+	c.configPath = &path
+	c.StringFlag(name, description, c.configPath)
+	return c
+}
+
+// FlagEnv binds environment variables to a flag of the root command
+func (c *Cli) FlagEnv(name string, envs ...string) *Cli {
+	c.rootCommand.FlagEnv(name, envs...)
+	return c
+}
+
+// FlagConfig binds a key of the config file to a flag of the root command
+func (c *Cli) FlagConfig(name, key string) *Cli {
+	c.rootCommand.FlagConfig(name, key)
+	return c
+}
+
+// BindConfig adds a flag to the root command for every field of an ika tagged struct
+func (c *Cli) BindConfig(cfg interface{}) error {
+	return c.rootCommand.BindConfig(cfg)
+}
+
+// config returns the values of the config file by key, nested keys are joined with a dot
+func (c *Cli) config() (map[string]interface{}, error) {
+	if c.configPath == nil || *c.configPath == "" {
+		return nil, nil
+	}
+	if c.configValues != nil && c.configLoaded == *c.configPath {
+		return c.configValues, nil
+	}
+
+	values := make(map[string]interface{})
+	data, err := os.ReadFile(*c.configPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("Failed to read config file: %w", err)
+	}
+
+	raw := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("Failed to parse config file %s: %w", *c.configPath, err)
+	}
+	flatten(values, "", raw)
+
+	c.configLoaded = *c.configPath
+	c.configValues = values
+	return values, nil
+}
+
+func flatten(values map[string]interface{}, prefix string, raw map[string]interface{}) {
+	for key, value := range raw {
+		values[prefix+key] = value
+		if v, ok := value.(map[string]interface{}); ok {
+			flatten(values, prefix+key+".", v)
+		}
+	}
+}
+
+// configValue formats a value of the config file as the flag value
+func (b *binding) configValue(value interface{}) (string, bool) {
+	separator := b.separator
+	if separator == "" {
+		separator = ","
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case map[string]interface{}:
+		if b.separator == "" {
+			return "", false
+		}
+		pairs := make([]string, 0, len(v))
+		for key, item := range v {
+			pairs = append(pairs, key+":"+fmt.Sprint(item))
+		}
+		sort.Strings(pairs)
+		return strings.Join(pairs, separator), true
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, separator), true
+	default:
+		return fmt.Sprint(v), true
+	}
+}
+
+// usage describes the environment variables and config keys of a flag in the help
+func (b *binding) usage() string {
+	var sources []string
+	if len(b.envs) > 0 {
+		sources = append(sources, "env "+strings.Join(b.envs, ", "))
+	}
+	if b.key != "" {
+		sources = append(sources, "config "+b.key)
+	}
+	if len(sources) == 0 {
+		return ""
+	}
+
+	return " [" + strings.Join(sources, "; ") + "]"
+}
@@ -0,0 +1,128 @@
+package kli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestFlagBindings(t *testing.T) {
+	path := writeConfig(t, "vault:\n  addr: http://file:8200\n  token: file-token\n  app: file-app\n")
+
+	run := func(args ...string) (string, string, string) {
+		addr, token, app := "http://default:8200", "default-token", "default-app"
+		cli := NewCli("test", "description", "0")
+		cli.ConfigFileFlag("config", "Config file", path)
+		cli.StringFlag("addr", "Vault address", &addr).FlagEnv("addr", "KLI_TEST_ADDR").FlagConfig("addr", "vault.addr")
+		cli.StringFlag("token", "Vault token", &token).FlagEnv("token", "KLI_TEST_TOKEN").FlagConfig("token", "vault.token")
+		cli.StringFlag("app", "Application", &app).FlagConfig("app", "vault.missing")
+		cli.Action(func() error { return nil })
+
+		if err := cli.Run(args...); err != nil {
+			t.Fatal(err)
+		}
+		return addr, token, app
+	}
+
+	addr, token, app := run("-addr", "http://flag:8200")
+	if addr != "http://flag:8200" || token != "file-token" || app != "default-app" {
+		t.Errorf("unexpected values %s, %s, %s", addr, token, app)
+	}
+
+	t.Setenv("KLI_TEST_ADDR", "http://env:8200")
+	addr, _, _ = run("-config", path)
+	if addr != "http://env:8200" {
+		t.Errorf("expected the environment to take precedence over the config file, got %s", addr)
+	}
+	addr, _, _ = run("-addr", "http://flag:8200")
+	if addr != "http://flag:8200" {
+		t.Errorf("expected the flag to take precedence over the environment, got %s", addr)
+	}
+
+	_, token, _ = run("-config", filepath.Join(t.TempDir(), "missing.yml"))
+	if token != "default-token" {
+		t.Errorf("expected the default value without config file, got %s", token)
+	}
+}
+
+func TestFlagBindingsInvalidValue(t *testing.T) {
+	port := 8080
+	cli := NewCli("test", "description", "0")
+	cli.IntFlag("port", "Port", &port).FlagEnv("port", "KLI_TEST_PORT")
+	cli.Action(func() error { return nil })
+
+	t.Setenv("KLI_TEST_PORT", "http")
+	err := cli.Run("-help=false")
+	expected := `Invalid value "http" for flag -port from environment variable KLI_TEST_PORT: parse error`
+	if err == nil || err.Error() != expected {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+type testConfig struct {
+	Database struct {
+		Host string `yaml:"host" env:"KLI_TEST_DATABASE_HOST" env-default:"localhost" env-description:"Database host"`
+		Port int    `yaml:"port" env-default:"5432"`
+	} `yaml:"database"`
+	LogLevel string        `yaml:"log_level" env:"KLI_TEST_LOG_LEVEL,LOG_LEVEL" env-default:"info"`
+	Timeout  time.Duration `yaml:"timeout" env-default:"5s"`
+	Debug    bool
+	Ignored  string `yaml:"-"`
+	internal string
+}
+
+func TestBindConfig(t *testing.T) {
+	path := writeConfig(t, "database:\n  port: 3306\nlog_level: warn\n")
+
+	cfg := &testConfig{}
+	cli := NewCli("test", "description", "0").ConfigFile(path)
+	if err := cli.BindConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	cli.Action(func() error { return nil })
+
+	for _, name := range []string{"database-host", "database-port", "log-level", "timeout", "debug"} {
+		if cli.rootCommand.flags.Lookup(name) == nil {
+			t.Errorf("flag %s is not defined", name)
+		}
+	}
+	if cli.rootCommand.flags.Lookup("ignored") != nil {
+		t.Error("field with yaml:\"-\" is bound")
+	}
+	if b := cli.rootCommand.bindings["log-level"]; b.usage() != " [env KLI_TEST_LOG_LEVEL, LOG_LEVEL; config log_level]" {
+		t.Errorf("unexpected usage %q", b.usage())
+	}
+
+	t.Setenv("LOG_LEVEL", "error")
+	if err := cli.Run("-debug", "-timeout", "1m"); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Database.Host != "localhost" || cfg.Database.Port != 3306 {
+		t.Errorf("unexpected database %+v", cfg.Database)
+	}
+	if cfg.LogLevel != "error" || cfg.Timeout != time.Minute || !cfg.Debug {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+
+	err := NewCli("test", "description", "0").BindConfig(&struct {
+		Ratio complex64 `env-default:"1"`
+	}{})
+	if err == nil || err.Error() != "Invalid default value of field Ratio: unsupported type .complex64" {
+		t.Errorf("unexpected error %v", err)
+	}
+	err = NewCli("test", "description", "0").BindConfig(testConfig{})
+	if err == nil || err.Error() != "BindConfig expects a pointer to a struct, got kli.testConfig" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -1,9 +1,13 @@
 package kli
 
 import (
+	"bufio"
+	"context"
 	"fmt"
-	"log"
+	"io"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
 // Cli - The main application object
@@ -13,6 +17,15 @@ type Cli struct {
 	defaultCommand *Command
 	preRunCommand  func(*Cli) error
 	bannerFunction func(*Cli) string
+	configPath     *string
+	configLoaded   string
+	configValues   map[string]interface{}
+	output         io.Writer
+	errOutput      io.Writer
+	input          io.Reader
+	inputReader    *bufio.Reader
+	assumeYes      bool
+	outputFormat   string
 }
 
 // Action represents a function that gets called when the command is executed
@@ -23,6 +36,9 @@ func NewCli(name, description, version string) *Cli {
 	result := &Cli{
 		version:        version,
 		bannerFunction: defaultBannerFunction,
+		output:         os.Stdout,
+		errOutput:      os.Stderr,
+		input:          os.Stdin,
 	}
 	result.rootCommand = NewCommand(name, description)
 	result.rootCommand.setApp(result)
@@ -51,10 +67,10 @@ func (c *Cli) SetBannerFunction(fn func(*Cli) string) {
 	c.bannerFunction = fn
 }
 
-// Abort prints the given error and terminates the application
+// Abort prints the given error to the error output and terminates the application with its exit code, see ExitCode
 func (c *Cli) Abort(err error) {
-	log.Fatal(err)
-	os.Exit(1)
+	fmt.Fprintln(c.ErrOutput(), err)
+	os.Exit(ExitCode(err))
 }
 
 // AddCommand - Adds a command to the application
@@ -64,8 +80,36 @@ func (c *Cli) AddCommand(command *Command) {
 
 // PrintBanner prints the application banner!
 func (c *Cli) PrintBanner() {
-	fmt.Println(c.bannerFunction(c))
-	fmt.Println("")
+	fmt.Fprintln(c.Output(), c.bannerFunction(c))
+	fmt.Fprintln(c.Output(), "")
+}
+
+// SetOutput sets the writer of the help and the application output, os.Stdout by default
+func (c *Cli) SetOutput(w io.Writer) *Cli {
+	c.output = w
+	return c
+}
+
+// SetErrOutput sets the writer of the errors, os.Stderr by default
+func (c *Cli) SetErrOutput(w io.Writer) *Cli {
+	c.errOutput = w
+	return c
+}
+
+// Output returns the writer of the help and the application output, for the actions to write to
+func (c *Cli) Output() io.Writer {
+	if c.output == nil {
+		return os.Stdout
+	}
+	return c.output
+}
+
+// ErrOutput returns the writer of the errors
+func (c *Cli) ErrOutput() io.Writer {
+	if c.errOutput == nil {
+		return os.Stderr
+	}
+	return c.errOutput
 }
 
 // PrintHelp - Prints the application's help
@@ -73,18 +117,37 @@ func (c *Cli) PrintHelp() {
 	c.rootCommand.PrintHelp()
 }
 
-// Run - Runs the application with the given arguments
+// Run - Runs the application with the given arguments,
+// the context of the actions is cancelled on SIGINT or SIGTERM
 func (c *Cli) Run(args ...string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		// Let a second signal terminate the application
+		<-ctx.Done()
+		stop()
+	}()
+
+	return c.RunContext(ctx, args...)
+}
+
+// RunContext - Runs the application with the given arguments and the context of the actions
+func (c *Cli) RunContext(ctx context.Context, args ...string) error {
+	if len(args) == 0 {
+		args = os.Args[1:]
+	}
+	// Completion scripts call the application back to complete the command line
+	if len(args) > 0 && args[0] == completeCommand {
+		c.rootCommand.complete(args[1:], c.Output())
+		return nil
+	}
 	if c.preRunCommand != nil {
 		err := c.preRunCommand(c)
 		if err != nil {
 			return err
 		}
 	}
-	if len(args) == 0 {
-		args = os.Args[1:]
-	}
-	return c.rootCommand.run(args)
+	return c.rootCommand.runContext(ctx, args)
 }
 
 // DefaultCommand - Sets the given command as the command to run when
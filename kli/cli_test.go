@@ -2,6 +2,8 @@ package kli
 
 import (
 	"errors"
+	"os"
+	"os/exec"
 	"testing"
 )
 
@@ -12,11 +14,6 @@ func TestCli(t *testing.T) {
 		c.SetBannerFunction(func(*Cli) string { return "" })
 	})
 
-	// t.Run("Run Abort()", func(t *testing.T) {
-	// 	cl := NewCli("test", "description", "0")
-	// 	cl.Abort(errors.New("test error"))
-	// })
-
 	t.Run("Run AddCommand()", func(t *testing.T) {
 		c.AddCommand(&Command{name: "test"})
 	})
@@ -165,3 +162,23 @@ func TestCliGlobalFlag(t *testing.T) {
 		t.Logf("After Execution")
 	})
 }
+
+func TestAbort(t *testing.T) {
+	// The test binary is run again to abort in a child process
+	if os.Getenv("KLI_TEST_ABORT") == "1" {
+		NewCli("test", "description", "0").SetErrOutput(os.Stdout).Abort(Exit(3, errors.New("test error")))
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestAbort$")
+	cmd.Env = append(os.Environ(), "KLI_TEST_ABORT=1")
+	out, err := cmd.Output()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Fatalf("unexpected exit %v", err)
+	}
+	if string(out) != "test error\n" {
+		t.Errorf("unexpected output %q", out)
+	}
+}
@@ -1,8 +1,10 @@
 package kli
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
@@ -17,11 +19,22 @@ type Command struct {
 	subCommandsMap    map[string]*Command
 	longestSubcommand int
 	actionCallback    Action
+	contextAction     ContextAction
+	beforeHooks       []ContextAction
+	afterHooks        []ContextAction
+	parent            *Command
 	app               *Cli
 	flags             *flag.FlagSet
 	flagCount         int
 	helpFlag          bool
 	hidden            bool
+	completers        map[string]Completer
+	bindings          map[string]*binding
+	required          map[string]bool
+	args              []argSpec
+	longestArg        int
+	shorts            map[rune]string
+	aliases           []string
 }
 
 // NewCommand creates a new Command
@@ -44,8 +57,9 @@ func (c *Command) setParentCommandPath(parentCommandPath string) {
 	}
 	c.commandPath += c.name
 
-	// Set up flag set
+	// Set up flag set, errors are printed by run
 	c.flags = flag.NewFlagSet(c.commandPath, flag.ContinueOnError)
+	c.flags.SetOutput(io.Discard)
 	c.BoolFlag("help", "Get help on the '"+strings.ToLower(c.commandPath)+"' command.", &c.helpFlag)
 }
 
@@ -53,27 +67,20 @@ func (c *Command) setApp(app *Cli) {
 	c.app = app
 }
 
-// parseFlags parses the given flags
-func (c *Command) parseFlags(args []string) ([]string, error) {
-	if len(args) == 0 {
-		return args, nil
-	}
-	tmp := os.Stderr
-	os.Stderr = nil
-	err := c.flags.Parse(args)
-	os.Stderr = tmp
-	return c.flags.Args(), err
-}
-
 // Run - Runs the Command with the given arguments
 func (c *Command) run(args []string) error {
+	return c.runContext(context.Background(), args)
+}
+
+// runContext runs the Command with the given arguments, the context is passed to the actions and hooks
+func (c *Command) runContext(ctx context.Context, args []string) error {
 
 	// Parse flags
 	args, err := c.parseFlags(args)
 	if err != nil {
-		fmt.Printf("Error: %s\n\n", err.Error())
+		fmt.Fprintf(c.errOutput(), "Error: %s\n\n", err.Error())
 		c.PrintHelp()
-		return err
+		return usageError(err)
 	}
 
 	// Help takes precedence
@@ -82,17 +89,40 @@ func (c *Command) run(args []string) error {
 		return nil
 	}
 
+	// Fall back to the environment and the config file
+	if err := c.resolveBindings(); err != nil {
+		fmt.Fprintf(c.errOutput(), "Error: %s\n\n", err.Error())
+		return usageError(err)
+	}
+
+	if err := c.checkRequired(); err != nil {
+		fmt.Fprintf(c.errOutput(), "Error: %s\n\n", err.Error())
+		c.PrintHelp()
+		return usageError(err)
+	}
+
 	// Check for subcommand
 	if len(args) > 0 {
 		subcommand := c.subCommandsMap[args[0]]
 		if subcommand != nil {
-			return subcommand.run(args[1:])
+			return subcommand.runContext(ctx, args[1:])
 		}
 	}
 
 	// Do we have an action?
-	if c.actionCallback != nil {
-		return c.actionCallback()
+	hasAction := c.actionCallback != nil || c.contextAction != nil
+	if !hasAction && len(args) > 0 && len(c.subCommands) > 0 {
+		err := c.unknownCommandError(args[0])
+		fmt.Fprintf(c.errOutput(), "Error: %s\n\n", err.Error())
+		return usageError(err)
+	}
+	if hasAction {
+		if err := c.checkArgs(args); err != nil {
+			fmt.Fprintf(c.errOutput(), "Error: %s\n\n", err.Error())
+			c.PrintHelp()
+			return usageError(err)
+		}
+		return c.runAction(&Context{Context: ctx, Command: c, Args: args})
 	}
 
 	// If we haven't specified a subcommand
@@ -102,7 +132,7 @@ func (c *Command) run(args []string) error {
 		if c.app.defaultCommand != c {
 			// only run default command if no args passed
 			if len(args) == 0 {
-				return c.app.defaultCommand.run(args)
+				return c.app.defaultCommand.runContext(ctx, args)
 			}
 		}
 	}
@@ -115,6 +145,7 @@ func (c *Command) run(args []string) error {
 // Action - Define an action from this command
 func (c *Command) Action(callback Action) *Command {
 	c.actionCallback = callback
+	c.contextAction = nil
 	return c
 }
 
@@ -124,20 +155,31 @@ func (c *Command) PrintHelp() {
 		c.app.PrintBanner()
 	}
 
+	w := c.output()
 	commandTitle := c.commandPath
 	if c.shortdescription != "" {
 		commandTitle += " - " + c.shortdescription
 	}
 	// Ignore root command
 	if c.commandPath != c.name {
-		fmt.Println(commandTitle)
+		fmt.Fprintln(w, commandTitle)
 	}
 	if c.longdescription != "" {
-		fmt.Println(c.longdescription + "\n")
+		fmt.Fprintln(w, c.longdescription+"\n")
+	}
+	if len(c.args) > 0 {
+		fmt.Fprintf(w, "Usage: %s\n\n", c.usageLine())
+		fmt.Fprintln(w, "Arguments:")
+		fmt.Fprintln(w, "")
+		for _, spec := range c.args {
+			spacer := strings.Repeat(" ", 3+c.longestArg-len(spec.name))
+			fmt.Fprintf(w, "   %s%s%s\n", spec.name, spacer, spec.description)
+		}
+		fmt.Fprintln(w, "")
 	}
 	if len(c.subCommands) > 0 {
-		fmt.Println("Available commands:")
-		fmt.Println("")
+		fmt.Fprintln(w, "Available commands:")
+		fmt.Fprintln(w, "")
 		for _, subcommand := range c.subCommands {
 			if subcommand.isHidden() {
 				continue
@@ -147,18 +189,40 @@ func (c *Command) PrintHelp() {
 			if subcommand.isDefaultCommand() {
 				isDefault = "[default]"
 			}
-			fmt.Printf("   %s%s%s %s\n", subcommand.name, spacer, subcommand.shortdescription, isDefault)
+			aliases := ""
+			if len(subcommand.aliases) > 0 {
+				aliases = "(aliases: " + strings.Join(subcommand.aliases, ", ") + ") "
+			}
+			fmt.Fprintf(w, "   %s%s%s %s%s\n", subcommand.name, spacer, subcommand.shortdescription, aliases, isDefault)
 		}
-		fmt.Println("")
+		fmt.Fprintln(w, "")
 	}
 	if c.flagCount > 0 {
-		fmt.Print("Flags:\n\n")
-		c.flags.SetOutput(os.Stdout)
+		fmt.Fprint(w, "Flags:\n\n")
+		restore := c.describeFlags(w)
+		c.flags.SetOutput(w)
 		c.flags.PrintDefaults()
-		c.flags.SetOutput(os.Stderr)
+		c.flags.SetOutput(io.Discard)
+		restore()
+
+	}
+	fmt.Fprintln(w)
+}
+
+// output returns the writer of the application output
+func (c *Command) output() io.Writer {
+	if c.app == nil {
+		return os.Stdout
+	}
+	return c.app.Output()
+}
 
+// errOutput returns the writer of the application errors
+func (c *Command) errOutput() io.Writer {
+	if c.app == nil {
+		return os.Stderr
 	}
-	fmt.Println()
+	return c.app.ErrOutput()
 }
 
 // isDefaultCommand returns true if called on the default command
@@ -188,11 +252,17 @@ func (c *Command) NewSubCommand(name, description string) *Command {
 
 // AddCommand - Adds a subcommand
 func (c *Command) AddCommand(command *Command) {
+	if command != c {
+		command.parent = c
+	}
 	command.setApp(c.app)
 	command.setParentCommandPath(c.commandPath)
 	name := command.name
 	c.subCommands = append(c.subCommands, command)
 	c.subCommandsMap[name] = command
+	for _, alias := range command.aliases {
+		c.subCommandsMap[alias] = command
+	}
 	if len(name) > c.longestSubcommand {
 		c.longestSubcommand = len(name)
 	}
@@ -0,0 +1,227 @@
+package kli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Completer returns the values completing the value of a flag starting with prefix
+type Completer func(prefix string) []string
+
+// completeCommand is the hidden command called by the completion scripts
+const completeCommand = "__complete"
+
+// completionScripts are the completion scripts of the supported shells,
+// they complete the command line with the candidates printed by the completeCommand
+var completionScripts = map[string]string{
+	"bash": `# bash completion for {{name}}
+_{{function}}_complete() {
+    local IFS=$'\n'
+    local words=() i
+    # Bash splits the words at =, e.g. --app=f into --app = f, join them back
+    for ((i = 1; i <= COMP_CWORD; i++)); do
+        if [[ $i -gt 1 && ( "${COMP_WORDS[i]}" == "=" || "${COMP_WORDS[i-1]}" == "=" ) ]]; then
+            words[${#words[@]}-1]+="${COMP_WORDS[i]}"
+        else
+            words+=("${COMP_WORDS[i]}")
+        fi
+    done
+    local cur="${words[${#words[@]}-1]}"
+    # The replies only replace the text after the last =
+    local prefix=""
+    [[ "$cur" == *=* ]] && prefix="${cur%=*}="
+    local candidates
+    candidates=$({{name}} {{complete}} "${words[@]}" 2>/dev/null | cut -f1)
+    COMPREPLY=($(compgen -W "${candidates}" -- "${cur}"))
+    COMPREPLY=("${COMPREPLY[@]#"$prefix"}")
+}
+complete -o default -F _{{function}}_complete {{name}}
+`,
+	"zsh": `#compdef {{name}}
+# zsh completion for {{name}}
+_{{function}}() {
+    local -a candidates
+    local line value
+    for line in "${(@f)$({{name}} {{complete}} "${(@)words[2,CURRENT]}" 2>/dev/null)}"; do
+        [[ -z "$line" ]] && continue
+        value=${line%%$'\t'*}
+        candidates+=("${value//:/\\:}:${line#*$'\t'}")
+    done
+    _describe '{{name}}' candidates
+}
+
+if [[ "$funcstack[1]" = "_{{function}}" ]]; then
+    _{{function}} "$@"
+else
+    compdef _{{function}} {{name}}
+fi
+`,
+	"fish": `# fish completion for {{name}}
+function __{{function}}_complete
+    set -l args (commandline -opc)
+    set -e args[1]
+    {{name}} {{complete}} $args (commandline -ct) 2>/dev/null
+end
+complete -c {{name}} -f -a '(__{{function}}_complete)'
+`,
+}
+
+// FlagCompleter sets the function completing the values of a flag of the command
+func (c *Command) FlagCompleter(name string, completer Completer) *Command {
+	if c.completers == nil {
+		c.completers = make(map[string]Completer)
+	}
+	c.completers[name] = completer
+	return c
+}
+
+// FlagCompleter sets the function completing the values of a flag of the root command
+func (c *Cli) FlagCompleter(name string, completer Completer) *Cli {
+	c.rootCommand.FlagCompleter(name, completer)
+	return c
+}
+
+// GenerateCompletion writes the completion script of a shell: bash, zsh or fish
+func (c *Cli) GenerateCompletion(shell string, w io.Writer) error {
+	script, ok := completionScripts[shell]
+	if !ok {
+		return fmt.Errorf("Unsupported shell %s, accept only bash, zsh and fish", shell)
+	}
+
+	replacer := strings.NewReplacer(
+		"{{name}}", c.Name(),
+		"{{function}}", identifier(c.Name()),
+		"{{complete}}", completeCommand,
+	)
+	_, err := io.WriteString(w, replacer.Replace(script))
+	return err
+}
+
+// AddCompletionCommand adds a completion command printing the completion scripts, e.g.
+//
+//	source <(app completion bash)
+func (c *Cli) AddCompletionCommand() *Command {
+	completion := c.NewSubCommand("completion", "Generate the shell completion script")
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		shell := shell
+		completion.NewSubCommand(shell, "Generate the "+shell+" completion script").Action(func() error {
+			return c.GenerateCompletion(shell, c.Output())
+		})
+	}
+
+	return completion
+}
+
+// complete writes the candidates completing the last word, one per line
+// followed by a tab and their description
+func (c *Command) complete(words []string, w io.Writer) {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	current := words[len(words)-1]
+
+	// Walk the command tree like run does
+	command := c
+	var pending *flag.Flag
+	positional := false
+	for _, word := range words[:len(words)-1] {
+		switch {
+		case pending != nil:
+			pending = nil
+		case word == "--":
+			return
+		case strings.HasPrefix(word, "-"):
+			name := strings.TrimLeft(word, "-")
+			if strings.Contains(name, "=") {
+				continue
+			}
+			if f := command.lookupFlag(name); f != nil && !isBoolFlag(f) {
+				pending = f
+			}
+		case positional:
+		default:
+			subcommand, ok := command.subCommandsMap[word]
+			if !ok {
+				if command.stopsAt(word, true) {
+					// The remaining words are other arguments
+					return
+				}
+				// Flags may follow the other arguments
+				positional = true
+				continue
+			}
+			command = subcommand
+		}
+	}
+
+	if pending != nil {
+		command.completeValue(w, pending.Name, "", current)
+		return
+	}
+
+	if strings.HasPrefix(current, "-") {
+		dashes := current[:len(current)-len(strings.TrimLeft(current, "-"))]
+		name := current[len(dashes):]
+		if i := strings.Index(name, "="); i >= 0 {
+			command.completeValue(w, name[:i], dashes+name[:i+1], name[i+1:])
+			return
+		}
+
+		command.flags.VisitAll(func(f *flag.Flag) {
+			if strings.HasPrefix(f.Name, name) {
+				fmt.Fprintf(w, "%s%s\t%s\n", dashes, f.Name, f.Usage)
+			}
+		})
+		return
+	}
+
+	if positional {
+		return
+	}
+	for _, subcommand := range command.subCommands {
+		if !subcommand.isHidden() && strings.HasPrefix(subcommand.name, current) {
+			fmt.Fprintf(w, "%s\t%s\n", subcommand.name, subcommand.shortdescription)
+		}
+	}
+}
+
+// completeValue writes the values of a flag returned by its completer
+func (c *Command) completeValue(w io.Writer, name, prefix, value string) {
+	completer, ok := c.completers[name]
+	if !ok {
+		// Complete the allowed values of enum flags
+		f := c.flags.Lookup(name)
+		if f == nil {
+			return
+		}
+		enum, ok := f.Value.(*enumValue)
+		if !ok {
+			return
+		}
+		completer = func(string) []string { return enum.allowed }
+	}
+
+	for _, candidate := range completer(value) {
+		if strings.HasPrefix(candidate, value) {
+			fmt.Fprintf(w, "%s%s\t\n", prefix, candidate)
+		}
+	}
+}
+
+// isBoolFlag returns true if the flag does not expect a value
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// identifier returns the name usable as a shell function name
+func identifier(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
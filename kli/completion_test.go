@@ -0,0 +1,140 @@
+package kli
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newCompletionCli() *Cli {
+	cli := NewCli("vault-cli", "Vault tools", "0")
+
+	var addr string
+	cli.StringFlag("addr", "Vault address", &addr)
+
+	apps := cli.NewSubCommand("apps", "Manage applications")
+	var app string
+	var force bool
+	apps.NewSubCommand("show", "Show an application").
+		StringFlag("app", "Application name", &app).
+		BoolFlag("force", "Force", &force).
+		FlagCompleter("app", func(prefix string) []string {
+			return []string{"barong", "peatio", "finex"}
+		})
+	apps.NewSubCommand("debug", "Debug an application").Hidden()
+	cli.AddCompletionCommand()
+
+	return cli
+}
+
+func TestComplete(t *testing.T) {
+	cli := newCompletionCli()
+
+	tests := []struct {
+		words    []string
+		expected string
+	}{
+		{nil, "apps\tManage applications\ncompletion\tGenerate the shell completion script\n"},
+		{[]string{"a"}, "apps\tManage applications\n"},
+		{[]string{"-addr", "localhost", "apps", ""}, "show\tShow an application\n"},
+		{[]string{"apps", "d"}, ""},
+		{[]string{"apps", "show", "-"}, "-app\tApplication name\n-force\tForce\n-help\tGet help on the 'vault-cli apps show' command.\n"},
+		{[]string{"apps", "show", "--f"}, "--force\tForce\n"},
+		{[]string{"apps", "show", "-app", ""}, "barong\t\npeatio\t\nfinex\t\n"},
+		{[]string{"apps", "show", "-force", "-app", "p"}, "peatio\t\n"},
+		{[]string{"apps", "show", "--app=f"}, "--app=finex\t\n"},
+		{[]string{"-addr", ""}, ""},
+		{[]string{"unknown", ""}, ""},
+		{[]string{"completion", "z"}, "zsh\tGenerate the zsh completion script\n"},
+	}
+
+	for _, test := range tests {
+		var out bytes.Buffer
+		cli.rootCommand.complete(test.words, &out)
+		if out.String() != test.expected {
+			t.Errorf("complete(%q) = %q, expected %q", test.words, out.String(), test.expected)
+		}
+	}
+}
+
+func TestBashCompletion(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not installed")
+	}
+
+	cli := newCompletionCli()
+	var script bytes.Buffer
+	if err := cli.GenerateCompletion("bash", &script); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		words    []string
+		args     string
+		expected string
+	}{
+		{[]string{"apps", "show", "-app", "p"}, "apps show -app p", "peatio"},
+		// Bash splits --app=f at =
+		{[]string{"apps", "show", "--app", "=", "f"}, "apps show --app=f", "finex"},
+		{[]string{"apps", "show", "--app", "="}, "apps show --app=", "barong peatio finex"},
+	}
+
+	for _, test := range tests {
+		// The application called back by the script completes the words it receives
+		dir := t.TempDir()
+		argsPath := filepath.Join(dir, "args")
+		candidatesPath := filepath.Join(dir, "candidates")
+		var candidates bytes.Buffer
+		cli.rootCommand.complete(strings.Split(test.args, " "), &candidates)
+		if err := os.WriteFile(candidatesPath, candidates.Bytes(), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		shell := script.String() + `
+vault-cli() { shift; printf '%s ' "$@" > "` + argsPath + `"; cat "` + candidatesPath + `"; }
+COMP_WORDS=(vault-cli ` + strings.Join(test.words, " ") + `)
+COMP_CWORD=$((${#COMP_WORDS[@]} - 1))
+_vault_cli_complete
+echo "${COMPREPLY[*]}"
+`
+		out, err := exec.Command("bash", "-c", shell).CombinedOutput()
+		if err != nil {
+			t.Fatalf("%s: %s", err, out)
+		}
+		if reply := strings.TrimSpace(string(out)); reply != test.expected {
+			t.Errorf("completion of %q = %q, expected %q", test.words, reply, test.expected)
+		}
+		if args, _ := os.ReadFile(argsPath); strings.TrimSpace(string(args)) != test.args {
+			t.Errorf("completion of %q called back with %q, expected %q", test.words, args, test.args)
+		}
+	}
+}
+
+func TestGenerateCompletion(t *testing.T) {
+	cli := newCompletionCli()
+
+	for shell, expected := range map[string]string{
+		"bash": "complete -o default -F _vault_cli_complete vault-cli",
+		"zsh":  "compdef _vault_cli vault-cli",
+		"fish": "complete -c vault-cli -f -a '(__vault_cli_complete)'",
+	} {
+		var out bytes.Buffer
+		if err := cli.GenerateCompletion(shell, &out); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("%s completion script does not contain %q:\n%s", shell, expected, out.String())
+		}
+		if !strings.Contains(out.String(), "vault-cli __complete") {
+			t.Errorf("%s completion script does not call the application back", shell)
+		}
+	}
+
+	err := cli.GenerateCompletion("powershell", &bytes.Buffer{})
+	if err == nil || err.Error() != "Unsupported shell powershell, accept only bash, zsh and fish" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,237 @@
+package kli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// CommandInfo describes a command and its visible subcommands, e.g. to generate documentation
+type CommandInfo struct {
+	Name            string        `json:"name"`
+	Aliases         []string      `json:"aliases,omitempty"`
+	Path            string        `json:"path"`
+	Description     string        `json:"description,omitempty"`
+	LongDescription string        `json:"long_description,omitempty"`
+	Usage           string        `json:"usage"`
+	Default         bool          `json:"default,omitempty"`
+	Args            []ArgInfo     `json:"args,omitempty"`
+	Flags           []FlagInfo    `json:"flags,omitempty"`
+	Commands        []CommandInfo `json:"commands,omitempty"`
+}
+
+// ArgInfo describes a positional argument, see Arg for its count
+type ArgInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Count       int    `json:"count"`
+}
+
+// FlagInfo describes a flag
+type FlagInfo struct {
+	Name        string   `json:"name"`
+	Short       string   `json:"short,omitempty"`
+	Type        string   `json:"type"`
+	Description string   `json:"description,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	Values      []string `json:"values,omitempty"`
+	Required    bool     `json:"required,omitempty"`
+	Env         []string `json:"env,omitempty"`
+	Config      string   `json:"config,omitempty"`
+}
+
+// Info returns the description of the command, without the help flag
+func (c *Command) Info() CommandInfo {
+	info := CommandInfo{
+		Name:            c.name,
+		Aliases:         c.aliases,
+		Path:            c.commandPath,
+		Description:     c.shortdescription,
+		LongDescription: c.longdescription,
+		Usage:           c.usageLine(),
+		Default:         c.isDefaultCommand(),
+	}
+
+	for _, spec := range c.args {
+		info.Args = append(info.Args, ArgInfo{Name: spec.name, Description: spec.description, Count: spec.count})
+	}
+
+	if c.flags != nil {
+		c.flags.VisitAll(func(f *flag.Flag) {
+			if f.Name == "help" {
+				return
+			}
+			typeName, usage := flag.UnquoteUsage(f)
+			fi := FlagInfo{
+				Name:        f.Name,
+				Type:        typeName,
+				Description: usage,
+				Default:     f.DefValue,
+				Required:    c.required[f.Name],
+			}
+			switch v := f.Value.(type) {
+			case *stringsValue:
+				fi.Type = "strings"
+			case *mapValue:
+				fi.Type = "map"
+			case *enumValue:
+				fi.Type = "enum"
+				fi.Values = v.allowed
+			case *fieldValue:
+				fi.Type = v.field.Value().Type().String()
+			}
+			if isBoolFlag(f) {
+				fi.Type = "bool"
+			}
+			if short, ok := c.shortOf(f.Name); ok {
+				fi.Short = string(short)
+			}
+			if b, ok := c.bindings[f.Name]; ok {
+				fi.Env = b.envs
+				fi.Config = b.key
+			}
+			info.Flags = append(info.Flags, fi)
+		})
+	}
+
+	for _, subcommand := range c.subCommands {
+		if !subcommand.isHidden() {
+			info.Commands = append(info.Commands, subcommand.Info())
+		}
+	}
+
+	return info
+}
+
+// Info returns the description of the command tree of the application
+func (c *Cli) Info() CommandInfo {
+	return c.rootCommand.Info()
+}
+
+// WriteJSON writes the command tree of the application as JSON
+func (c *Cli) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(c.Info())
+}
+
+// WriteMarkdown writes the reference of the commands of the application as markdown,
+// with a section per command
+func (c *Cli) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	info := c.Info()
+	fmt.Fprintf(&b, "# %s\n\n", info.Name)
+	if info.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", info.Description)
+	}
+	if c.version != "" {
+		fmt.Fprintf(&b, "Version: %s\n\n", c.version)
+	}
+	writeMarkdownCommand(&b, info, true)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownCommand(b *strings.Builder, info CommandInfo, root bool) {
+	if !root {
+		fmt.Fprintf(b, "## %s\n\n", info.Path)
+		if info.Description != "" {
+			fmt.Fprintf(b, "%s\n\n", info.Description)
+		}
+		if len(info.Aliases) > 0 {
+			fmt.Fprintf(b, "Aliases: %s\n\n", strings.Join(info.Aliases, ", "))
+		}
+	}
+	if info.LongDescription != "" {
+		fmt.Fprintf(b, "%s\n\n", info.LongDescription)
+	}
+	fmt.Fprintf(b, "```\n%s\n```\n\n", info.Usage)
+
+	if len(info.Args) > 0 {
+		b.WriteString("| Argument | Description |\n| --- | --- |\n")
+		for _, arg := range info.Args {
+			fmt.Fprintf(b, "| `%s` | %s |\n", argSpec{name: arg.Name, count: arg.Count}.usage(), escapeCell(arg.Description))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(info.Flags) > 0 {
+		b.WriteString("| Flag | Type | Default | Description |\n| --- | --- | --- | --- |\n")
+		for _, f := range info.Flags {
+			description := f.Description
+			if f.Required {
+				description += " (required)"
+			}
+			if len(f.Env) > 0 {
+				description += ", env " + strings.Join(f.Env, ", ")
+			}
+			if f.Config != "" {
+				description += ", config " + f.Config
+			}
+			def := ""
+			if f.Default != "" {
+				def = "`" + f.Default + "`"
+			}
+			name := "`-" + f.Name + "`"
+			if f.Short != "" {
+				name = "`-" + f.Short + "`, " + name
+			}
+			fmt.Fprintf(b, "| %s | %s | %s | %s |\n", name, f.Type, def, escapeCell(description))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(info.Commands) > 0 {
+		b.WriteString("| Command | Description |\n| --- | --- |\n")
+		for _, sub := range info.Commands {
+			fmt.Fprintf(b, "| `%s` | %s |\n", sub.Path, escapeCell(sub.Description))
+		}
+		b.WriteString("\n")
+	}
+
+	for _, sub := range info.Commands {
+		writeMarkdownCommand(b, sub, false)
+	}
+}
+
+// escapeCell escapes a markdown table cell
+func escapeCell(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
+
+// Describe returns the commands of the application as table keys and rows,
+// so that the application fills a doc.Document
+func (c *Cli) Describe() ([]string, [][]string, error) {
+	var rows [][]string
+	var walk func(info CommandInfo)
+	walk = func(info CommandInfo) {
+		for _, sub := range info.Commands {
+			rows = append(rows, []string{sub.Usage, sub.Description})
+			walk(sub)
+		}
+	}
+	walk(c.Info())
+	sort.Slice(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+
+	return []string{"Command", "Description"}, rows, nil
+}
+
+// AddDocsCommand adds a hidden docs command writing the command tree as JSON or markdown
+func (c *Cli) AddDocsCommand() *Command {
+	format := "markdown"
+	docs := c.NewSubCommand("docs", "Generate the reference of the commands")
+	docs.EnumFlag("format", "Output format", []string{"markdown", "json"}, &format)
+	docs.Action(func() error {
+		if format == "json" {
+			return c.WriteJSON(c.Output())
+		}
+		return c.WriteMarkdown(c.Output())
+	})
+	docs.Hidden()
+
+	return docs
+}
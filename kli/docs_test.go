@@ -0,0 +1,134 @@
+package kli
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newDocsCli(out, errOut *bytes.Buffer) *Cli {
+	cli := NewCli("vault-cli", "Vault tools", "1.0.0")
+	cli.SetOutput(out).SetErrOutput(errOut)
+
+	var addr, format string
+	cli.StringFlag("addr", "Vault address", &addr).FlagEnv("addr", "VAULT_ADDR")
+	apps := cli.NewSubCommand("apps", "Manage applications")
+	apps.NewSubCommand("show", "Show an application").
+		EnumFlag("format", "Format", []string{"yaml", "json"}, &format).
+		Required("format").
+		Arg("app", "Application | name", 1).
+		Action(func() error { return nil })
+	apps.NewSubCommand("debug", "Debug an application").Hidden()
+	cli.AddDocsCommand()
+
+	return cli
+}
+
+func TestOutput(t *testing.T) {
+	var out, errOut bytes.Buffer
+	cli := newDocsCli(&out, &errOut)
+
+	if err := cli.Run("apps", "-help"); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"vault-cli 1.0.0 - Vault tools", "vault-cli apps - Manage applications", "   show    Show an application", "Flags:"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("help does not contain %q:\n%s", expected, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "debug") {
+		t.Errorf("help contains a hidden command:\n%s", out.String())
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("unexpected errors %q", errOut.String())
+	}
+
+	out.Reset()
+	if err := cli.Run("-unknown"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if errOut.String() != "Error: flag provided but not defined: -unknown\n\n" {
+		t.Errorf("unexpected errors %q", errOut.String())
+	}
+	if !strings.Contains(out.String(), "Available commands:") {
+		t.Errorf("help not printed:\n%s", out.String())
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var out, errOut bytes.Buffer
+	cli := newDocsCli(&out, &errOut)
+
+	if err := cli.Run("docs", "-format", "json"); err != nil {
+		t.Fatal(err)
+	}
+
+	info := CommandInfo{}
+	if err := json.Unmarshal(out.Bytes(), &info); err != nil {
+		t.Fatal(err)
+	}
+	if info.Name != "vault-cli" || len(info.Commands) != 1 {
+		t.Fatalf("unexpected tree %+v", info)
+	}
+	if !reflect.DeepEqual(info.Flags, []FlagInfo{{Name: "addr", Type: "string", Description: "Vault address", Env: []string{"VAULT_ADDR"}}}) {
+		t.Errorf("unexpected flags %+v", info.Flags)
+	}
+
+	apps := info.Commands[0]
+	if len(apps.Commands) != 1 {
+		t.Fatalf("unexpected apps commands %+v", apps.Commands)
+	}
+	show := apps.Commands[0]
+	if show.Path != "vault-cli apps show" || show.Usage != "vault-cli apps show [flags] <app>" {
+		t.Errorf("unexpected command %+v", show)
+	}
+	expected := FlagInfo{Name: "format", Type: "enum", Description: "Format (yaml, json)", Values: []string{"yaml", "json"}, Required: true}
+	if !reflect.DeepEqual(show.Flags, []FlagInfo{expected}) {
+		t.Errorf("unexpected flags %+v", show.Flags)
+	}
+	if !reflect.DeepEqual(show.Args, []ArgInfo{{Name: "app", Description: "Application | name", Count: 1}}) {
+		t.Errorf("unexpected args %+v", show.Args)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var out, errOut bytes.Buffer
+	cli := newDocsCli(&out, &errOut)
+
+	if err := cli.Run("docs"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"# vault-cli\n\nVault tools\n\nVersion: 1.0.0\n",
+		"| `-addr` | string |  | Vault address, env VAULT_ADDR |",
+		"## vault-cli apps show\n\nShow an application\n\n```\nvault-cli apps show [flags] <app>\n```",
+		"| `<app>` | Application \\| name |",
+		"| `-format` | enum |  | Format (yaml, json) (required) |",
+		"| `vault-cli apps show` | Show an application |",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("markdown does not contain %q:\n%s", expected, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "debug") || strings.Contains(out.String(), "docs") {
+		t.Errorf("markdown contains hidden commands:\n%s", out.String())
+	}
+
+	keys, rows, err := cli.Describe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(keys, []string{"Command", "Description"}) {
+		t.Errorf("unexpected keys %v", keys)
+	}
+	expectedRows := [][]string{
+		{"vault-cli apps [flags]", "Manage applications"},
+		{"vault-cli apps show [flags] <app>", "Show an application"},
+	}
+	if !reflect.DeepEqual(rows, expectedRows) {
+		t.Errorf("unexpected rows %v", rows)
+	}
+}
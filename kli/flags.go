@@ -0,0 +1,243 @@
+package kli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// stringsValue is a repeatable flag of comma separated values
+type stringsValue struct {
+	values  *[]string
+	changed bool
+}
+
+func (v *stringsValue) String() string {
+	if v.values == nil {
+		return ""
+	}
+	return strings.Join(*v.values, ",")
+}
+
+func (v *stringsValue) Set(s string) error {
+	// The first value replaces the default ones
+	if !v.changed {
+		*v.values = nil
+		v.changed = true
+	}
+	*v.values = append(*v.values, strings.Split(s, ",")...)
+	return nil
+}
+
+// mapValue is a repeatable flag of comma separated key=value pairs
+type mapValue struct {
+	values  *map[string]string
+	changed bool
+}
+
+func (v *mapValue) String() string {
+	if v.values == nil {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(*v.values))
+	for key, value := range *v.values {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (v *mapValue) Set(s string) error {
+	values := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		i := strings.Index(pair, "=")
+		if i <= 0 {
+			return fmt.Errorf("expected key=value, got %q", pair)
+		}
+		values[pair[:i]] = pair[i+1:]
+	}
+
+	// The first values replace the default ones
+	if !v.changed || *v.values == nil {
+		*v.values = make(map[string]string)
+		v.changed = true
+	}
+	for key, value := range values {
+		(*v.values)[key] = value
+	}
+	return nil
+}
+
+// enumValue is a string flag restricted to allowed values
+type enumValue struct {
+	value   *string
+	allowed []string
+}
+
+func (v *enumValue) String() string {
+	if v.value == nil {
+		return ""
+	}
+	return *v.value
+}
+
+func (v *enumValue) Set(s string) error {
+	for _, allowed := range v.allowed {
+		if s == allowed {
+			*v.value = s
+			return nil
+		}
+	}
+
+	return fmt.Errorf("must be one of %s", strings.Join(v.allowed, ", "))
+}
+
+// DurationFlag - Adds a duration flag to the command, e.g. 1m30s
+func (c *Command) DurationFlag(name, description string, variable *time.Duration) *Command {
+	c.flags.DurationVar(variable, name, *variable, description)
+	c.flagCount++
+	return c
+}
+
+// Float64Flag - Adds a float flag to the command
+func (c *Command) Float64Flag(name, description string, variable *float64) *Command {
+	c.flags.Float64Var(variable, name, *variable, description)
+	c.flagCount++
+	return c
+}
+
+// StringsFlag - Adds a repeatable flag of comma separated strings to the command,
+// the given values replace the default ones
+func (c *Command) StringsFlag(name, description string, variable *[]string) *Command {
+	c.flags.Var(&stringsValue{values: variable}, name, description)
+	c.flagCount++
+	return c
+}
+
+// StringMapFlag - Adds a repeatable flag of comma separated key=value pairs to the command,
+// the given pairs replace the default ones
+func (c *Command) StringMapFlag(name, description string, variable *map[string]string) *Command {
+	c.flags.Var(&mapValue{values: variable}, name, description)
+	c.flagCount++
+	return c
+}
+
+// EnumFlag - Adds a string flag accepting only the allowed values to the command
+func (c *Command) EnumFlag(name, description string, allowed []string, variable *string) *Command {
+	description += " (" + strings.Join(allowed, ", ") + ")"
+	c.flags.Var(&enumValue{value: variable, allowed: allowed}, name, description)
+	c.flagCount++
+	return c
+}
+
+// Required - Marks flags of the command as required, they must be given
+// on the command line or by their environment variables or config file keys
+func (c *Command) Required(names ...string) *Command {
+	if c.required == nil {
+		c.required = make(map[string]bool)
+	}
+	for _, name := range names {
+		c.required[name] = true
+	}
+	return c
+}
+
+// checkRequired returns an error listing the required flags not set
+func (c *Command) checkRequired() error {
+	if len(c.required) == 0 {
+		return nil
+	}
+
+	set := make(map[string]bool)
+	c.flags.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	var missing []string
+	for name := range c.required {
+		if !set[name] {
+			missing = append(missing, "-"+name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+
+	if len(missing) == 1 {
+		return fmt.Errorf("Required flag %s is missing", missing[0])
+	}
+	return fmt.Errorf("Required flags %s are missing", strings.Join(missing, ", "))
+}
+
+// describeFlags adds to the usage of the flags whether they are required,
+// and their environment variables and config keys, until the returned function is called
+func (c *Command) describeFlags(w io.Writer) func() {
+	if len(c.bindings) > 0 {
+		fmt.Fprint(w, "Flags are read from the command line, then the environment, the config file and their default.\n\n")
+	}
+
+	usages := make(map[*flag.Flag]string)
+	c.flags.VisitAll(func(f *flag.Flag) {
+		usage := f.Usage
+		if short, ok := c.shortOf(f.Name); ok {
+			usage += fmt.Sprintf(" (-%c)", short)
+		}
+		if c.required[f.Name] {
+			usage += " (required)"
+		}
+		if b, ok := c.bindings[f.Name]; ok {
+			usage += b.usage()
+		}
+		if usage != f.Usage {
+			usages[f] = f.Usage
+			f.Usage = usage
+		}
+	})
+
+	return func() {
+		for f, usage := range usages {
+			f.Usage = usage
+		}
+	}
+}
+
+// DurationFlag - Adds a duration flag to the root command
+func (c *Cli) DurationFlag(name, description string, variable *time.Duration) *Cli {
+	c.rootCommand.DurationFlag(name, description, variable)
+	return c
+}
+
+// Float64Flag - Adds a float flag to the root command
+func (c *Cli) Float64Flag(name, description string, variable *float64) *Cli {
+	c.rootCommand.Float64Flag(name, description, variable)
+	return c
+}
+
+// StringsFlag - Adds a repeatable flag of comma separated strings to the root command
+func (c *Cli) StringsFlag(name, description string, variable *[]string) *Cli {
+	c.rootCommand.StringsFlag(name, description, variable)
+	return c
+}
+
+// StringMapFlag - Adds a repeatable flag of comma separated key=value pairs to the root command
+func (c *Cli) StringMapFlag(name, description string, variable *map[string]string) *Cli {
+	c.rootCommand.StringMapFlag(name, description, variable)
+	return c
+}
+
+// EnumFlag - Adds a string flag accepting only the allowed values to the root command
+func (c *Cli) EnumFlag(name, description string, allowed []string, variable *string) *Cli {
+	c.rootCommand.EnumFlag(name, description, allowed, variable)
+	return c
+}
+
+// Required - Marks flags of the root command as required
+func (c *Cli) Required(names ...string) *Cli {
+	c.rootCommand.Required(names...)
+	return c
+}
@@ -0,0 +1,176 @@
+package kli
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openware/pkg/ika"
+)
+
+func TestFlagTypes(t *testing.T) {
+	timeout := 5 * time.Second
+	ratio := 0.5
+	hosts := []string{"localhost"}
+	labels := map[string]string{"env": "dev"}
+	mode := "dry-run"
+
+	cli := NewCli("test", "description", "0")
+	cli.DurationFlag("timeout", "Timeout", &timeout).
+		Float64Flag("ratio", "Ratio", &ratio).
+		StringsFlag("host", "Hosts", &hosts).
+		StringMapFlag("label", "Labels", &labels).
+		EnumFlag("mode", "Mode", []string{"dry-run", "apply"}, &mode)
+	cli.Action(func() error { return nil })
+
+	err := cli.Run("-timeout", "1m30s", "-ratio", "0.25", "-host", "a,b", "-host", "c",
+		"-label", "app=peatio,tier=backend", "-label", "env=prod", "-mode", "apply")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if timeout != 90*time.Second || ratio != 0.25 || mode != "apply" {
+		t.Errorf("unexpected values %s, %f, %s", timeout, ratio, mode)
+	}
+	if !reflect.DeepEqual(hosts, []string{"a", "b", "c"}) {
+		t.Errorf("unexpected hosts %v", hosts)
+	}
+	if !reflect.DeepEqual(labels, map[string]string{"app": "peatio", "tier": "backend", "env": "prod"}) {
+		t.Errorf("unexpected labels %v", labels)
+	}
+
+	for args, expected := range map[string]string{
+		"-mode=destroy": `invalid value "destroy" for flag -mode: must be one of dry-run, apply`,
+		"-label=app":    `invalid value "app" for flag -label: expected key=value, got "app"`,
+	} {
+		err := cli.Run(args)
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error %v for %s", err, args)
+		}
+	}
+
+	var out bytes.Buffer
+	cli.rootCommand.complete([]string{"-mode", ""}, &out)
+	if out.String() != "dry-run\t\napply\t\n" {
+		t.Errorf("unexpected completion %q", out.String())
+	}
+}
+
+func TestRequiredFlags(t *testing.T) {
+	var app, scope string
+	cli := NewCli("test", "description", "0")
+	cli.StringFlag("app", "Application", &app).
+		StringFlag("scope", "Scope", &scope).
+		FlagEnv("scope", "KLI_TEST_SCOPE").
+		Required("app", "scope")
+	cli.Action(func() error { return nil })
+
+	err := cli.Run("-help=false")
+	if err == nil || err.Error() != "Required flags -app, -scope are missing" {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	t.Setenv("KLI_TEST_SCOPE", "private")
+	err = cli.Run("-help=false")
+	if err == nil || err.Error() != "Required flag -app is missing" {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if err := cli.Run("-app", "peatio"); err != nil {
+		t.Fatal(err)
+	}
+	if app != "peatio" || scope != "private" {
+		t.Errorf("unexpected values %s, %s", app, scope)
+	}
+
+	restore := cli.rootCommand.describeFlags(io.Discard)
+	if usage := cli.rootCommand.flags.Lookup("scope").Usage; usage != "Scope (required) [env KLI_TEST_SCOPE]" {
+		t.Errorf("unexpected usage %q", usage)
+	}
+	restore()
+	if usage := cli.rootCommand.flags.Lookup("scope").Usage; usage != "Scope" {
+		t.Errorf("usage not restored: %q", usage)
+	}
+}
+
+// upperValue is an ika Setter
+type upperValue string
+
+func (v *upperValue) SetValue(s string) error {
+	*v = upperValue(strings.ToUpper(s))
+	return nil
+}
+
+func TestBindConfigTypes(t *testing.T) {
+	cfg := &struct {
+		Hosts  []string          `yaml:"hosts" env-default:"a,b"`
+		Labels map[string]string `yaml:"labels"`
+		Token  string            `yaml:"token" env-required:"true"`
+		Ports  []int             `yaml:"ports" env-separator:";"`
+		Since  time.Time         `yaml:"since" env-layout:"2006-01-02"`
+		Name   upperValue        `yaml:"name"`
+	}{}
+
+	cli := NewCli("test", "description", "0")
+	if err := cli.BindConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	cli.Action(func() error { return nil })
+
+	err := cli.Run("-help=false")
+	if err == nil || err.Error() != "Required flag -token is missing" {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	// Values are parsed like ika does: maps of key:value pairs, the env-separator, env-layout and Setter types
+	if err := cli.Run("-token", "secret", "-labels", "a:1,b:c:d", "-ports", "80;443", "-since", "2022-10-01", "-name", "finex"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg.Hosts, []string{"a", "b"}) || !reflect.DeepEqual(cfg.Labels, map[string]string{"a": "1", "b": "c:d"}) {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+	if !reflect.DeepEqual(cfg.Ports, []int{80, 443}) || cfg.Since != time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC) || cfg.Name != "FINEX" {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+
+	err = cli.Run("-token", "secret", "-labels", "a=1")
+	if err == nil || err.Error() != `invalid value "a=1" for flag -labels: invalid map item: "a=1"` {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestBindConfigFile(t *testing.T) {
+	path := writeConfig(t, "labels:\n  a: 1\n  b: 2\nports: [80, 443]\n")
+
+	cfg := &struct {
+		Labels map[string]int `yaml:"labels" env-separator:";"`
+		Ports  []int          `yaml:"ports" env-separator:";"`
+	}{}
+	cli := NewCli("test", "description", "0").ConfigFile(path)
+	if err := cli.BindConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	cli.Action(func() error { return nil })
+
+	if err := cli.Run("-help=false"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg.Labels, map[string]int{"a": 1, "b": 2}) || !reflect.DeepEqual(cfg.Ports, []int{80, 443}) {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+
+	// The flags are read back by ika
+	fs := cli.rootCommand.flags
+	other := &struct {
+		Ports []int `yaml:"ports" env-separator:";"`
+	}{}
+	if _, err := ika.ReadSources(other, ika.Flags(fs)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(other.Ports, []int{80, 443}) {
+		t.Errorf("unexpected ports %v", other.Ports)
+	}
+}
@@ -1,3 +1,17 @@
 module github.com/openware/pkg/kli
 
 go 1.18
+
+require (
+	github.com/openware/pkg/ika v0.1.1
+	golang.org/x/term v0.0.0-20220722155259-a9ba230a4035
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/BurntSushi/toml v1.2.1 // indirect
+	github.com/joho/godotenv v1.4.0 // indirect
+	golang.org/x/sys v0.0.0-20220722155257-8c9f86f7a55f // indirect
+)
+
+replace github.com/openware/pkg/ika => ../ika
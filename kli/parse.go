@@ -0,0 +1,160 @@
+package kli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// Short - Sets the one letter shorthand of a flag, e.g. -v for --verbose.
+// Shorthands of bool flags may be combined, e.g. -vf, and values may follow the shorthand, e.g. -n5
+func (c *Command) Short(name string, short rune) *Command {
+	if c.flags.Lookup(name) == nil {
+		panic(fmt.Sprintf("kli: shorthand -%c of undefined flag %s", short, name))
+	}
+	if other, ok := c.shorts[short]; ok {
+		panic(fmt.Sprintf("kli: shorthand -%c of flag %s already used by flag %s", short, name, other))
+	}
+
+	if c.shorts == nil {
+		c.shorts = make(map[rune]string)
+	}
+	c.shorts[short] = name
+	return c
+}
+
+// Short - Sets the one letter shorthand of a flag of the root command
+func (c *Cli) Short(name string, short rune) *Cli {
+	c.rootCommand.Short(name, short)
+	return c
+}
+
+// shortOf returns the shorthand of a flag
+func (c *Command) shortOf(name string) (rune, bool) {
+	for short, long := range c.shorts {
+		if long == name {
+			return short, true
+		}
+	}
+
+	return 0, false
+}
+
+// lookupFlag returns a flag by name or shorthand
+func (c *Command) lookupFlag(name string) *flag.Flag {
+	if f := c.flags.Lookup(name); f != nil {
+		return f
+	}
+	if runes := []rune(name); len(runes) == 1 {
+		if long, ok := c.shorts[runes[0]]; ok {
+			return c.flags.Lookup(long)
+		}
+	}
+
+	return nil
+}
+
+// parseFlags parses the given flags, GNU style: flags are given with one or two dashes, with their
+// shorthands, and before or after the other arguments, until the name of a subcommand or --
+func (c *Command) parseFlags(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return args, nil
+	}
+
+	var flags, others []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			others = append(others, args[i+1:]...)
+			break
+		}
+
+		if len(arg) < 2 || arg[0] != '-' {
+			// The remaining arguments belong to the subcommand
+			if c.stopsAt(arg, len(others) == 0) {
+				others = append(others, args[i:]...)
+				break
+			}
+			others = append(others, arg)
+			continue
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		if j := strings.Index(name, "="); j >= 0 {
+			name = name[:j]
+		}
+
+		// Expand shorthands, unless a long flag is given with one dash
+		if !strings.HasPrefix(arg, "--") && c.flags.Lookup(name) == nil {
+			if expanded, consumed, ok := c.expandShorts(arg[1:], args[i+1:]); ok {
+				flags = append(flags, expanded...)
+				i += consumed
+				continue
+			}
+		}
+
+		// Keep the value of the flag with it
+		flags = append(flags, arg)
+		if f := c.flags.Lookup(name); f != nil && !isBoolFlag(f) && !strings.Contains(arg, "=") && i+1 < len(args) {
+			flags = append(flags, args[i+1])
+			i++
+		}
+	}
+
+	err := c.flags.Parse(append(append(flags, "--"), others...))
+	return c.flags.Args(), err
+}
+
+// stopsAt reports whether the flags are not parsed after the argument:
+// before a subcommand, or an unknown one when the command has no action
+func (c *Command) stopsAt(arg string, first bool) bool {
+	if !first {
+		return false
+	}
+	if _, ok := c.subCommandsMap[arg]; ok {
+		return true
+	}
+
+	return len(c.subCommands) > 0 && c.actionCallback == nil && c.contextAction == nil
+}
+
+// expandShorts returns the long flags of combined shorthands and how many following arguments were consumed
+func (c *Command) expandShorts(shorts string, next []string) ([]string, int, bool) {
+	runes := []rune(shorts)
+	if len(runes) == 0 {
+		return nil, 0, false
+	}
+	if _, ok := c.shorts[runes[0]]; !ok {
+		return nil, 0, false
+	}
+
+	var flags []string
+	for i, r := range runes {
+		name, ok := c.shorts[r]
+		if !ok {
+			// Let the flag set report the unknown flag
+			return append(flags, "-"+string(runes[i:])), 0, true
+		}
+
+		f := c.flags.Lookup(name)
+		if isBoolFlag(f) {
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				return append(flags, "--"+name+string(runes[i+1:])), 0, true
+			}
+			flags = append(flags, "--"+name)
+			continue
+		}
+
+		// The rest is the value, or the next argument
+		value := strings.TrimPrefix(string(runes[i+1:]), "=")
+		if i+1 < len(runes) {
+			return append(flags, "--"+name+"="+value), 0, true
+		}
+		if len(next) > 0 {
+			return append(flags, "--"+name+"="+next[0]), 1, true
+		}
+		return append(flags, "--"+name), 0, true
+	}
+
+	return flags, 0, true
+}
@@ -0,0 +1,115 @@
+package kli
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestShortFlags(t *testing.T) {
+	var verbose, force bool
+	var count int
+	var name string
+	var args []string
+
+	cli := NewCli("test", "description", "0")
+	cli.BoolFlag("verbose", "Verbose", &verbose).Short("verbose", 'v').
+		BoolFlag("force", "Force", &force).Short("force", 'f').
+		IntFlag("count", "Count", &count).Short("count", 'n').
+		StringFlag("name", "Name", &name)
+	cli.ContextAction(func(ctx *Context) error {
+		args = ctx.Args
+		return nil
+	})
+
+	for _, test := range []struct {
+		args    []string
+		verbose bool
+		force   bool
+		count   int
+		name    string
+		others  []string
+	}{
+		{[]string{"-v"}, true, false, 0, "", nil},
+		{[]string{"-vf", "-n", "3"}, true, true, 3, "", nil},
+		{[]string{"-fn5"}, false, true, 5, "", nil},
+		{[]string{"-n=7", "--verbose"}, true, false, 7, "", nil},
+		{[]string{"a", "--name", "b", "c", "-v"}, true, false, 0, "b", []string{"a", "c"}},
+		{[]string{"a", "--", "-v"}, false, false, 0, "", []string{"a", "-v"}},
+		{[]string{"-name=x", "a"}, false, false, 0, "x", []string{"a"}},
+	} {
+		verbose, force, count, name, args = false, false, 0, "", nil
+		if err := cli.Run(test.args...); err != nil {
+			t.Errorf("unexpected error %v for %v", err, test.args)
+			continue
+		}
+		if verbose != test.verbose || force != test.force || count != test.count || name != test.name {
+			t.Errorf("unexpected values %t, %t, %d, %q for %v", verbose, force, count, name, test.args)
+		}
+		if len(args) != 0 || len(test.others) != 0 {
+			if !reflect.DeepEqual(args, test.others) {
+				t.Errorf("unexpected args %v for %v", args, test.args)
+			}
+		}
+	}
+
+	err := cli.Run("-vx")
+	if err == nil || err.Error() != "flag provided but not defined: -x" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestShortPanics(t *testing.T) {
+	var verbose bool
+	cli := NewCli("test", "description", "0")
+	cli.BoolFlag("verbose", "Verbose", &verbose).Short("verbose", 'v')
+
+	for _, short := range []func(){
+		func() { cli.Short("unknown", 'u') },
+		func() { cli.Short("help", 'v') },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected a panic")
+				}
+			}()
+			short()
+		}()
+	}
+}
+
+func TestSubCommandFlags(t *testing.T) {
+	var verbose, force bool
+	var args []string
+
+	cli := NewCli("test", "description", "0")
+	cli.BoolFlag("verbose", "Verbose", &verbose).Short("verbose", 'v')
+	cli.NewSubCommand("delete", "Delete").
+		BoolFlag("force", "Force", &force).Short("force", 'f').
+		ContextAction(func(ctx *Context) error {
+			args = ctx.Args
+			return nil
+		})
+
+	if err := cli.Run("-v", "delete", "item", "-f"); err != nil {
+		t.Fatal(err)
+	}
+	if !verbose || !force || !reflect.DeepEqual(args, []string{"item"}) {
+		t.Errorf("unexpected values %t, %t, %v", verbose, force, args)
+	}
+
+	// Flags of the subcommand are not parsed by the root command
+	var out, errOut bytes.Buffer
+	cli.SetOutput(&out).SetErrOutput(&errOut)
+	err := cli.Run("-f", "delete")
+	if err == nil || err.Error() != "flag provided but not defined: -f" {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	out.Reset()
+	cli.rootCommand.complete([]string{"delete", "item", "-"}, &out)
+	if out.String() != "-force\tForce\n-help\tGet help on the 'test delete' command.\n" {
+		t.Errorf("unexpected completion %q", out.String())
+	}
+}
@@ -0,0 +1,162 @@
+package kli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ErrNotInteractive is returned by the prompts when no answer can be read and there is no default
+var ErrNotInteractive = errors.New("Cannot prompt, the input is not a terminal")
+
+// SetInput sets the reader of the prompts answers, os.Stdin by default
+func (c *Cli) SetInput(r io.Reader) *Cli {
+	c.input = r
+	c.inputReader = nil
+	return c
+}
+
+// Input returns the reader of the prompts answers
+func (c *Cli) Input() io.Reader {
+	if c.input == nil {
+		return os.Stdin
+	}
+	return c.input
+}
+
+// Interactive reports whether the input is a terminal.
+// Otherwise the prompts read the answers line by line, e.g. piped by a script, and fall back to their default
+func (c *Cli) Interactive() bool {
+	f, ok := c.Input().(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// AddYesFlag adds the -yes flag to the root command, answering yes to the confirmations
+func (c *Cli) AddYesFlag() *Cli {
+	c.BoolFlag("yes", "Answer yes to the confirmations", &c.assumeYes)
+	c.Short("yes", 'y')
+	return c
+}
+
+// Confirm asks a yes or no question and returns the answer,
+// true if the -yes flag is given and the default on an empty answer or without input
+func (c *Cli) Confirm(question string, def bool) (bool, error) {
+	if c.assumeYes {
+		return true, nil
+	}
+
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+	for {
+		answer, err := c.readLine(fmt.Sprintf("%s %s ", question, hint))
+		if errors.Is(err, io.EOF) {
+			return def, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		if !c.Interactive() {
+			return false, fmt.Errorf("Invalid answer %q, expected yes or no", answer)
+		}
+		fmt.Fprintln(c.ErrOutput(), "Please answer yes or no.")
+	}
+}
+
+// Secret asks for a secret, e.g. a password, without echoing it on a terminal
+func (c *Cli) Secret(prompt string) (string, error) {
+	if !c.Interactive() {
+		secret, err := c.readLine(prompt + ": ")
+		if errors.Is(err, io.EOF) {
+			return "", ErrNotInteractive
+		}
+		return secret, err
+	}
+
+	fmt.Fprint(c.ErrOutput(), prompt+": ")
+	secret, err := term.ReadPassword(int(c.Input().(*os.File).Fd()))
+	fmt.Fprintln(c.ErrOutput())
+	return string(secret), err
+}
+
+// Select asks to choose one of the options by number or name and returns its index,
+// def is the index returned on an empty answer or without input, -1 for none
+func (c *Cli) Select(prompt string, options []string, def int) (int, error) {
+	if len(options) == 0 {
+		return -1, errors.New("No option to select")
+	}
+
+	fmt.Fprintln(c.ErrOutput(), prompt)
+	for i, option := range options {
+		fmt.Fprintf(c.ErrOutput(), "  %d) %s\n", i+1, option)
+	}
+	hasDefault := def >= 0 && def < len(options)
+	question := fmt.Sprintf("Choose [1-%d]: ", len(options))
+	if hasDefault {
+		question = fmt.Sprintf("Choose [1-%d, default %d]: ", len(options), def+1)
+	}
+
+	for {
+		answer, err := c.readLine(question)
+		if errors.Is(err, io.EOF) {
+			if hasDefault {
+				return def, nil
+			}
+			return -1, ErrNotInteractive
+		}
+		if err != nil {
+			return -1, err
+		}
+		if answer == "" && hasDefault {
+			return def, nil
+		}
+
+		if i, err := strconv.Atoi(answer); err == nil && i >= 1 && i <= len(options) {
+			return i - 1, nil
+		}
+		for i, option := range options {
+			if answer == option {
+				return i, nil
+			}
+		}
+		if !c.Interactive() {
+			return -1, fmt.Errorf("Invalid choice %q", answer)
+		}
+		fmt.Fprintf(c.ErrOutput(), "Please choose a number between 1 and %d.\n", len(options))
+	}
+}
+
+// readLine prints the prompt on the error output, so that it does not mix with the output of the command,
+// and reads a line of the input
+func (c *Cli) readLine(prompt string) (string, error) {
+	fmt.Fprint(c.ErrOutput(), prompt)
+	if c.inputReader == nil {
+		c.inputReader = bufio.NewReader(c.Input())
+	}
+
+	line, err := c.inputReader.ReadString('\n')
+	if !c.Interactive() {
+		// Answers read from a pipe are not echoed
+		fmt.Fprintln(c.ErrOutput())
+	}
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
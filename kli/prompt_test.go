@@ -0,0 +1,114 @@
+package kli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func newPromptCli(input string) (*Cli, *bytes.Buffer) {
+	var errOut bytes.Buffer
+	cli := NewCli("test", "description", "0")
+	cli.SetInput(strings.NewReader(input)).SetErrOutput(&errOut)
+	return cli, &errOut
+}
+
+func TestConfirm(t *testing.T) {
+	for _, test := range []struct {
+		input    string
+		def      bool
+		expected bool
+	}{
+		{"y\n", false, true},
+		{"YES\n", false, true},
+		{"n\n", true, false},
+		{"\n", true, true},
+		{"\n", false, false},
+		{"", true, true},
+		{"", false, false},
+		{"yes", false, true},
+	} {
+		cli, _ := newPromptCli(test.input)
+		answer, err := cli.Confirm("Delete the wallet?", test.def)
+		if err != nil {
+			t.Errorf("unexpected error %v for %q", err, test.input)
+		}
+		if answer != test.expected {
+			t.Errorf("unexpected answer %t for %q", answer, test.input)
+		}
+	}
+
+	cli, errOut := newPromptCli("maybe\n")
+	if _, err := cli.Confirm("Delete the wallet?", false); err == nil || err.Error() != `Invalid answer "maybe", expected yes or no` {
+		t.Errorf("unexpected error %v", err)
+	}
+	if errOut.String() != "Delete the wallet? [y/N] \n" {
+		t.Errorf("unexpected prompt %q", errOut.String())
+	}
+}
+
+func TestYesFlag(t *testing.T) {
+	cli, errOut := newPromptCli("n\n")
+	cli.AddYesFlag()
+
+	var answer bool
+	cli.Action(func() (err error) {
+		answer, err = cli.Confirm("Delete the wallet?", false)
+		return err
+	})
+
+	if err := cli.Run("-y"); err != nil {
+		t.Fatal(err)
+	}
+	if !answer || errOut.Len() != 0 {
+		t.Errorf("unexpected answer %t, prompt %q", answer, errOut.String())
+	}
+}
+
+func TestSecret(t *testing.T) {
+	cli, _ := newPromptCli("s3cr3t\nother\n")
+	secret, err := cli.Secret("Vault token")
+	if err != nil || secret != "s3cr3t" {
+		t.Errorf("unexpected secret %q, %v", secret, err)
+	}
+	if cli.Interactive() {
+		t.Error("unexpected interactive input")
+	}
+
+	cli, _ = newPromptCli("")
+	if _, err := cli.Secret("Vault token"); err != ErrNotInteractive {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	options := []string{"mainnet", "testnet", "devnet"}
+	for _, test := range []struct {
+		input    string
+		def      int
+		expected int
+		err      string
+	}{
+		{"2\n", -1, 1, ""},
+		{"devnet\n", -1, 2, ""},
+		{"\n", 0, 0, ""},
+		{"", 1, 1, ""},
+		{"", -1, -1, ErrNotInteractive.Error()},
+		{"4\n", 0, -1, `Invalid choice "4"`},
+		{"\n", -1, -1, `Invalid choice ""`},
+	} {
+		cli, _ := newPromptCli(test.input)
+		index, err := cli.Select("Network", options, test.def)
+		if index != test.expected || (err == nil) != (test.err == "") || (err != nil && err.Error() != test.err) {
+			t.Errorf("unexpected index %d, %v for %q", index, err, test.input)
+		}
+	}
+
+	cli, errOut := newPromptCli("1\n")
+	if _, err := cli.Select("Network", options, 1); err != nil {
+		t.Fatal(err)
+	}
+	if errOut.String() != "Network\n  1) mainnet\n  2) testnet\n  3) devnet\nChoose [1-3, default 2]: \n" {
+		t.Errorf("unexpected prompt %q", errOut.String())
+	}
+}
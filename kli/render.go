@@ -0,0 +1,168 @@
+package kli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formats of the -output flag
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+)
+
+// Describer is implemented by the values rendered as a table with their own columns, like doc.Interface
+type Describer interface {
+	Describe() ([]string, [][]string, error)
+}
+
+// AddOutputFlag adds the -output flag to the root command, choosing the format of Render
+func (c *Cli) AddOutputFlag() *Cli {
+	c.outputFormat = FormatTable
+	c.EnumFlag("output", "Output format", []string{FormatTable, FormatJSON, FormatYAML}, &c.outputFormat)
+	return c
+}
+
+// OutputFormat returns the format chosen with the -output flag, table by default
+func (c *Cli) OutputFormat() string {
+	if c.outputFormat == "" {
+		return FormatTable
+	}
+	return c.outputFormat
+}
+
+// Render writes a result to the output in the format chosen with the -output flag
+func (c *Cli) Render(v interface{}) error {
+	return Render(c.Output(), c.OutputFormat(), v)
+}
+
+// Render writes a value as a table, JSON or YAML.
+// Tables are built from Describer values, slices of structs with a column per field, structs and maps
+// with a row per field or key, other values are printed as is
+func Render(w io.Writer, format string, v interface{}) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case FormatTable, "":
+		keys, rows, err := table(v)
+		if err != nil {
+			return err
+		}
+		if keys == nil {
+			_, err := fmt.Fprintln(w, v)
+			return err
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(keys, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("Unsupported format %s, accept only table, json and yaml", format)
+	}
+}
+
+// table returns the columns and rows of a value, no columns if it is not tabular
+func table(v interface{}) ([]string, [][]string, error) {
+	if d, ok := v.(Describer); ok {
+		return d.Describe()
+	}
+
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		elem := value.Type().Elem()
+		for elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		if elem.Kind() != reflect.Struct {
+			rows := make([][]string, value.Len())
+			for i := range rows {
+				rows[i] = []string{cell(value.Index(i))}
+			}
+			return []string{"Value"}, rows, nil
+		}
+
+		fields := columns(elem)
+		keys := make([]string, len(fields))
+		for i, f := range fields {
+			keys[i] = elem.Field(f).Name
+		}
+		rows := make([][]string, value.Len())
+		for i := range rows {
+			item := reflect.Indirect(value.Index(i))
+			rows[i] = make([]string, len(fields))
+			for j, f := range fields {
+				if item.IsValid() {
+					rows[i][j] = cell(item.Field(f))
+				}
+			}
+		}
+		return keys, rows, nil
+
+	case reflect.Struct:
+		var rows [][]string
+		for _, f := range columns(value.Type()) {
+			rows = append(rows, []string{value.Type().Field(f).Name, cell(value.Field(f))})
+		}
+		return []string{"Field", "Value"}, rows, nil
+
+	case reflect.Map:
+		var rows [][]string
+		iter := value.MapRange()
+		for iter.Next() {
+			rows = append(rows, []string{cell(iter.Key()), cell(iter.Value())})
+		}
+		sort.Slice(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+		return []string{"Key", "Value"}, rows, nil
+	}
+
+	return nil, nil, nil
+}
+
+// columns returns the indexes of the exported fields of a struct not ignored in JSON
+func columns(t reflect.Type) []int {
+	var fields []int
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || f.Tag.Get("json") == "-" {
+			continue
+		}
+		fields = append(fields, i)
+	}
+	return fields
+}
+
+// cell formats a value of a table, nil pointers are empty
+func cell(v reflect.Value) string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,84 @@
+package kli
+
+import (
+	"bytes"
+	"testing"
+)
+
+type renderedWallet struct {
+	Name     string  `json:"name" yaml:"name"`
+	Currency string  `json:"currency" yaml:"currency"`
+	Balance  *string `json:"balance,omitempty" yaml:"balance,omitempty"`
+	secret   string
+	Internal string `json:"-" yaml:"-"`
+}
+
+type renderedTable struct{}
+
+func (renderedTable) Describe() ([]string, [][]string, error) {
+	return []string{"Market", "State"}, [][]string{{"btcusd", "enabled"}}, nil
+}
+
+func TestRender(t *testing.T) {
+	balance := "1.5"
+	wallets := []*renderedWallet{
+		{Name: "hot", Currency: "btc", Balance: &balance, secret: "x", Internal: "y"},
+		{Name: "cold-storage", Currency: "eth"},
+	}
+
+	for _, test := range []struct {
+		format   string
+		value    interface{}
+		expected string
+	}{
+		{FormatTable, wallets, "Name          Currency  Balance\nhot           btc       1.5\ncold-storage  eth       \n"},
+		{FormatTable, wallets[1], "Field     Value\nName      cold-storage\nCurrency  eth\nBalance   \n"},
+		{FormatTable, map[string]int{"b": 2, "a": 1}, "Key  Value\na    1\nb    2\n"},
+		{FormatTable, []string{"btc", "eth"}, "Value\nbtc\neth\n"},
+		{FormatTable, renderedTable{}, "Market  State\nbtcusd  enabled\n"},
+		{FormatTable, "done", "done\n"},
+		{FormatJSON, wallets[1], "{\n  \"name\": \"cold-storage\",\n  \"currency\": \"eth\"\n}\n"},
+		{FormatYAML, wallets, "- name: hot\n  currency: btc\n  balance: \"1.5\"\n- name: cold-storage\n  currency: eth\n"},
+	} {
+		var out bytes.Buffer
+		if err := Render(&out, test.format, test.value); err != nil {
+			t.Errorf("unexpected error %v for %s", err, test.format)
+			continue
+		}
+		if out.String() != test.expected {
+			t.Errorf("unexpected %s output %q", test.format, out.String())
+		}
+	}
+
+	if err := Render(&bytes.Buffer{}, "xml", wallets); err == nil || err.Error() != "Unsupported format xml, accept only table, json and yaml" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestOutputFlag(t *testing.T) {
+	var out bytes.Buffer
+	cli := NewCli("test", "description", "0")
+	cli.SetOutput(&out).AddOutputFlag()
+	cli.Action(func() error {
+		return cli.Render(map[string]string{"state": "enabled"})
+	})
+
+	if err := cli.Run("-output", "json"); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "{\n  \"state\": \"enabled\"\n}\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+
+	out.Reset()
+	if err := cli.Run("--output=yaml"); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "state: enabled\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+
+	if err := cli.Run("-output", "xml"); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,111 @@
+package kli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Aliases - Sets other names of the command, e.g. ls for list
+func (c *Command) Aliases(aliases ...string) *Command {
+	c.aliases = append(c.aliases, aliases...)
+	if c.parent != nil {
+		for _, alias := range aliases {
+			c.parent.subCommandsMap[alias] = c
+		}
+	}
+	return c
+}
+
+// unknownCommandError returns the error of an unknown subcommand, suggesting the closest ones
+func (c *Command) unknownCommandError(name string) error {
+	suggestions := c.suggest(name)
+	if len(suggestions) == 0 {
+		return fmt.Errorf("Unknown command %q for %q", name, c.commandPath)
+	}
+
+	quoted := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	return fmt.Errorf("Unknown command %q for %q, did you mean %s?", name, c.commandPath, strings.Join(quoted, " or "))
+}
+
+// suggest returns the visible subcommands whose name or alias is close to name, closest first
+func (c *Command) suggest(name string) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+
+	// Allow a typo every 3 characters, at least one
+	max := len(name) / 3
+	if max < 1 {
+		max = 1
+	}
+
+	var candidates []candidate
+	for _, subcommand := range c.subCommands {
+		if subcommand.isHidden() {
+			continue
+		}
+
+		best := -1
+		for _, n := range append([]string{subcommand.name}, subcommand.aliases...) {
+			d := distance(strings.ToLower(name), strings.ToLower(n))
+			if strings.HasPrefix(n, name) {
+				d = 0
+			}
+			if best < 0 || d < best {
+				best = d
+			}
+		}
+		if best <= max {
+			candidates = append(candidates, candidate{subcommand.name, best})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+	names := make([]string, len(candidates))
+	for i, c := range candidates {
+		names[i] = c.name
+	}
+	return names
+}
+
+// distance returns the Levenshtein distance between two strings
+func distance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	row := make([]int, len(t)+1)
+	for j := range row {
+		row[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		prev := row[0]
+		row[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			current := row[j]
+			row[j] = minInt(row[j]+1, row[j-1]+1, prev+cost)
+			prev = current
+		}
+	}
+
+	return row[len(t)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,71 @@
+package kli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAliases(t *testing.T) {
+	called := ""
+	cli := NewCli("test", "description", "0")
+	apps := cli.NewSubCommand("apps", "Manage applications").Aliases("app", "a")
+	apps.NewSubCommand("list", "List applications").Aliases("ls").
+		Action(func() error {
+			called = "list"
+			return nil
+		})
+
+	for _, args := range [][]string{{"apps", "list"}, {"app", "ls"}, {"a", "list"}} {
+		called = ""
+		if err := cli.Run(args...); err != nil {
+			t.Fatalf("unexpected error %v for %v", err, args)
+		}
+		if called != "list" {
+			t.Errorf("list not called for %v", args)
+		}
+	}
+
+	var out, errOut bytes.Buffer
+	cli.SetOutput(&out).SetErrOutput(&errOut)
+	if err := cli.Run("-help"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "(aliases: app, a)") {
+		t.Errorf("help does not contain the aliases:\n%s", out.String())
+	}
+	if info := cli.Info(); len(info.Commands) != 1 || strings.Join(info.Commands[0].Aliases, ",") != "app,a" {
+		t.Errorf("unexpected info %+v", info)
+	}
+}
+
+func TestSuggestions(t *testing.T) {
+	var out, errOut bytes.Buffer
+	cli := NewCli("test", "description", "0")
+	cli.SetOutput(&out).SetErrOutput(&errOut)
+	cli.NewSubCommand("status", "Show the status")
+	cli.NewSubCommand("start", "Start the service").Aliases("up")
+	cli.NewSubCommand("stop", "Stop the service")
+	cli.NewSubCommand("secret", "Secret").Hidden()
+
+	for name, expected := range map[string]string{
+		"stauts": `Unknown command "stauts" for "test", did you mean "status" or "start"?`,
+		"sto":    `Unknown command "sto" for "test", did you mean "stop"?`,
+		"upp":    `Unknown command "upp" for "test", did you mean "start"?`,
+		"st":     `Unknown command "st" for "test", did you mean "status" or "start" or "stop"?`,
+		"secre":  `Unknown command "secre" for "test"`,
+		"deploy": `Unknown command "deploy" for "test"`,
+	} {
+		errOut.Reset()
+		err := cli.Run(name)
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error %v for %s", err, name)
+		}
+		if ExitCode(err) != ExitUsage {
+			t.Errorf("unexpected exit code %d for %s", ExitCode(err), name)
+		}
+		if errOut.String() != "Error: "+expected+"\n\n" {
+			t.Errorf("unexpected errors %q for %s", errOut.String(), name)
+		}
+	}
+}
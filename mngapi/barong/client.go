@@ -1,8 +1,7 @@
 package barong
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
 	"net/http"
 
 	"github.com/openware/pkg/mngapi"
@@ -25,72 +24,228 @@ func New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey string) (*Client, error) {
 	}, nil
 }
 
-// CreateServiceAccount call barong management api to create new service account
-func (b *Client) CreateServiceAccount(params CreateServiceAccountParams) (*ServiceAccount, *mngapi.APIError) {
-	res, apiError := b.mngapiClient.Request(http.MethodPost, "service_accounts/create", params)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	serviceAccount := &ServiceAccount{}
-	err := json.Unmarshal([]byte(res), serviceAccount)
+// NewWithSigners return barong management api client signing requests with every signer
+func NewWithSigners(URL, jwtIssuer string, signers ...mngapi.JWSSigner) (*Client, error) {
+	client, err := mngapi.NewWithSigners(URL, jwtIssuer, signers...)
 	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
+		return nil, err
 	}
 
-	return serviceAccount, nil
+	return &Client{
+		mngapiClient: client,
+	}, nil
+}
+
+// CreateServiceAccount call barong management api to create new service account
+func (b *Client) CreateServiceAccount(params CreateServiceAccountParams) (*ServiceAccount, *mngapi.APIError) {
+	serviceAccount, err := b.CreateServiceAccountContext(context.Background(), params)
+	return serviceAccount, mngapi.ToAPIError(err)
+}
+
+// CreateServiceAccountContext is like CreateServiceAccount, the request is cancelled with the context
+func (b *Client) CreateServiceAccountContext(ctx context.Context, params CreateServiceAccountParams) (*ServiceAccount, error) {
+	return mngapi.Do[*ServiceAccount](ctx, b.mngapiClient, http.MethodPost, "service_accounts/create", params)
 }
 
 // CreateAPIKey calls Barong Management Api to create a new API key for a given
 func (b *Client) CreateAPIKey(params CreateAPIKeyParams) (*APIKey, *mngapi.APIError) {
-	res, apiError := b.mngapiClient.Request(http.MethodPost, "api_keys", params)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	apiKey := &APIKey{}
-	err := json.Unmarshal([]byte(res), apiKey)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
+	apiKey, err := b.CreateAPIKeyContext(context.Background(), params)
+	return apiKey, mngapi.ToAPIError(err)
+}
 
-	return apiKey, nil
+// CreateAPIKeyContext is like CreateAPIKey, the request is cancelled with the context
+func (b *Client) CreateAPIKeyContext(ctx context.Context, params CreateAPIKeyParams) (*APIKey, error) {
+	return mngapi.Do[*APIKey](ctx, b.mngapiClient, http.MethodPost, "api_keys", params)
 }
 
 // DeleteServiceAccountByUID call barong management api to delete service account by uid
 func (b *Client) DeleteServiceAccountByUID(uid string) (*ServiceAccount, *mngapi.APIError) {
+	serviceAccount, err := b.DeleteServiceAccountByUIDContext(context.Background(), uid)
+	return serviceAccount, mngapi.ToAPIError(err)
+}
+
+// DeleteServiceAccountByUIDContext is like DeleteServiceAccountByUID, the request is cancelled with the context
+func (b *Client) DeleteServiceAccountByUIDContext(ctx context.Context, uid string) (*ServiceAccount, error) {
 	// Build parameters
 	params := map[string]interface{}{
 		"uid": uid,
 	}
 
-	res, apiError := b.mngapiClient.Request(http.MethodPost, "service_accounts/delete", params)
-	if apiError != nil {
-		return nil, apiError
-	}
+	return mngapi.Do[*ServiceAccount](ctx, b.mngapiClient, http.MethodPost, "service_accounts/delete", params)
+}
 
-	serviceAccount := &ServiceAccount{}
-	err := json.Unmarshal([]byte(res), serviceAccount)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
+// CreateAttachment call barong management api to create new attachment
+func (b *Client) CreateAttachment(params CreateAttachmentParams) (*Attachment, *mngapi.APIError) {
+	attachment, err := b.CreateAttachmentContext(context.Background(), params)
+	return attachment, mngapi.ToAPIError(err)
+}
 
-	return serviceAccount, nil
+// CreateAttachmentContext is like CreateAttachment, the request is cancelled with the context
+func (b *Client) CreateAttachmentContext(ctx context.Context, params CreateAttachmentParams) (*Attachment, error) {
+	return mngapi.Do[*Attachment](ctx, b.mngapiClient, http.MethodPost, "attachments", params)
 }
 
-// CreateAPIKey calls Barong Management Api to create a new API key for a given
-func (b *Client) CreateAttachment(params CreateAttachmentParams) (*Attachment, *mngapi.APIError) {
-	res, apiError := b.mngapiClient.Request(http.MethodPost, "attachments", params)
-	if apiError != nil {
-		return nil, apiError
-	}
+// GetUser call barong management api to get a user with its KYC data by uid, email or phone number
+func (b *Client) GetUser(params GetUserParams) (*UserWithKYC, *mngapi.APIError) {
+	user, err := b.GetUserContext(context.Background(), params)
+	return user, mngapi.ToAPIError(err)
+}
 
-	attachment := &Attachment{}
-	err := json.Unmarshal([]byte(res), attachment)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
+// GetUserContext is like GetUser, the request is cancelled with the context
+func (b *Client) GetUserContext(ctx context.Context, params GetUserParams) (*UserWithKYC, error) {
+	return mngapi.Do[*UserWithKYC](ctx, b.mngapiClient, http.MethodPost, "users/get", params, mngapi.Idempotent())
+}
+
+// GetUsers call barong management api to get users as paginated collection
+func (b *Client) GetUsers(params GetUsersParams) ([]*User, *mngapi.APIError) {
+	users, err := b.GetUsersContext(context.Background(), params)
+	return users, mngapi.ToAPIError(err)
+}
+
+// GetUsersContext is like GetUsers, the request is cancelled with the context
+func (b *Client) GetUsersContext(ctx context.Context, params GetUsersParams) ([]*User, error) {
+	return mngapi.Do[[]*User](ctx, b.mngapiClient, http.MethodPost, "users/list", params, mngapi.Idempotent())
+}
+
+// UpdateUser call barong management api to update the state, role or level of a user
+func (b *Client) UpdateUser(params UpdateUserParams) (*User, *mngapi.APIError) {
+	user, err := b.UpdateUserContext(context.Background(), params)
+	return user, mngapi.ToAPIError(err)
+}
+
+// UpdateUserContext is like UpdateUser, the request is cancelled with the context
+func (b *Client) UpdateUserContext(ctx context.Context, params UpdateUserParams) (*User, error) {
+	return mngapi.Do[*User](ctx, b.mngapiClient, http.MethodPost, "users/update", params, mngapi.Idempotent())
+}
+
+// GetLabels call barong management api to get the labels of a user
+func (b *Client) GetLabels(uid string) ([]*Label, *mngapi.APIError) {
+	labels, err := b.GetLabelsContext(context.Background(), uid)
+	return labels, mngapi.ToAPIError(err)
+}
+
+// GetLabelsContext is like GetLabels, the request is cancelled with the context
+func (b *Client) GetLabelsContext(ctx context.Context, uid string) ([]*Label, error) {
+	return mngapi.Do[[]*Label](ctx, b.mngapiClient, http.MethodPost, "labels/list", map[string]interface{}{"user_uid": uid}, mngapi.Idempotent())
+}
+
+// CreateLabel call barong management api to create new user label
+func (b *Client) CreateLabel(params LabelParams) (*Label, *mngapi.APIError) {
+	label, err := b.CreateLabelContext(context.Background(), params)
+	return label, mngapi.ToAPIError(err)
+}
+
+// CreateLabelContext is like CreateLabel, the request is cancelled with the context
+func (b *Client) CreateLabelContext(ctx context.Context, params LabelParams) (*Label, error) {
+	return mngapi.Do[*Label](ctx, b.mngapiClient, http.MethodPost, "labels", params)
+}
+
+// UpdateLabel call barong management api to update a user label
+func (b *Client) UpdateLabel(params LabelParams) (*Label, *mngapi.APIError) {
+	label, err := b.UpdateLabelContext(context.Background(), params)
+	return label, mngapi.ToAPIError(err)
+}
+
+// UpdateLabelContext is like UpdateLabel, the request is cancelled with the context
+func (b *Client) UpdateLabelContext(ctx context.Context, params LabelParams) (*Label, error) {
+	return mngapi.Do[*Label](ctx, b.mngapiClient, http.MethodPut, "labels", params, mngapi.Idempotent())
+}
+
+// DeleteLabel call barong management api to delete a user label
+func (b *Client) DeleteLabel(params DeleteLabelParams) (*Label, *mngapi.APIError) {
+	label, err := b.DeleteLabelContext(context.Background(), params)
+	return label, mngapi.ToAPIError(err)
+}
+
+// DeleteLabelContext is like DeleteLabel, the request is cancelled with the context
+func (b *Client) DeleteLabelContext(ctx context.Context, params DeleteLabelParams) (*Label, error) {
+	return mngapi.Do[*Label](ctx, b.mngapiClient, http.MethodPost, "labels/delete", params)
+}
+
+// CreateProfile call barong management api to create new user profile
+func (b *Client) CreateProfile(params CreateProfileParams) (*Profile, *mngapi.APIError) {
+	profile, err := b.CreateProfileContext(context.Background(), params)
+	return profile, mngapi.ToAPIError(err)
+}
+
+// CreateProfileContext is like CreateProfile, the request is cancelled with the context
+func (b *Client) CreateProfileContext(ctx context.Context, params CreateProfileParams) (*Profile, error) {
+	return mngapi.Do[*Profile](ctx, b.mngapiClient, http.MethodPost, "profiles", params)
+}
+
+// GetPhones call barong management api to get the phone numbers of a user
+func (b *Client) GetPhones(uid string) ([]*Phone, *mngapi.APIError) {
+	phones, err := b.GetPhonesContext(context.Background(), uid)
+	return phones, mngapi.ToAPIError(err)
+}
+
+// GetPhonesContext is like GetPhones, the request is cancelled with the context
+func (b *Client) GetPhonesContext(ctx context.Context, uid string) ([]*Phone, error) {
+	return mngapi.Do[[]*Phone](ctx, b.mngapiClient, http.MethodPost, "phones/get", map[string]interface{}{"uid": uid}, mngapi.Idempotent())
+}
+
+// CreatePhone call barong management api to add a phone number to a user
+func (b *Client) CreatePhone(params CreatePhoneParams) (*Phone, *mngapi.APIError) {
+	phone, err := b.CreatePhoneContext(context.Background(), params)
+	return phone, mngapi.ToAPIError(err)
+}
+
+// CreatePhoneContext is like CreatePhone, the request is cancelled with the context
+func (b *Client) CreatePhoneContext(ctx context.Context, params CreatePhoneParams) (*Phone, error) {
+	return mngapi.Do[*Phone](ctx, b.mngapiClient, http.MethodPost, "phones", params)
+}
+
+// GetDocuments call barong management api to get the documents of a user as paginated collection
+func (b *Client) GetDocuments(params GetDocumentsParams) ([]*Document, *mngapi.APIError) {
+	documents, err := b.GetDocumentsContext(context.Background(), params)
+	return documents, mngapi.ToAPIError(err)
+}
+
+// GetDocumentsContext is like GetDocuments, the request is cancelled with the context
+func (b *Client) GetDocumentsContext(ctx context.Context, params GetDocumentsParams) ([]*Document, error) {
+	return mngapi.Do[[]*Document](ctx, b.mngapiClient, http.MethodPost, "documents/list", params, mngapi.Idempotent())
+}
+
+// GetAPIKeys call barong management api to get the API keys of a user as paginated collection
+func (b *Client) GetAPIKeys(params GetAPIKeysParams) ([]*APIKey, *mngapi.APIError) {
+	apiKeys, err := b.GetAPIKeysContext(context.Background(), params)
+	return apiKeys, mngapi.ToAPIError(err)
+}
+
+// GetAPIKeysContext is like GetAPIKeys, the request is cancelled with the context
+func (b *Client) GetAPIKeysContext(ctx context.Context, params GetAPIKeysParams) ([]*APIKey, error) {
+	return mngapi.Do[[]*APIKey](ctx, b.mngapiClient, http.MethodPost, "api_keys/list", params, mngapi.Idempotent())
+}
+
+// DeleteAPIKey call barong management api to revoke an API key
+func (b *Client) DeleteAPIKey(params DeleteAPIKeyParams) (*APIKey, *mngapi.APIError) {
+	apiKey, err := b.DeleteAPIKeyContext(context.Background(), params)
+	return apiKey, mngapi.ToAPIError(err)
+}
+
+// DeleteAPIKeyContext is like DeleteAPIKey, the request is cancelled with the context
+func (b *Client) DeleteAPIKeyContext(ctx context.Context, params DeleteAPIKeyParams) (*APIKey, error) {
+	return mngapi.Do[*APIKey](ctx, b.mngapiClient, http.MethodPost, "api_keys/delete", params)
+}
+
+// GetServiceAccounts call barong management api to get service accounts as paginated collection
+func (b *Client) GetServiceAccounts(params GetServiceAccountsParams) ([]*ServiceAccount, *mngapi.APIError) {
+	serviceAccounts, err := b.GetServiceAccountsContext(context.Background(), params)
+	return serviceAccounts, mngapi.ToAPIError(err)
+}
+
+// GetServiceAccountsContext is like GetServiceAccounts, the request is cancelled with the context
+func (b *Client) GetServiceAccountsContext(ctx context.Context, params GetServiceAccountsParams) ([]*ServiceAccount, error) {
+	return mngapi.Do[[]*ServiceAccount](ctx, b.mngapiClient, http.MethodPost, "service_accounts/list", params, mngapi.Idempotent())
+}
+
+// GetServiceAccountByUID call barong management api to get service account by uid
+func (b *Client) GetServiceAccountByUID(uid string) (*ServiceAccount, *mngapi.APIError) {
+	serviceAccount, err := b.GetServiceAccountByUIDContext(context.Background(), uid)
+	return serviceAccount, mngapi.ToAPIError(err)
+}
 
-	fmt.Println(attachment)
-	return attachment, nil
+// GetServiceAccountByUIDContext is like GetServiceAccountByUID, the request is cancelled with the context
+func (b *Client) GetServiceAccountByUIDContext(ctx context.Context, uid string) (*ServiceAccount, error) {
+	return mngapi.Do[*ServiceAccount](ctx, b.mngapiClient, http.MethodPost, "service_accounts/get", map[string]interface{}{"uid": uid}, mngapi.Idempotent())
 }
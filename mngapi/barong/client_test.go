@@ -1,6 +1,7 @@
 package barong
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -19,6 +20,9 @@ const (
 type MockClient struct {
 	response []byte
 	apiError *mngapi.APIError
+	method   string
+	path     string
+	body     interface{}
 }
 
 // Mock request function
@@ -26,6 +30,15 @@ func (m *MockClient) Request(method string, path string, body interface{}) ([]by
 	return m.response, m.apiError
 }
 
+// Mock request function with context
+func (m *MockClient) RequestContext(ctx context.Context, method string, path string, body interface{}, opts ...mngapi.RequestOption) ([]byte, error) {
+	m.method, m.path, m.body = method, path, body
+	if m.apiError != nil {
+		return nil, &mngapi.ResponseError{APIError: m.apiError}
+	}
+	return m.response, nil
+}
+
 func TestCreateNewClient(t *testing.T) {
 	t.Run("Success creation", func(t *testing.T) {
 		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
@@ -360,3 +373,226 @@ func TestCreateAttachment(t *testing.T) {
 		assert.Nil(t, apiKey)
 	})
 }
+
+func TestGetUser(t *testing.T) {
+	t.Run("Success response", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		expected := `{"email":"test@test.com","uid":"IDCA2AC08296","role":"member","level":2,"otp":false,"state":"active","referral_uid":"","data":"","labels":[{"key":"email","value":"verified","scope":"private","description":"","created_at":"2021-02-15T10:15:18Z","updated_at":"2021-02-15T10:15:18Z"}],"phones":[{"country":"FR","number":"33612345678","validated_at":"2021-02-15T10:15:18Z"}],"profiles":[{"first_name":"John","last_name":"Doe","dob":"1990-01-01","address":"1 rue de Rivoli","postcode":"75001","city":"Paris","country":"FR","state":"verified","metadata":"","created_at":"2021-02-15T10:15:18Z","updated_at":"2021-02-15T10:15:18Z"}],"documents":[],"created_at":"2021-02-15T10:15:18Z","updated_at":"2021-02-15T10:15:18Z"}`
+		mock := &MockClient{response: []byte(expected)}
+		client.mngapiClient = mock
+
+		user, apiError := client.GetUser(GetUserParams{Email: "test@test.com"})
+		assert.Nil(t, apiError)
+		assert.Equal(t, "users/get", mock.path)
+		assert.Equal(t, "IDCA2AC08296", user.UID)
+
+		result, err := json.Marshal(user)
+		assert.NoError(t, err)
+		assert.Equal(t, result, []byte(expected))
+	})
+
+	t.Run("Error record not found", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		client.mngapiClient = &MockClient{
+			apiError: &mngapi.APIError{StatusCode: 404, Error: "Couldn't find record."},
+		}
+
+		user, apiError := client.GetUser(GetUserParams{UID: "IDFFFFFFFFFF"})
+		assert.NotNil(t, apiError)
+		assert.Equal(t, apiError.StatusCode, 404)
+		assert.Equal(t, apiError.Error, "Couldn't find record.")
+		assert.Nil(t, user)
+	})
+}
+
+func TestGetUsers(t *testing.T) {
+	client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+	assert.NoError(t, err)
+
+	expected := `[{"email":"test@test.com","uid":"IDCA2AC08296","role":"member","level":2,"otp":false,"state":"active","referral_uid":"","data":""}]`
+	client.mngapiClient = &MockClient{response: []byte(expected)}
+
+	users, apiError := client.GetUsers(GetUsersParams{Page: 1, Limit: 10})
+	assert.Nil(t, apiError)
+
+	result, err := json.Marshal(users)
+	assert.NoError(t, err)
+	assert.Equal(t, result, []byte(expected))
+}
+
+func TestUpdateUser(t *testing.T) {
+	client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+	assert.NoError(t, err)
+
+	mock := &MockClient{response: []byte(`{"email":"test@test.com","uid":"IDCA2AC08296","role":"member","level":2,"otp":false,"state":"locked","referral_uid":"","data":""}`)}
+	client.mngapiClient = mock
+
+	user, apiError := client.UpdateUser(UpdateUserParams{UID: "IDCA2AC08296", State: "locked"})
+	assert.Nil(t, apiError)
+	assert.Equal(t, "locked", user.State)
+	assert.Equal(t, UpdateUserParams{UID: "IDCA2AC08296", State: "locked"}, mock.body)
+}
+
+func TestLabels(t *testing.T) {
+	expected := `{"key":"document","value":"verified","scope":"private","description":"","created_at":"2021-02-15T10:15:18Z","updated_at":"2021-02-15T10:15:18Z"}`
+	params := LabelParams{UserUID: "IDCA2AC08296", Key: "document", Value: "verified"}
+
+	t.Run("Create label", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		mock := &MockClient{response: []byte(expected)}
+		client.mngapiClient = mock
+
+		label, apiError := client.CreateLabel(params)
+		assert.Nil(t, apiError)
+		assert.Equal(t, "POST", mock.method)
+
+		result, err := json.Marshal(label)
+		assert.NoError(t, err)
+		assert.Equal(t, result, []byte(expected))
+	})
+
+	t.Run("Update label", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		mock := &MockClient{response: []byte(expected)}
+		client.mngapiClient = mock
+
+		_, apiError := client.UpdateLabel(params)
+		assert.Nil(t, apiError)
+		assert.Equal(t, "PUT", mock.method)
+		assert.Equal(t, "labels", mock.path)
+	})
+
+	t.Run("Delete label", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		mock := &MockClient{response: []byte(expected)}
+		client.mngapiClient = mock
+
+		_, apiError := client.DeleteLabel(DeleteLabelParams{UserUID: "IDCA2AC08296", Key: "document"})
+		assert.Nil(t, apiError)
+		assert.Equal(t, "labels/delete", mock.path)
+	})
+
+	t.Run("Get labels", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		mock := &MockClient{response: []byte("[" + expected + "]")}
+		client.mngapiClient = mock
+
+		labels, apiError := client.GetLabels("IDCA2AC08296")
+		assert.Nil(t, apiError)
+		assert.Len(t, labels, 1)
+		assert.Equal(t, map[string]interface{}{"user_uid": "IDCA2AC08296"}, mock.body)
+	})
+
+	t.Run("Error invalid json response during unmarshal", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		client.mngapiClient = &MockClient{response: []byte(`{""}`)}
+
+		labels, apiError := client.GetLabels("IDCA2AC08296")
+		assert.NotNil(t, apiError)
+		assert.Equal(t, apiError.StatusCode, 500)
+		assert.NotEmpty(t, apiError.Error)
+		assert.Nil(t, labels)
+	})
+}
+
+func TestProfileAndPhones(t *testing.T) {
+	t.Run("Create profile", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		expected := `{"first_name":"John","last_name":"Doe","dob":"1990-01-01","address":"","postcode":"","city":"Paris","country":"FR","state":"submitted","metadata":"","created_at":"2021-02-15T10:15:18Z","updated_at":"2021-02-15T10:15:18Z"}`
+		client.mngapiClient = &MockClient{response: []byte(expected)}
+
+		profile, apiError := client.CreateProfile(CreateProfileParams{UID: "IDCA2AC08296", FirstName: "John", LastName: "Doe"})
+		assert.Nil(t, apiError)
+
+		result, err := json.Marshal(profile)
+		assert.NoError(t, err)
+		assert.Equal(t, result, []byte(expected))
+	})
+
+	t.Run("Get phones", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		expected := `[{"country":"FR","number":"33612345678","validated_at":null}]`
+		client.mngapiClient = &MockClient{response: []byte(expected)}
+
+		phones, apiError := client.GetPhones("IDCA2AC08296")
+		assert.Nil(t, apiError)
+
+		result, err := json.Marshal(phones)
+		assert.NoError(t, err)
+		assert.Equal(t, result, []byte(expected))
+	})
+}
+
+func TestAPIKeys(t *testing.T) {
+	expected := `{"kid":"a7b3b1d5c5e7c0f3","algorithm":"HS256","scope":["trade"],"state":"active","secret":"","created_at":"2021-02-15T10:15:18Z","updated_at":"2021-02-15T10:15:18Z"}`
+
+	t.Run("Get API keys", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		client.mngapiClient = &MockClient{response: []byte("[" + expected + "]")}
+
+		apiKeys, apiError := client.GetAPIKeys(GetAPIKeysParams{UID: "IDCA2AC08296"})
+		assert.Nil(t, apiError)
+		assert.Len(t, apiKeys, 1)
+	})
+
+	t.Run("Revoke API key", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		mock := &MockClient{response: []byte(expected)}
+		client.mngapiClient = mock
+
+		apiKey, apiError := client.DeleteAPIKey(DeleteAPIKeyParams{UID: "IDCA2AC08296", KID: "a7b3b1d5c5e7c0f3"})
+		assert.Nil(t, apiError)
+		assert.Equal(t, "a7b3b1d5c5e7c0f3", apiKey.KID)
+		assert.Equal(t, "api_keys/delete", mock.path)
+	})
+}
+
+func TestGetServiceAccounts(t *testing.T) {
+	expected := `{"email":"test+SI0388B7681C@yellow.com","uid":"SI0388B7681C","role":"service_account","level":3,"state":"active","user":{"email":"test@test.com","uid":"IDCA2AC08296","role":"superadmin","level":3,"otp":true,"state":"active","referral_uid":"","data":""},"created_at":"2021-02-15T10:15:18Z","updated_at":"2021-02-15T10:15:18Z"}`
+
+	t.Run("List", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		client.mngapiClient = &MockClient{response: []byte("[" + expected + "]")}
+
+		serviceAccounts, err := client.IterateServiceAccounts(GetServiceAccountsParams{OwnerUID: "IDCA2AC08296"}).All(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, serviceAccounts, 1)
+	})
+
+	t.Run("Get by uid", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		mock := &MockClient{response: []byte(expected)}
+		client.mngapiClient = mock
+
+		serviceAccount, apiError := client.GetServiceAccountByUID("SI0388B7681C")
+		assert.Nil(t, apiError)
+		assert.Equal(t, "SI0388B7681C", serviceAccount.UID)
+		assert.Equal(t, "service_accounts/get", mock.path)
+	})
+}
@@ -0,0 +1,39 @@
+package barong
+
+import (
+	"context"
+
+	"github.com/openware/pkg/mngapi"
+)
+
+// IterateUsers returns an iterator over every user matching the params, from params.Page
+func (b *Client) IterateUsers(params GetUsersParams) *mngapi.Iterator[*User] {
+	return mngapi.NewIterator(params.Page, params.Limit, func(ctx context.Context, page, limit int64) ([]*User, error) {
+		params.Page, params.Limit = page, limit
+		return b.GetUsersContext(ctx, params)
+	})
+}
+
+// IterateDocuments returns an iterator over every document of the user matching the params, from params.Page
+func (b *Client) IterateDocuments(params GetDocumentsParams) *mngapi.Iterator[*Document] {
+	return mngapi.NewIterator(params.Page, params.Limit, func(ctx context.Context, page, limit int64) ([]*Document, error) {
+		params.Page, params.Limit = page, limit
+		return b.GetDocumentsContext(ctx, params)
+	})
+}
+
+// IterateAPIKeys returns an iterator over every API key of the user matching the params, from params.Page
+func (b *Client) IterateAPIKeys(params GetAPIKeysParams) *mngapi.Iterator[*APIKey] {
+	return mngapi.NewIterator(params.Page, params.Limit, func(ctx context.Context, page, limit int64) ([]*APIKey, error) {
+		params.Page, params.Limit = page, limit
+		return b.GetAPIKeysContext(ctx, params)
+	})
+}
+
+// IterateServiceAccounts returns an iterator over every service account matching the params, from params.Page
+func (b *Client) IterateServiceAccounts(params GetServiceAccountsParams) *mngapi.Iterator[*ServiceAccount] {
+	return mngapi.NewIterator(params.Page, params.Limit, func(ctx context.Context, page, limit int64) ([]*ServiceAccount, error) {
+		params.Page, params.Limit = page, limit
+		return b.GetServiceAccountsContext(ctx, params)
+	})
+}
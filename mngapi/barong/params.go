@@ -24,3 +24,90 @@ type CreateAttachmentParams struct {
 	FileExt  string `json:"file_ext,omitempty"`
 	Upload   string `json:"upload,omitempty"`
 }
+
+// GetUserParams identify a user by uid, email or phone number
+type GetUserParams struct {
+	UID   string `json:"uid,omitempty"`
+	Email string `json:"email,omitempty"`
+	Phone string `json:"phone_num,omitempty"`
+}
+
+// GetUsersParams contain all the allowed params for users listing
+type GetUsersParams struct {
+	Extended bool   `json:"extended,omitempty"`
+	Range    string `json:"range,omitempty"`
+	From     int64  `json:"from,omitempty"`
+	To       int64  `json:"to,omitempty"`
+	Page     int64  `json:"page,omitempty"`
+	Limit    int64  `json:"limit,omitempty"`
+}
+
+// UpdateUserParams contain all the allowed params for user update,
+// Level is a pointer so that a user may be set back to level 0
+type UpdateUserParams struct {
+	UID   string `json:"uid"`
+	State string `json:"state,omitempty"`
+	Role  string `json:"role,omitempty"`
+	Level *int   `json:"level,omitempty"`
+}
+
+// LabelParams contain all the allowed params for label creation and update
+type LabelParams struct {
+	UserUID     string `json:"user_uid"`
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+// DeleteLabelParams contain all the allowed params for label deletion
+type DeleteLabelParams struct {
+	UserUID string `json:"user_uid"`
+	Key     string `json:"key"`
+}
+
+// CreateProfileParams contain all the allowed params for profile creation
+type CreateProfileParams struct {
+	UID       string `json:"uid"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+	DOB       string `json:"dob,omitempty"`
+	Address   string `json:"address,omitempty"`
+	Postcode  string `json:"postcode,omitempty"`
+	City      string `json:"city,omitempty"`
+	Country   string `json:"country,omitempty"`
+	Metadata  string `json:"metadata,omitempty"`
+}
+
+// CreatePhoneParams contain all the allowed params for phone creation
+type CreatePhoneParams struct {
+	UID    string `json:"uid"`
+	Number string `json:"number"`
+}
+
+// GetDocumentsParams contain all the allowed params for documents listing
+type GetDocumentsParams struct {
+	UID   string `json:"uid"`
+	Page  int64  `json:"page,omitempty"`
+	Limit int64  `json:"limit,omitempty"`
+}
+
+// GetAPIKeysParams contain all the allowed params for API keys listing
+type GetAPIKeysParams struct {
+	UID   string `json:"uid"`
+	Page  int64  `json:"page,omitempty"`
+	Limit int64  `json:"limit,omitempty"`
+}
+
+// DeleteAPIKeyParams contain all the allowed params for API key revocation
+type DeleteAPIKeyParams struct {
+	UID string `json:"uid"`
+	KID string `json:"kid"`
+}
+
+// GetServiceAccountsParams contain all the allowed params for service accounts listing
+type GetServiceAccountsParams struct {
+	OwnerUID string `json:"owner_uid,omitempty"`
+	State    string `json:"state,omitempty"`
+	Page     int64  `json:"page,omitempty"`
+	Limit    int64  `json:"limit,omitempty"`
+}
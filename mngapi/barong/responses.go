@@ -39,3 +39,57 @@ type Attachment struct {
 	ID  uint64 `json:"id"`
 	UID string `json:"user_uid"`
 }
+
+// UserWithKYC represents a user with its KYC data
+type UserWithKYC struct {
+	User
+	Labels    []Label    `json:"labels"`
+	Phones    []Phone    `json:"phones"`
+	Profiles  []Profile  `json:"profiles"`
+	Documents []Document `json:"documents"`
+	CreatedAt string     `json:"created_at"`
+	UpdatedAt string     `json:"updated_at"`
+}
+
+// Label represents a user label
+type Label struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Scope       string `json:"scope"`
+	Description string `json:"description"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+// Profile represents a user profile
+type Profile struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	DOB       string `json:"dob"`
+	Address   string `json:"address"`
+	Postcode  string `json:"postcode"`
+	City      string `json:"city"`
+	Country   string `json:"country"`
+	State     string `json:"state"`
+	Metadata  string `json:"metadata"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// Phone represents a user phone number
+type Phone struct {
+	Country     string  `json:"country"`
+	Number      string  `json:"number"`
+	ValidatedAt *string `json:"validated_at"`
+}
+
+// Document represents a user KYC document
+type Document struct {
+	Upload    string `json:"upload"`
+	DocType   string `json:"doc_type"`
+	DocNumber string `json:"doc_number"`
+	DocExpire string `json:"doc_expire"`
+	Metadata  string `json:"metadata"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
@@ -2,15 +2,16 @@ package mngapi
 
 import (
 	"bytes"
+	"context"
 	"crypto/rsa"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"path/filepath"
-	"strings"
 	"time"
 
 	jwtgo "github.com/golang-jwt/jwt"
@@ -37,13 +38,57 @@ type DefaultClient interface {
 	Request(method string, path string, body interface{}) ([]byte, *APIError)
 }
 
+// ContextClient is a DefaultClient supporting cancellation and request options, such as Client
+type ContextClient interface {
+	DefaultClient
+	RequestContext(ctx context.Context, method string, path string, body interface{}, opts ...RequestOption) ([]byte, error)
+}
+
+// RetryPolicy configures retries of idempotent requests failing with a transport error
+// or a 5xx response, zero values select the defaults
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries, requests are not retried by default
+	MaxRetries int
+	// MinBackoff is the delay before the first retry, doubled on every retry, 100ms by default
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between retries, 5 seconds by default
+	MaxBackoff time.Duration
+}
+
+// RequestOption configures a single request
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	idempotent     bool
+	idempotencyKey string
+}
+
+// Idempotent marks the request as safe to retry
+func Idempotent() RequestOption {
+	return func(o *requestOptions) {
+		o.idempotent = true
+	}
+}
+
+// IdempotencyKey sends the key in the Idempotency-Key header so that the management API
+// processes retries of the request only once, and marks the request as safe to retry
+func IdempotencyKey(key string) RequestOption {
+	return func(o *requestOptions) {
+		o.idempotent = true
+		o.idempotencyKey = key
+	}
+}
+
 // Client instance
 type Client struct {
 	URL              string
 	jwtIssuer        string
 	jwtSigningMethod jwtgo.SigningMethod
 	jwtPrivateKey    *rsa.PrivateKey
+	signers          []JWSSigner
 	httpClient       HTTPClient
+	timeout          time.Duration
+	retryPolicy      RetryPolicy
 }
 
 // APIError response from management API
@@ -74,50 +119,197 @@ func New(URL string, jwtIssuer string, jwtAlgo string, jwtPrivateKey string) (*C
 	}
 
 	return &Client{
-		httpClient:       &http.Client{Timeout: RequestTimeout},
+		httpClient:       &http.Client{},
+		timeout:          RequestTimeout,
 		URL:              URL,
 		jwtIssuer:        jwtIssuer,
 		jwtSigningMethod: sm,
 		jwtPrivateKey:    pk,
+		signers:          []JWSSigner{&rsaSigner{kid: jwtIssuer, method: sm, key: pk}},
+	}, nil
+}
+
+// NewWithSigners to return Client struct sending requests signed by every signer,
+// as required by management API actions configured with several keys
+func NewWithSigners(URL string, jwtIssuer string, signers ...JWSSigner) (*Client, error) {
+	if jwtIssuer == "" {
+		return nil, fmt.Errorf("JWT issuer unset")
+	}
+
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("JWT signers unset")
+	}
+
+	kids := make(map[string]bool, len(signers))
+	for _, s := range signers {
+		if kids[s.KeyID()] {
+			return nil, fmt.Errorf("Duplicate JWT signer %s", s.KeyID())
+		}
+		kids[s.KeyID()] = true
+	}
+
+	return &Client{
+		httpClient: &http.Client{},
+		timeout:    RequestTimeout,
+		URL:        URL,
+		jwtIssuer:  jwtIssuer,
+		signers:    signers,
 	}, nil
 }
 
+// SetTimeout sets the timeout of every request attempt, RequestTimeout by default
+func (m *Client) SetTimeout(timeout time.Duration) {
+	m.timeout = timeout
+}
+
+// SetRetryPolicy sets the retry policy of idempotent requests
+func (m *Client) SetRetryPolicy(policy RetryPolicy) {
+	if policy.MinBackoff <= 0 {
+		policy.MinBackoff = 100 * time.Millisecond
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = 5 * time.Second
+	}
+
+	m.retryPolicy = policy
+}
+
+// SetHTTPClient sets the HTTP client used to send requests
+func (m *Client) SetHTTPClient(httpClient HTTPClient) {
+	m.httpClient = httpClient
+}
+
 // Request to call HTTP request
 func (m *Client) Request(method string, path string, body interface{}) ([]byte, *APIError) {
+	res, err := m.RequestContext(context.Background(), method, path, body)
+	return res, ToAPIError(err)
+}
+
+// RequestContext to call HTTP request until the context is done. Requests rejected before sending
+// are returned as *RequestError, transport failures as *TransportError and error responses as *ResponseError. Idempotent requests
+// are retried following the retry policy.
+//
+// GET and DELETE requests also send the body params in the query string, see EncodeQuery,
+// the signed JWT still carries them so that the server verifies the same params.
+func (m *Client) RequestContext(ctx context.Context, method string, path string, body interface{}, opts ...RequestOption) ([]byte, error) {
 	// Check for allowed HTTP methods
 	if !allowedHTTPMethods(method) {
-		return nil, &APIError{StatusCode: 500, Error: "HTTP method is not allowed, accept only POST and PUT"}
+		return nil, &RequestError{Err: fmt.Errorf("HTTP method is not allowed, accept only GET, POST, PUT and DELETE")}
+	}
+
+	options := requestOptions{}
+	for _, opt := range opts {
+		opt(&options)
 	}
 
 	url, err := url.Parse(m.URL)
+	if err != nil {
+		return nil, &RequestError{Err: err}
+	}
 	url.Path = filepath.Join(url.Path, path)
 
-	// TODO: Add to support JWT with multiple signatures
+	if method == http.MethodGet || method == http.MethodDelete {
+		query, err := EncodeQuery(body)
+		if err != nil {
+			return nil, &RequestError{Err: err}
+		}
+		url.RawQuery = query.Encode()
+	}
+
+	backoff := m.retryPolicy.MinBackoff
+	for attempt := 0; ; attempt++ {
+		res, err := m.do(ctx, method, url.String(), body, options)
+		if err == nil || !options.idempotent || attempt >= m.retryPolicy.MaxRetries || !retryable(err) || ctx.Err() != nil {
+			return res, err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, &TransportError{Err: ctx.Err()}
+		}
+
+		if backoff *= 2; backoff > m.retryPolicy.MaxBackoff {
+			backoff = m.retryPolicy.MaxBackoff
+		}
+	}
+}
+
+// Do calls the management api and decodes the response as T,
+// which is usually a pointer to a response struct or a slice:
+//
+//	deposits, err := mngapi.Do[[]*peatio.Deposit](ctx, client, http.MethodPost, "deposits", params)
+//
+// The request is sent with RequestContext if the client is a ContextClient. Otherwise it is sent
+// with Request, without the context and the options, and its error is returned as *ResponseError.
+func Do[T any](ctx context.Context, client DefaultClient, method string, path string, body interface{}, opts ...RequestOption) (T, error) {
+	var result T
+
+	res, err := request(ctx, client, method, path, body, opts...)
+	if err != nil {
+		return result, err
+	}
+
+	if err := json.Unmarshal(res, &result); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return result, nil
+}
+
+// request sends a request with the best method supported by the client
+func request(ctx context.Context, client DefaultClient, method string, path string, body interface{}, opts ...RequestOption) ([]byte, error) {
+	if c, ok := client.(ContextClient); ok {
+		return c.RequestContext(ctx, method, path, body, opts...)
+	}
+
+	res, apiError := client.Request(method, path, body)
+	if apiError != nil {
+		return nil, &ResponseError{APIError: apiError}
+	}
+
+	return res, nil
+}
+
+// do sends a single request attempt
+func (m *Client) do(ctx context.Context, method string, url string, body interface{}, options requestOptions) ([]byte, error) {
+	if m.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.timeout)
+		defer cancel()
+	}
+
 	// Generate JWT
 	jwt, err := m.generateJWT(convertToStringInterface(body), JWTExpireDuration)
 	if err != nil {
-		return nil, &APIError{StatusCode: 500, Error: err.Error()}
+		return nil, &RequestError{Err: err}
 	}
 
 	// Convert jwt to json string
 	jwtstr, err := json.Marshal(jwt)
 	if err != nil {
-		return nil, &APIError{StatusCode: 500, Error: err.Error()}
+		return nil, &RequestError{Err: err}
 	}
 
 	// Create new HTTP request
-	req, err := http.NewRequest(method, url.String(), bytes.NewBuffer(jwtstr))
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(jwtstr))
 	if err != nil {
-		return nil, &APIError{StatusCode: 500, Error: err.Error()}
+		return nil, &RequestError{Err: err}
 	}
 
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("Content-Type", "application/json")
+	if options.idempotencyKey != "" {
+		req.Header.Add("Idempotency-Key", options.idempotencyKey)
+	}
 
 	// Call HTTP request
 	res, err := m.httpClient.Do(req)
 	if err != nil {
-		return nil, &APIError{StatusCode: 500, Error: err.Error()}
+		return nil, &TransportError{Err: err}
 	}
 
 	defer res.Body.Close()
@@ -125,7 +317,7 @@ func (m *Client) Request(method string, path string, body interface{}) ([]byte,
 	// Convert response body to []byte
 	resbody, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return nil, &APIError{StatusCode: 500, Error: err.Error()}
+		return nil, &TransportError{Err: err}
 	}
 
 	// Check for API error
@@ -137,12 +329,23 @@ func (m *Client) Request(method string, path string, body interface{}) ([]byte,
 
 		_ = json.Unmarshal(resbody, &apiError)
 
-		return nil, &apiError
+		return nil, &ResponseError{APIError: &apiError}
 	}
 
 	return resbody, nil
 }
 
+// retryable reports whether a failed request attempt is worth retrying
+func retryable(err error) bool {
+	var responseError *ResponseError
+	if errors.As(err, &responseError) {
+		return responseError.StatusCode >= 500
+	}
+
+	var transportError *TransportError
+	return errors.As(err, &transportError)
+}
+
 func (m *Client) generateJWT(data map[string]interface{}, validPeriod time.Duration, opts ...interface{}) (map[string]interface{}, error) {
 	iat := time.Now()
 	jti := RandomString(16)
@@ -160,32 +363,34 @@ func (m *Client) generateJWT(data map[string]interface{}, validPeriod time.Durat
 		"jti":  jti,
 	}
 
-	t := jwtgo.NewWithClaims(m.jwtSigningMethod, claims)
-
-	sstr, err := t.SigningString()
+	payload, err := json.Marshal(claims)
 	if err != nil {
 		return nil, err
 	}
+	encodedPayload := jwtgo.EncodeSegment(payload)
 
-	hp := strings.Split(sstr, ".")
-	if len(hp) != 2 {
-		return nil, fmt.Errorf("Invalid segment count in sstr %d, expected 2", len(hp))
-	}
+	signatures := make([]map[string]interface{}, 0, len(m.signers))
+	for _, s := range m.signers {
+		protected, err := protectedHeader(s.Algorithm())
+		if err != nil {
+			return nil, err
+		}
 
-	sig, err := t.Method.Sign(sstr, m.jwtPrivateKey)
-	if err != nil {
-		return nil, err
+		sig, err := s.Sign(protected + "." + encodedPayload)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to sign JWT with %s: %w", s.KeyID(), err)
+		}
+
+		signatures = append(signatures, map[string]interface{}{
+			"protected": protected,
+			"header":    map[string]string{"kid": s.KeyID()},
+			"signature": sig,
+		})
 	}
 
 	jwt := map[string]interface{}{
-		"payload": hp[1],
-		"signatures": []map[string]interface{}{
-			{
-				"protected": hp[0],
-				"header":    map[string]string{"kid": m.jwtIssuer},
-				"signature": sig,
-			},
-		},
+		"payload":    encodedPayload,
+		"signatures": signatures,
 	}
 
 	return jwt, nil
@@ -210,7 +415,7 @@ func allowedHTTPMethods(method string) bool {
 		return false
 	}
 
-	var methods = []string{http.MethodPost, http.MethodPut}
+	var methods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete}
 
 	for _, v := range methods {
 		if v == method {
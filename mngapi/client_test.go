@@ -2,9 +2,15 @@ package mngapi
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -32,6 +38,16 @@ func (m *MockHTTPClient) Do(req *http.Request) (*http.Response, error) {
 	return m.httpResponse, m.httpError
 }
 
+// legacyClient implements DefaultClient without RequestContext
+type legacyClient struct {
+	response []byte
+	apiError *APIError
+}
+
+func (c *legacyClient) Request(method string, path string, body interface{}) ([]byte, *APIError) {
+	return c.response, c.apiError
+}
+
 func TestCreateNewClient(t *testing.T) {
 	t.Run("Success creation", func(t *testing.T) {
 		mgnt, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
@@ -96,12 +112,12 @@ func TestRequest(t *testing.T) {
 			httpError:    nil,
 		}
 
-		res, apierr := mgnt.Request(http.MethodGet, "api/test", nil)
+		res, apierr := mgnt.Request(http.MethodPatch, "api/test", nil)
 
 		assert.Nil(t, res)
 		assert.NotNil(t, apierr)
-		assert.Equal(t, apierr.StatusCode, 500)
-		assert.Equal(t, apierr.Error, "HTTP method is not allowed, accept only POST and PUT")
+		assert.Equal(t, apierr.StatusCode, 0)
+		assert.Equal(t, apierr.Error, "HTTP method is not allowed, accept only GET, POST, PUT and DELETE")
 	})
 
 	t.Run("HTTP client error", func(t *testing.T) {
@@ -179,8 +195,8 @@ func TestAllowedHTTPMethods(t *testing.T) {
 
 	assert.Equal(t, postMethod, true)
 	assert.Equal(t, putMethod, true)
-	assert.Equal(t, getMethod, false)
-	assert.Equal(t, deleteMethod, false)
+	assert.Equal(t, getMethod, true)
+	assert.Equal(t, deleteMethod, true)
 	assert.Equal(t, unknownMethod, false)
 	assert.Equal(t, emptyMethod, false)
 }
@@ -206,3 +222,155 @@ func TestGenerateJWT(t *testing.T) {
 		},
 	}, jwt)
 }
+
+func TestRequestContext(t *testing.T) {
+	newServer := func(t *testing.T, handler http.HandlerFunc) *Client {
+		srv := httptest.NewServer(handler)
+		t.Cleanup(srv.Close)
+
+		mgnt, err := New(srv.URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		require.NoError(t, err)
+		mgnt.SetRetryPolicy(RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond})
+		return mgnt
+	}
+
+	t.Run("Retry idempotent request on 5xx", func(t *testing.T) {
+		var calls int32
+		mgnt := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"uid":"IDCA2AC08296"}`))
+		})
+
+		res, err := mgnt.RequestContext(context.Background(), http.MethodPost, "api/test", nil, Idempotent())
+		require.NoError(t, err)
+		assert.Equal(t, `{"uid":"IDCA2AC08296"}`, string(res))
+		assert.Equal(t, int32(3), calls)
+	})
+
+	t.Run("No retry of non idempotent request", func(t *testing.T) {
+		var calls int32
+		mgnt := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		})
+
+		_, err := mgnt.RequestContext(context.Background(), http.MethodPost, "api/test", nil)
+		var responseError *ResponseError
+		require.True(t, errors.As(err, &responseError))
+		assert.Equal(t, 502, responseError.StatusCode)
+		assert.Equal(t, "502 Bad Gateway", err.Error())
+		assert.Equal(t, int32(1), calls)
+	})
+
+	t.Run("No retry on 4xx", func(t *testing.T) {
+		var calls int32
+		mgnt := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"errors":["management.withdraw.invalid_amount"]}`))
+		})
+
+		_, err := mgnt.RequestContext(context.Background(), http.MethodPost, "api/test", nil, Idempotent())
+		var responseError *ResponseError
+		require.True(t, errors.As(err, &responseError))
+		assert.Equal(t, []string{"management.withdraw.invalid_amount"}, responseError.Errors)
+		assert.Equal(t, int32(1), calls)
+	})
+
+	t.Run("Idempotency key", func(t *testing.T) {
+		var keys []string
+		mgnt := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+			keys = append(keys, r.Header.Get("Idempotency-Key"))
+			if len(keys) == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte(`{}`))
+		})
+
+		_, err := mgnt.RequestContext(context.Background(), http.MethodPost, "api/test", nil, IdempotencyKey("TID123"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"TID123", "TID123"}, keys)
+	})
+
+	t.Run("Attempt timeout", func(t *testing.T) {
+		mgnt := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(50 * time.Millisecond)
+		})
+		mgnt.SetTimeout(10 * time.Millisecond)
+		mgnt.SetRetryPolicy(RetryPolicy{})
+
+		_, err := mgnt.RequestContext(context.Background(), http.MethodPost, "api/test", nil, Idempotent())
+		var transportError *TransportError
+		require.True(t, errors.As(err, &transportError))
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Equal(t, 500, ToAPIError(err).StatusCode)
+	})
+
+	t.Run("GET with query params", func(t *testing.T) {
+		rsaKey, err := loadPrivateKeyFromString(jwtPrivateKey)
+		require.NoError(t, err)
+		verifier := NewVerifier()
+		verifier.AddRSAKey(jwtIssuer, &rsaKey.PublicKey)
+
+		var query url.Values
+		var data json.RawMessage
+		mgnt := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.Query()
+			body, _ := ioutil.ReadAll(r.Body)
+			claims, _, err := verifier.Verify(body)
+			require.NoError(t, err)
+			data = claims.Data
+			w.Write([]byte(`[{"uid":"IDCA2AC08296"}]`))
+		})
+
+		params := struct {
+			UID        string   `json:"uid"`
+			Page       int64    `json:"page,omitempty"`
+			Currencies []string `json:"currencies"`
+		}{UID: "IDCA2AC08296", Currencies: []string{"eth", "btc"}}
+
+		res, err := Do[[]map[string]string](context.Background(), mgnt, http.MethodGet, "api/test", params)
+		require.NoError(t, err)
+		assert.Equal(t, []map[string]string{{"uid": "IDCA2AC08296"}}, res)
+		assert.Equal(t, url.Values{"uid": {"IDCA2AC08296"}, "currencies[]": {"eth", "btc"}}, query)
+		assert.JSONEq(t, `{"uid":"IDCA2AC08296","currencies":["eth","btc"]}`, string(data))
+	})
+
+	t.Run("Do with invalid payload", func(t *testing.T) {
+		mgnt := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"uid":1}`))
+		})
+
+		res, err := Do[*struct {
+			UID string `json:"uid"`
+		}](context.Background(), mgnt, http.MethodPost, "api/test", nil)
+		assert.Nil(t, res)
+		assert.EqualError(t, err, `json: cannot unmarshal number into Go struct field .uid of type string`)
+	})
+
+	t.Run("Do with a DefaultClient", func(t *testing.T) {
+		res, err := Do[map[string]string](context.Background(), &legacyClient{response: []byte(`{"uid":"IDCA2AC08296"}`)}, http.MethodPost, "api/test", nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"uid": "IDCA2AC08296"}, res)
+
+		_, err = Do[map[string]string](context.Background(), &legacyClient{apiError: &APIError{StatusCode: 404, Error: "404 Not Found"}}, http.MethodPost, "api/test", nil)
+		assert.Equal(t, &APIError{StatusCode: 404, Error: "404 Not Found"}, ToAPIError(err))
+	})
+
+	t.Run("Cancelled context", func(t *testing.T) {
+		mgnt := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		mgnt.SetRetryPolicy(RetryPolicy{MaxRetries: 10, MinBackoff: time.Second})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := mgnt.RequestContext(ctx, http.MethodPost, "api/test", nil, Idempotent())
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+}
@@ -0,0 +1,73 @@
+package mngapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TransportError is returned when a request could not reach the management API
+// or its response could not be read, e.g. connection errors and timeouts
+type TransportError struct {
+	Err error
+}
+
+func (e *TransportError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// RequestError is returned when a request is rejected before being sent to the management API,
+// e.g. an unsupported HTTP method or params failing a client-side validation
+type RequestError struct {
+	Err error
+}
+
+func (e *RequestError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// ResponseError is returned when the management API responds with an error status
+type ResponseError struct {
+	*APIError
+}
+
+func (e *ResponseError) Error() string {
+	switch {
+	case e.APIError.Error != "":
+		return e.APIError.Error
+	case len(e.Errors) > 0:
+		return strings.Join(e.Errors, ", ")
+	default:
+		return fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+}
+
+// ToAPIError converts an error returned by RequestContext to the *APIError returned by Request,
+// RequestError is reported with the status code 0 as the request never reached the server,
+// and the other errors with the status code 500
+func ToAPIError(err error) *APIError {
+	if err == nil {
+		return nil
+	}
+
+	var e *ResponseError
+	if errors.As(err, &e) {
+		return e.APIError
+	}
+
+	var requestError *RequestError
+	if errors.As(err, &requestError) {
+		return &APIError{StatusCode: 0, Error: err.Error()}
+	}
+
+	return &APIError{StatusCode: 500, Error: err.Error()}
+}
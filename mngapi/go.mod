@@ -3,12 +3,20 @@ module github.com/openware/pkg/mngapi
 go 1.18
 
 require (
+	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.0.1
+	github.com/ethereum/go-ethereum v1.10.25
 	github.com/golang-jwt/jwt v3.2.2+incompatible
+	github.com/openware/pkg/ika v0.1.1
+	github.com/rabbitmq/amqp091-go v1.5.0
+	github.com/shopspring/decimal v1.4.0
 	github.com/stretchr/testify v1.8.0
 )
 
 require (
+	github.com/btcsuite/btcd/btcec/v2 v2.2.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/crypto v0.0.0-20210921155107-089bfa567519 // indirect
+	golang.org/x/sys v0.0.0-20220624220833-87e55d714810 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
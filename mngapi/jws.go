@@ -0,0 +1,146 @@
+package mngapi
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	jwtgo "github.com/golang-jwt/jwt"
+)
+
+// JWSSigner contributes one signature to the JWS JSON envelope sent to the management API
+type JWSSigner interface {
+	// KeyID returns the key id set in the signature header, as configured in the management API keychain
+	KeyID() string
+	// Algorithm returns the JWS algorithm of the signature
+	Algorithm() string
+	// Sign returns the base64url encoded signature of the signing input
+	Sign(signingInput string) (string, error)
+}
+
+// rsaSigner signs with an RSA private key
+type rsaSigner struct {
+	kid    string
+	method jwtgo.SigningMethod
+	key    *rsa.PrivateKey
+}
+
+// NewRSASigner returns a JWSSigner using a base64 encoded PEM RSA private key
+func NewRSASigner(kid string, jwtAlgo string, jwtPrivateKey string) (JWSSigner, error) {
+	pk, err := loadPrivateKeyFromString(jwtPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if jwtAlgo == "" {
+		jwtAlgo = JWTAlgorithm
+	}
+
+	sm := jwtgo.GetSigningMethod(jwtAlgo)
+	if sm == nil {
+		return nil, fmt.Errorf("Unsupported signing method %s", jwtAlgo)
+	}
+
+	return &rsaSigner{kid: kid, method: sm, key: pk}, nil
+}
+
+func (s *rsaSigner) KeyID() string {
+	return s.kid
+}
+
+func (s *rsaSigner) Algorithm() string {
+	return s.method.Alg()
+}
+
+func (s *rsaSigner) Sign(signingInput string) (string, error) {
+	return s.method.Sign(signingInput, s.key)
+}
+
+// funcSigner signs with a signing function, see NewDigestSigner and NewMessageSigner
+type funcSigner struct {
+	kid  string
+	alg  string
+	sign func(signingInput []byte) ([]byte, error)
+}
+
+func (s *funcSigner) KeyID() string {
+	return s.kid
+}
+
+func (s *funcSigner) Algorithm() string {
+	return s.alg
+}
+
+func (s *funcSigner) Sign(signingInput string) (string, error) {
+	sig, err := s.sign([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+
+	return jwtgo.EncodeSegment(sig), nil
+}
+
+// NewDigestSigner returns an ES256K JWSSigner from a secp256k1 digest signing function,
+// such as the Sign method of any signer.SignerInterface:
+//
+//	s := mngapi.NewDigestSigner("applogic", kmsSigner.Sign)
+func NewDigestSigner[S ~[]byte](kid string, sign func(digest []byte) (S, error)) JWSSigner {
+	return &funcSigner{
+		kid: kid,
+		alg: "ES256K",
+		sign: func(signingInput []byte) ([]byte, error) {
+			digest := sha256.Sum256(signingInput)
+			sig, err := sign(digest[:])
+			if err != nil {
+				return nil, err
+			}
+
+			return trimRecoveryID(sig), nil
+		},
+	}
+}
+
+// NewMessageSigner returns a JWSSigner from a message signing function and its JWS algorithm,
+// such as the SignMessage method of any signer.KeySigner:
+//
+//	s := mngapi.NewMessageSigner("applogic", string(kmsSigner.Algorithm()), kmsSigner.SignMessage)
+func NewMessageSigner[S ~[]byte](kid string, jwtAlgo string, sign func(message []byte) (S, error)) JWSSigner {
+	return &funcSigner{
+		kid: kid,
+		alg: jwtAlgo,
+		sign: func(signingInput []byte) ([]byte, error) {
+			sig, err := sign(signingInput)
+			if err != nil {
+				return nil, err
+			}
+
+			if jwtAlgo == "ES256K" {
+				return trimRecoveryID(sig), nil
+			}
+			return sig, nil
+		},
+	}
+}
+
+// trimRecoveryID drops V from R || S || V secp256k1 signatures, JWS expects R || S only
+func trimRecoveryID[S ~[]byte](sig S) []byte {
+	if len(sig) == 65 {
+		return sig[:64]
+	}
+
+	return sig
+}
+
+// protectedHeader returns the base64url encoded protected header of a signature
+func protectedHeader(alg string) (string, error) {
+	header, err := json.Marshal(map[string]string{
+		"alg": alg,
+		"typ": "JWT",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return jwtgo.EncodeSegment(header), nil
+}
@@ -0,0 +1,202 @@
+package mngapi
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	jwtgo "github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiSignatureJWT(t *testing.T) {
+	rsaSigner, err := NewRSASigner("applogic", "RS256", jwtPrivateKey)
+	require.NoError(t, err)
+	rsaKey, err := loadPrivateKeyFromString(jwtPrivateKey)
+	require.NoError(t, err)
+
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	edSigner := NewMessageSigner("finex", "EdDSA", func(message []byte) ([]byte, error) {
+		return ed25519.Sign(edKey, message), nil
+	})
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecSigner := NewMessageSigner("wallet", "ES256", func(message []byte) ([]byte, error) {
+		digest := sha256.Sum256(message)
+		r, s, err := ecdsa.Sign(rand.Reader, ecKey, digest[:])
+		if err != nil {
+			return nil, err
+		}
+		sig := make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+		return sig, nil
+	})
+
+	mgnt, err := NewWithSigners(URL, jwtIssuer, rsaSigner, edSigner, ecSigner)
+	require.NoError(t, err)
+
+	jwt, err := mgnt.generateJWT(map[string]interface{}{"uid": "IDCA2AC08296"}, time.Hour)
+	require.NoError(t, err)
+	assert.Len(t, jwt["signatures"], 3)
+
+	body, err := json.Marshal(jwt)
+	require.NoError(t, err)
+
+	verifier := NewVerifier()
+	verifier.AddRSAKey("applogic", &rsaKey.PublicKey)
+	verifier.AddEd25519Key("finex", edPub)
+	verifier.AddECDSAKey("wallet", &ecKey.PublicKey)
+
+	t.Run("Valid signatures", func(t *testing.T) {
+		claims, kids, err := verifier.Verify(body, "applogic", "finex")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"applogic", "finex", "wallet"}, kids)
+		assert.Equal(t, jwtIssuer, claims.Issuer)
+		assert.JSONEq(t, `{"uid":"IDCA2AC08296"}`, string(claims.Data))
+	})
+
+	t.Run("Missing required signature", func(t *testing.T) {
+		_, _, err := verifier.Verify(body, "barong")
+		assert.EqualError(t, err, "Missing JWT signature of barong")
+	})
+
+	t.Run("Unknown signer", func(t *testing.T) {
+		v := NewVerifier()
+		v.AddRSAKey("applogic", &rsaKey.PublicKey)
+		_, _, err := v.Verify(body)
+		assert.EqualError(t, err, `Unknown JWT signer "finex"`)
+	})
+
+	t.Run("Tampered payload", func(t *testing.T) {
+		tampered := map[string]interface{}{"payload": "e30", "signatures": jwt["signatures"]}
+		b, err := json.Marshal(tampered)
+		require.NoError(t, err)
+		_, _, err = verifier.Verify(b)
+		assert.EqualError(t, err, "Signature verification failed for applogic")
+	})
+
+	t.Run("RSA hash of the header algorithm", func(t *testing.T) {
+		// RS256 in the header with a SHA-512 signature
+		mismatched := NewMessageSigner("applogic", "RS256", func(message []byte) ([]byte, error) {
+			digest := sha512.Sum512(message)
+			return rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA512, digest[:])
+		})
+		mgnt, err := NewWithSigners(URL, jwtIssuer, mismatched)
+		require.NoError(t, err)
+		jwt, err := mgnt.generateJWT(map[string]interface{}{}, time.Hour)
+		require.NoError(t, err)
+		b, err := json.Marshal(jwt)
+		require.NoError(t, err)
+		_, _, err = verifier.Verify(b)
+		assert.EqualError(t, err, "Signature verification failed for applogic")
+
+		rs512, err := NewRSASigner("applogic", "RS512", jwtPrivateKey)
+		require.NoError(t, err)
+		mgnt, err = NewWithSigners(URL, jwtIssuer, rs512)
+		require.NoError(t, err)
+		jwt, err = mgnt.generateJWT(map[string]interface{}{}, time.Hour)
+		require.NoError(t, err)
+		b, err = json.Marshal(jwt)
+		require.NoError(t, err)
+		_, _, err = verifier.Verify(b)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Token without expiration", func(t *testing.T) {
+		protected := jwtgo.EncodeSegment([]byte(`{"alg":"EdDSA"}`))
+		payload := jwtgo.EncodeSegment([]byte(`{"iss":"applogic","data":{}}`))
+		b, err := json.Marshal(map[string]interface{}{
+			"payload": payload,
+			"signatures": []map[string]interface{}{{
+				"protected": protected,
+				"header":    map[string]string{"kid": "finex"},
+				"signature": jwtgo.EncodeSegment(ed25519.Sign(edKey, []byte(protected+"."+payload))),
+			}},
+		})
+		require.NoError(t, err)
+		_, _, err = verifier.Verify(b)
+		assert.EqualError(t, err, "JWT has no expiration")
+	})
+
+	t.Run("Ed25519 key of a wrong length", func(t *testing.T) {
+		v := NewVerifier()
+		v.AddRSAKey("applogic", &rsaKey.PublicKey)
+		v.AddEd25519Key("finex", edPub[:16])
+		v.AddECDSAKey("wallet", &ecKey.PublicKey)
+		_, _, err := v.Verify(body)
+		assert.EqualError(t, err, "Signature verification failed for finex")
+	})
+
+	t.Run("Expired token", func(t *testing.T) {
+		verifier.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+		defer func() { verifier.now = time.Now }()
+		_, _, err := verifier.Verify(body)
+		assert.EqualError(t, err, "JWT is expired")
+	})
+}
+
+func TestNewWithSigners(t *testing.T) {
+	rsaSigner, err := NewRSASigner("applogic", "", jwtPrivateKey)
+	require.NoError(t, err)
+	assert.Equal(t, "RS256", rsaSigner.Algorithm())
+
+	_, err = NewWithSigners(URL, "", rsaSigner)
+	assert.EqualError(t, err, "JWT issuer unset")
+
+	_, err = NewWithSigners(URL, jwtIssuer)
+	assert.EqualError(t, err, "JWT signers unset")
+
+	_, err = NewWithSigners(URL, jwtIssuer, rsaSigner, rsaSigner)
+	assert.EqualError(t, err, "Duplicate JWT signer applogic")
+}
+
+func TestDigestSigner(t *testing.T) {
+	key, err := ethcrypto.GenerateKey()
+	require.NoError(t, err)
+
+	// crypto.Sign returns R || S || V signatures, like the Sign method of signer.LocalSigner
+	s := NewDigestSigner("applogic", func(digest []byte) ([]byte, error) {
+		return ethcrypto.Sign(digest, key)
+	})
+	assert.Equal(t, "ES256K", s.Algorithm())
+
+	mgnt, err := NewWithSigners(URL, jwtIssuer, s)
+	require.NoError(t, err)
+	jwt, err := mgnt.generateJWT(map[string]interface{}{}, time.Hour)
+	require.NoError(t, err)
+	body, err := json.Marshal(jwt)
+	require.NoError(t, err)
+
+	other, err := ethcrypto.GenerateKey()
+	require.NoError(t, err)
+	verifier := NewVerifier()
+	verifier.AddSecp256k1Key("applogic", &other.PublicKey)
+	_, _, err = verifier.Verify(body)
+	assert.EqualError(t, err, "Signature verification failed for applogic")
+
+	verifier.AddSecp256k1Key("applogic", &key.PublicKey)
+	_, _, err = verifier.Verify(body)
+	require.NoError(t, err)
+
+	failing := NewDigestSigner("applogic", func(digest []byte) ([]byte, error) {
+		return nil, errors.New("kms unavailable")
+	})
+	mgnt, err = NewWithSigners(URL, jwtIssuer, failing)
+	require.NoError(t, err)
+	_, err = mgnt.generateJWT(map[string]interface{}{}, time.Hour)
+	assert.EqualError(t, err, "Failed to sign JWT with applogic: kms unavailable")
+}
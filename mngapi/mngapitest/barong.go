@@ -0,0 +1,398 @@
+package mngapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/openware/pkg/mngapi"
+	"github.com/openware/pkg/mngapi/barong"
+)
+
+// Barong is an in-memory barong management API, serving the endpoints of barong.Client
+// for users, labels, service accounts and API keys
+type Barong struct {
+	*server
+
+	users           []*barong.UserWithKYC
+	serviceAccounts []*barong.ServiceAccount
+	apiKeys         map[string][]*barong.APIKey
+}
+
+// NewBarong returns an empty barong management API accepting requests signed by the verifier keys,
+// every key id in required must have signed the requests
+func NewBarong(verifier *mngapi.Verifier, required ...string) *Barong {
+	b := &Barong{
+		server:  newServer(verifier, required),
+		apiKeys: make(map[string][]*barong.APIKey),
+	}
+
+	b.handle(http.MethodPost, "users/get", b.getUser)
+	b.handle(http.MethodPost, "users/list", b.listUsers)
+	b.handle(http.MethodPost, "users/update", b.updateUser)
+
+	b.handle(http.MethodPost, "labels", b.createLabel)
+	b.handle(http.MethodPut, "labels", b.updateLabel)
+	b.handle(http.MethodPost, "labels/delete", b.deleteLabel)
+	b.handle(http.MethodPost, "labels/list", b.listLabels)
+
+	b.handle(http.MethodPost, "service_accounts/create", b.createServiceAccount)
+	b.handle(http.MethodPost, "service_accounts/get", b.getServiceAccount)
+	b.handle(http.MethodPost, "service_accounts/list", b.listServiceAccounts)
+	b.handle(http.MethodPost, "service_accounts/delete", b.deleteServiceAccount)
+
+	b.handle(http.MethodPost, "api_keys", b.createAPIKey)
+	b.handle(http.MethodPost, "api_keys/list", b.listAPIKeys)
+	b.handle(http.MethodPost, "api_keys/delete", b.deleteAPIKey)
+
+	return b
+}
+
+// AddUser seeds a user, barong management API has no user creation endpoint
+func (b *Barong) AddUser(user barong.User) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if user.Role == "" {
+		user.Role = "member"
+	}
+	if user.State == "" {
+		user.State = "active"
+	}
+
+	b.users = append(b.users, &barong.UserWithKYC{
+		User:      user,
+		Labels:    []barong.Label{},
+		Phones:    []barong.Phone{},
+		Profiles:  []barong.Profile{},
+		Documents: []barong.Document{},
+		CreatedAt: now(),
+		UpdatedAt: now(),
+	})
+}
+
+func (b *Barong) getUser(_ []string, data json.RawMessage) (interface{}, error) {
+	params := barong.GetUserParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	for _, u := range b.users {
+		if (params.UID != "" && u.UID == params.UID) || (params.Email != "" && u.Email == params.Email) {
+			return u, nil
+		}
+	}
+
+	return nil, errorf(http.StatusNotFound, "user.doesnt_exist")
+}
+
+func (b *Barong) listUsers(_ []string, data json.RawMessage) (interface{}, error) {
+	params := barong.GetUsersParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	users := make([]*barong.User, 0, len(b.users))
+	for _, u := range b.users {
+		users = append(users, &u.User)
+	}
+
+	return paginate(users, params.Page, params.Limit), nil
+}
+
+func (b *Barong) updateUser(_ []string, data json.RawMessage) (interface{}, error) {
+	params := barong.UpdateUserParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	u := b.user(params.UID)
+	if u == nil {
+		return nil, errorf(http.StatusNotFound, "user.doesnt_exist")
+	}
+
+	if params.State != "" {
+		u.State = params.State
+	}
+	if params.Role != "" {
+		u.Role = params.Role
+	}
+	if params.Level != nil {
+		u.Level = uint64(*params.Level)
+	}
+	u.UpdatedAt = now()
+
+	return &u.User, nil
+}
+
+func (b *Barong) createLabel(_ []string, data json.RawMessage) (interface{}, error) {
+	params := barong.LabelParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	u := b.user(params.UserUID)
+	if u == nil {
+		return nil, errorf(http.StatusNotFound, "user.doesnt_exist")
+	}
+	if _, l := label(u, params.Key); l != nil {
+		return nil, errorf(http.StatusUnprocessableEntity, "key.taken")
+	}
+
+	u.Labels = append(u.Labels, barong.Label{
+		Key:         params.Key,
+		Value:       params.Value,
+		Scope:       "private",
+		Description: params.Description,
+		CreatedAt:   now(),
+		UpdatedAt:   now(),
+	})
+
+	return &u.Labels[len(u.Labels)-1], nil
+}
+
+func (b *Barong) updateLabel(_ []string, data json.RawMessage) (interface{}, error) {
+	params := barong.LabelParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	u := b.user(params.UserUID)
+	if u == nil {
+		return nil, errorf(http.StatusNotFound, "user.doesnt_exist")
+	}
+	_, l := label(u, params.Key)
+	if l == nil {
+		return nil, errorf(http.StatusNotFound, "label.doesnt_exist")
+	}
+
+	l.Value = params.Value
+	if params.Description != "" {
+		l.Description = params.Description
+	}
+	l.UpdatedAt = now()
+
+	return l, nil
+}
+
+func (b *Barong) deleteLabel(_ []string, data json.RawMessage) (interface{}, error) {
+	params := barong.DeleteLabelParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	u := b.user(params.UserUID)
+	if u == nil {
+		return nil, errorf(http.StatusNotFound, "user.doesnt_exist")
+	}
+	i, l := label(u, params.Key)
+	if l == nil {
+		return nil, errorf(http.StatusNotFound, "label.doesnt_exist")
+	}
+
+	deleted := *l
+	u.Labels = append(u.Labels[:i], u.Labels[i+1:]...)
+
+	return &deleted, nil
+}
+
+func (b *Barong) listLabels(_ []string, data json.RawMessage) (interface{}, error) {
+	params := struct {
+		UserUID string `json:"user_uid"`
+	}{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	u := b.user(params.UserUID)
+	if u == nil {
+		return nil, errorf(http.StatusNotFound, "user.doesnt_exist")
+	}
+
+	return u.Labels, nil
+}
+
+func (b *Barong) createServiceAccount(_ []string, data json.RawMessage) (interface{}, error) {
+	params := barong.CreateServiceAccountParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	owner := b.user(params.OwnerUID)
+	if owner == nil {
+		return nil, errorf(http.StatusNotFound, "user.doesnt_exist")
+	}
+
+	uid := params.UID
+	if uid == "" {
+		uid = fmt.Sprintf("SI%08d", len(b.serviceAccounts)+1)
+	}
+	if b.serviceAccount(uid) != nil {
+		return nil, errorf(http.StatusUnprocessableEntity, "uid.taken")
+	}
+
+	sa := &barong.ServiceAccount{
+		Email:     withDefault(params.Email, fmt.Sprintf("%s@%s", uid, owner.UID)),
+		UID:       uid,
+		Role:      params.Role,
+		Level:     owner.Level,
+		State:     withDefault(params.State, "active"),
+		User:      owner.User,
+		CreatedAt: now(),
+		UpdatedAt: now(),
+	}
+	if params.Level > 0 {
+		sa.Level = uint64(params.Level)
+	}
+
+	b.serviceAccounts = append(b.serviceAccounts, sa)
+	return sa, nil
+}
+
+func (b *Barong) getServiceAccount(_ []string, data json.RawMessage) (interface{}, error) {
+	params := struct {
+		UID string `json:"uid"`
+	}{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	sa := b.serviceAccount(params.UID)
+	if sa == nil {
+		return nil, errorf(http.StatusNotFound, "service_account.doesnt_exist")
+	}
+
+	return sa, nil
+}
+
+func (b *Barong) listServiceAccounts(_ []string, data json.RawMessage) (interface{}, error) {
+	params := barong.GetServiceAccountsParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	serviceAccounts := []*barong.ServiceAccount{}
+	for _, sa := range b.serviceAccounts {
+		if matches(params.OwnerUID, sa.User.UID) && matches(params.State, sa.State) {
+			serviceAccounts = append(serviceAccounts, sa)
+		}
+	}
+
+	return paginate(serviceAccounts, params.Page, params.Limit), nil
+}
+
+func (b *Barong) deleteServiceAccount(_ []string, data json.RawMessage) (interface{}, error) {
+	params := struct {
+		UID string `json:"uid"`
+	}{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	sa := b.serviceAccount(params.UID)
+	if sa == nil {
+		return nil, errorf(http.StatusNotFound, "service_account.doesnt_exist")
+	}
+
+	sa.State = "disabled"
+	sa.UpdatedAt = now()
+	delete(b.apiKeys, sa.UID)
+
+	return sa, nil
+}
+
+func (b *Barong) createAPIKey(_ []string, data json.RawMessage) (interface{}, error) {
+	params := barong.CreateAPIKeyParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	if b.user(params.UID) == nil && b.serviceAccount(params.UID) == nil {
+		return nil, errorf(http.StatusNotFound, "user.doesnt_exist")
+	}
+
+	key := &barong.APIKey{
+		KID:       fmt.Sprintf("%016x", b.countAPIKeys()+1),
+		Algorithm: params.Algorithm,
+		Scope:     []string{},
+		State:     "active",
+		Secret:    fmt.Sprintf("%032x", b.countAPIKeys()+1),
+		CreatedAt: now(),
+		UpdatedAt: now(),
+	}
+	if params.Scopes != "" {
+		key.Scope = []string{params.Scopes}
+	}
+
+	// The secret is only disclosed on creation
+	stored := *key
+	stored.Secret = ""
+	b.apiKeys[params.UID] = append(b.apiKeys[params.UID], &stored)
+
+	return key, nil
+}
+
+func (b *Barong) listAPIKeys(_ []string, data json.RawMessage) (interface{}, error) {
+	params := barong.GetAPIKeysParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	return paginate(append([]*barong.APIKey{}, b.apiKeys[params.UID]...), params.Page, params.Limit), nil
+}
+
+func (b *Barong) deleteAPIKey(_ []string, data json.RawMessage) (interface{}, error) {
+	params := barong.DeleteAPIKeyParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	keys := b.apiKeys[params.UID]
+	for i, key := range keys {
+		if key.KID == params.KID {
+			b.apiKeys[params.UID] = append(keys[:i], keys[i+1:]...)
+			return key, nil
+		}
+	}
+
+	return nil, errorf(http.StatusNotFound, "api_key.doesnt_exist")
+}
+
+func (b *Barong) user(uid string) *barong.UserWithKYC {
+	for _, u := range b.users {
+		if u.UID == uid {
+			return u
+		}
+	}
+
+	return nil
+}
+
+func (b *Barong) serviceAccount(uid string) *barong.ServiceAccount {
+	for _, sa := range b.serviceAccounts {
+		if sa.UID == uid {
+			return sa
+		}
+	}
+
+	return nil
+}
+
+func (b *Barong) countAPIKeys() int {
+	count := 0
+	for _, keys := range b.apiKeys {
+		count += len(keys)
+	}
+
+	return count
+}
+
+func label(u *barong.UserWithKYC, key string) (int, *barong.Label) {
+	for i := range u.Labels {
+		if u.Labels[i].Key == key {
+			return i, &u.Labels[i]
+		}
+	}
+
+	return -1, nil
+}
@@ -0,0 +1,94 @@
+package mngapitest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openware/pkg/mngapi/barong"
+	"github.com/openware/pkg/mngapi/mngapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBarong(t *testing.T) *barong.Client {
+	verifier, privateKey := generateKey(t)
+
+	fake := mngapitest.NewBarong(verifier)
+	fake.AddUser(barong.User{UID: "ID873B710D88", Email: "john@barong.io", Level: 3})
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	client, err := barong.New(srv.URL, "applogic", "RS256", privateKey)
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestBarongUsersAndLabels(t *testing.T) {
+	client := newBarong(t)
+	ctx := context.Background()
+	uid := "ID873B710D88"
+
+	user, err := client.GetUserContext(ctx, barong.GetUserParams{Email: "john@barong.io"})
+	require.NoError(t, err)
+	assert.Equal(t, uid, user.UID)
+	assert.Equal(t, "member", user.Role)
+
+	_, err = client.GetUserContext(ctx, barong.GetUserParams{UID: "unknown"})
+	requireStatus(t, err, http.StatusNotFound, "user.doesnt_exist")
+
+	updated, err := client.UpdateUserContext(ctx, barong.UpdateUserParams{UID: uid, Role: "admin"})
+	require.NoError(t, err)
+	assert.Equal(t, "admin", updated.Role)
+
+	level := 0
+	updated, err = client.UpdateUserContext(ctx, barong.UpdateUserParams{UID: uid, Level: &level})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), updated.Level)
+	assert.Equal(t, "admin", updated.Role)
+
+	_, err = client.CreateLabelContext(ctx, barong.LabelParams{UserUID: uid, Key: "email", Value: "verified"})
+	require.NoError(t, err)
+	_, err = client.CreateLabelContext(ctx, barong.LabelParams{UserUID: uid, Key: "email", Value: "verified"})
+	requireStatus(t, err, http.StatusUnprocessableEntity, "key.taken")
+
+	label, err := client.UpdateLabelContext(ctx, barong.LabelParams{UserUID: uid, Key: "email", Value: "rejected"})
+	require.NoError(t, err)
+	assert.Equal(t, "rejected", label.Value)
+
+	_, err = client.DeleteLabelContext(ctx, barong.DeleteLabelParams{UserUID: uid, Key: "email"})
+	require.NoError(t, err)
+
+	labels, err := client.GetLabelsContext(ctx, uid)
+	require.NoError(t, err)
+	assert.Empty(t, labels)
+}
+
+func TestBarongServiceAccounts(t *testing.T) {
+	client := newBarong(t)
+	ctx := context.Background()
+
+	sa, err := client.CreateServiceAccountContext(ctx, barong.CreateServiceAccountParams{OwnerUID: "ID873B710D88", Role: "service_account"})
+	require.NoError(t, err)
+	assert.Equal(t, "active", sa.State)
+	assert.Equal(t, uint64(3), sa.Level)
+
+	key, err := client.CreateAPIKeyContext(ctx, barong.CreateAPIKeyParams{UID: sa.UID, Algorithm: "HS256"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, key.Secret)
+
+	keys, err := client.GetAPIKeysContext(ctx, barong.GetAPIKeysParams{UID: sa.UID})
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Empty(t, keys[0].Secret)
+
+	sa, err = client.DeleteServiceAccountByUIDContext(ctx, sa.UID)
+	require.NoError(t, err)
+	assert.Equal(t, "disabled", sa.State)
+
+	accounts, err := client.GetServiceAccountsContext(ctx, barong.GetServiceAccountsParams{State: "active"})
+	require.NoError(t, err)
+	assert.Empty(t, accounts)
+}
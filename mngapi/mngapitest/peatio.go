@@ -0,0 +1,1095 @@
+package mngapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/openware/pkg/mngapi"
+	"github.com/openware/pkg/mngapi/peatio"
+	"github.com/shopspring/decimal"
+)
+
+// Peatio is an in-memory peatio management API, serving the endpoints of peatio.Client
+// for currencies, blockchain currencies, engines, markets, members, wallets, deposits,
+// withdraws and account balances.
+// Withdraws lock the member funds until they succeed or are canceled.
+//
+//	srv := httptest.NewServer(mngapitest.NewPeatio(verifier))
+//	client, err := peatio.New(srv.URL, "applogic", "RS256", privateKey)
+type Peatio struct {
+	*server
+
+	currencies []*peatio.Currency
+	networks   []*peatio.BlockchainCurrency
+	engines    []*peatio.Engine
+	engineKeys map[int]engineCredentials
+	markets    []*peatio.Market
+	members    []*peatio.Member
+	wallets    []*peatio.Wallet
+	deposits   []*peatio.Deposit
+	withdraws  []*peatio.Withdraw
+	accounts   map[string]*account
+}
+
+// engineCredentials are the key and secret of an engine, not listed by the API
+type engineCredentials struct {
+	key    string
+	secret string
+}
+
+type account struct {
+	balance decimal.Decimal
+	locked  decimal.Decimal
+}
+
+// NewPeatio returns an empty peatio management API accepting requests signed by the verifier keys,
+// every key id in required must have signed the requests
+func NewPeatio(verifier *mngapi.Verifier, required ...string) *Peatio {
+	p := &Peatio{
+		server:     newServer(verifier, required),
+		engineKeys: make(map[int]engineCredentials),
+		accounts:   make(map[string]*account),
+	}
+
+	p.handle(http.MethodPost, "currencies/create", p.createCurrency)
+	p.handle(http.MethodPut, "currencies/update", p.updateCurrency)
+	p.handle(http.MethodPost, "currencies/list", p.listCurrencies)
+	p.handle(http.MethodPost, "currencies/*", p.getCurrency)
+
+	p.handle(http.MethodPost, "blockchain_currencies/new", p.createBlockchainCurrency)
+	p.handle(http.MethodPut, "blockchain_currencies/update", p.updateBlockchainCurrency)
+	p.handle(http.MethodPost, "blockchain_currencies/*", p.getBlockchainCurrency)
+
+	p.handle(http.MethodPost, "engines/new", p.createEngine)
+	p.handle(http.MethodPost, "engines/update", p.updateEngine)
+	p.handle(http.MethodPost, "engines/get", p.listEngines)
+
+	p.handle(http.MethodPost, "markets/new", p.createMarket)
+	p.handle(http.MethodPut, "markets/update", p.updateMarket)
+	p.handle(http.MethodPost, "markets/list", p.listMarkets)
+	p.handle(http.MethodPost, "markets/*", p.getMarket)
+
+	p.handle(http.MethodPost, "members", p.createMember)
+	p.handle(http.MethodPost, "members/group", p.setMemberGroup)
+	p.handle(http.MethodPost, "members/list", p.listMembers)
+
+	p.handle(http.MethodPost, "wallets/new", p.createWallet)
+	p.handle(http.MethodPost, "wallets/update", p.updateWallet)
+	p.handle(http.MethodPost, "wallets", p.listWallets)
+	p.handle(http.MethodPost, "wallets/*", p.getWallet)
+
+	p.handle(http.MethodPost, "deposits/new", p.createDeposit)
+	p.handle(http.MethodPut, "deposits/state", p.updateDepositState)
+	p.handle(http.MethodPost, "deposits/get", p.getDeposit)
+	p.handle(http.MethodPost, "deposits", p.listDeposits)
+
+	p.handle(http.MethodPost, "withdraws/new", p.createWithdraw)
+	p.handle(http.MethodPut, "withdraws/action", p.withdrawAction)
+	p.handle(http.MethodPost, "withdraws/get", p.getWithdraw)
+	p.handle(http.MethodPost, "withdraws", p.listWithdraws)
+
+	p.handle(http.MethodPost, "accounts/balance", p.getBalance)
+	p.handle(http.MethodPost, "accounts/balances", p.listBalances)
+
+	return p
+}
+
+func (p *Peatio) createCurrency(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.CreateCurrencyParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	if params.Code == "" {
+		return nil, errorf(http.StatusUnprocessableEntity, "management.currency.missing_code")
+	}
+	if p.currency(params.Code) != nil {
+		return nil, errorf(http.StatusUnprocessableEntity, "management.currency.already_exists")
+	}
+
+	price, err := parseDecimal(params.Price)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &peatio.Currency{
+		ID:          params.Code,
+		Code:        params.Code,
+		Name:        params.Name,
+		Description: params.Description,
+		Homepage:    params.Homepage,
+		Price:       price,
+		Status:      withDefault(params.Status, "enabled"),
+		Type:        withDefault(params.Type, "coin"),
+		Precision:   8,
+		Position:    uint64(params.Position),
+		IconURL:     params.IconURL,
+		Networks:    []peatio.BlockchainCurrency{},
+	}
+	if params.Precision > 0 {
+		c.Precision = uint64(params.Precision)
+	}
+
+	p.currencies = append(p.currencies, c)
+	return c, nil
+}
+
+func (p *Peatio) updateCurrency(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.UpdateCurrencyParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	c := p.currency(params.ID)
+	if c == nil {
+		return nil, errorf(http.StatusNotFound, "Couldn't find record.")
+	}
+
+	if params.Name != "" {
+		c.Name = params.Name
+	}
+	if params.Position > 0 {
+		c.Position = uint64(params.Position)
+	}
+	if params.Status != "" {
+		c.Status = params.Status
+	}
+	if params.Precision > 0 {
+		c.Precision = uint64(params.Precision)
+	}
+	if params.IconURL != "" {
+		c.IconURL = params.IconURL
+	}
+
+	return c, nil
+}
+
+func (p *Peatio) listCurrencies(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.CurrenciesListParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	currencies := []*peatio.Currency{}
+	for _, c := range p.currencies {
+		if params.Type == "" || c.Type == params.Type {
+			currencies = append(currencies, c)
+		}
+	}
+
+	return paginate(currencies, params.Page, params.Limit), nil
+}
+
+func (p *Peatio) getCurrency(path []string, _ json.RawMessage) (interface{}, error) {
+	c := p.currency(path[1])
+	if c == nil {
+		return nil, errorf(http.StatusNotFound, "Couldn't find record.")
+	}
+
+	return c, nil
+}
+
+func (p *Peatio) createBlockchainCurrency(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.CreateBlockchainCurrencyParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	c := p.currency(params.CurrencyID)
+	if c == nil {
+		return nil, errorf(http.StatusUnprocessableEntity, "management.blockchain_currency.currency_not_found")
+	}
+	for _, n := range c.Networks {
+		if n.BlockchainKey == params.BlockchainKey {
+			return nil, errorf(http.StatusUnprocessableEntity, "management.blockchain_currency.already_exists")
+		}
+	}
+
+	n := &peatio.BlockchainCurrency{
+		ID:              strconv.Itoa(len(p.networks) + 1),
+		CurrencyID:      c.ID,
+		BlockchainKey:   params.BlockchainKey,
+		ParentID:        params.ParentID,
+		Status:          withDefault(params.Status, "enabled"),
+		DepositEnabled:  params.DepositEnabled,
+		WithdrawEnabled: params.WithdrawEnabled,
+		BaseFactor:      uint64(params.BaseFactor),
+		Options:         params.Options,
+	}
+
+	for _, f := range []struct {
+		value string
+		field *peatio.Decimal
+	}{
+		{params.DepositFee, &n.DepositFee},
+		{params.MinDepositAmount, &n.MinDepositAmount},
+		{params.WithdrawFee, &n.WithdrawFee},
+		{params.MinWithdrawAmount, &n.MinWithdrawAmount},
+		{params.MinCollectionAmount, &n.MinCollectionAmount},
+	} {
+		d, err := parseDecimal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		*f.field = d
+	}
+
+	p.networks = append(p.networks, n)
+	c.Networks = append(c.Networks, *n)
+	return n, nil
+}
+
+func (p *Peatio) updateBlockchainCurrency(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.UpdateBlockchainCurrencyParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	n := p.network(params.ID)
+	if n == nil {
+		return nil, errorf(http.StatusNotFound, "Couldn't find record.")
+	}
+
+	for _, f := range []struct {
+		value string
+		field *peatio.Decimal
+	}{
+		{params.DepositFee, &n.DepositFee},
+		{params.MinDepositAmount, &n.MinDepositAmount},
+		{params.WithdrawFee, &n.WithdrawFee},
+		{params.MinWithdrawAmount, &n.MinWithdrawAmount},
+		{params.MinCollectionAmount, &n.MinCollectionAmount},
+	} {
+		if f.value == "" {
+			continue
+		}
+		d, err := parseDecimal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		*f.field = d
+	}
+	if params.DepositEnabled != nil {
+		n.DepositEnabled = *params.DepositEnabled
+	}
+	if params.WithdrawEnabled != nil {
+		n.WithdrawEnabled = *params.WithdrawEnabled
+	}
+	if params.Status != "" {
+		n.Status = params.Status
+	}
+	if params.Options != nil {
+		n.Options = params.Options
+	}
+
+	c := p.currency(n.CurrencyID)
+	for i := range c.Networks {
+		if c.Networks[i].ID == n.ID {
+			c.Networks[i] = *n
+		}
+	}
+
+	return n, nil
+}
+
+func (p *Peatio) getBlockchainCurrency(path []string, _ json.RawMessage) (interface{}, error) {
+	n := p.network(path[1])
+	if n == nil {
+		return nil, errorf(http.StatusNotFound, "Couldn't find record.")
+	}
+
+	return n, nil
+}
+
+func (p *Peatio) createEngine(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.CreateEngineParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	if params.Name == "" {
+		return nil, errorf(http.StatusUnprocessableEntity, "management.engine.missing_name")
+	}
+	if p.engine(params.Name) != nil {
+		return nil, errorf(http.StatusUnprocessableEntity, "management.engine.already_exists")
+	}
+
+	e := &peatio.Engine{
+		ID:     len(p.engines) + 1,
+		Name:   params.Name,
+		Driver: params.Driver,
+		UID:    params.UID,
+		URL:    params.URL,
+		State:  engineState(params.State),
+	}
+
+	p.engines = append(p.engines, e)
+	p.engineKeys[e.ID] = engineCredentials{key: params.Key, secret: params.Secret}
+	return e, nil
+}
+
+func (p *Peatio) updateEngine(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.UpdateEngineParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	n, err := strconv.Atoi(params.ID)
+	if err != nil || n < 1 || n > len(p.engines) {
+		return nil, errorf(http.StatusNotFound, "Couldn't find record.")
+	}
+
+	e := p.engines[n-1]
+	if params.Name != "" {
+		e.Name = params.Name
+	}
+	if params.Driver != "" {
+		e.Driver = params.Driver
+	}
+	if params.UID != "" {
+		e.UID = params.UID
+	}
+	if params.URL != "" {
+		e.URL = params.URL
+	}
+	if params.State != nil {
+		e.State = engineState(*params.State)
+	}
+	credentials := p.engineKeys[e.ID]
+	if params.Key != "" {
+		credentials.key = params.Key
+	}
+	if params.Secret != "" {
+		credentials.secret = params.Secret
+	}
+	p.engineKeys[e.ID] = credentials
+
+	return e, nil
+}
+
+// EngineCredentials returns the key and secret of an engine, which the API never lists
+func (p *Peatio) EngineCredentials(name string) (key, secret string) {
+	e := p.engine(name)
+	if e == nil {
+		return "", ""
+	}
+
+	credentials := p.engineKeys[e.ID]
+	return credentials.key, credentials.secret
+}
+
+func (p *Peatio) listEngines(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.GetEngineParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	engines := []*peatio.Engine{}
+	for _, e := range p.engines {
+		if matches(params.Name, e.Name) {
+			engines = append(engines, e)
+		}
+	}
+
+	return engines, nil
+}
+
+func (p *Peatio) createMarket(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.CreateMarketParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	if p.currency(params.BaseCurrency) == nil || p.currency(params.QuoteCurrency) == nil {
+		return nil, errorf(http.StatusUnprocessableEntity, "management.market.currency_not_found")
+	}
+
+	id := params.BaseCurrency + params.QuoteCurrency
+	if p.market(id) != nil {
+		return nil, errorf(http.StatusUnprocessableEntity, "management.market.already_exists")
+	}
+
+	var engineID int
+	if params.EngineName != "" {
+		e := p.engine(params.EngineName)
+		if e == nil {
+			return nil, errorf(http.StatusUnprocessableEntity, "management.market.engine_not_found")
+		}
+		engineID = e.ID
+	}
+
+	m := &peatio.Market{
+		ID:              id,
+		Name:            fmt.Sprintf("%s/%s", params.BaseCurrency, params.QuoteCurrency),
+		BaseUnit:        params.BaseCurrency,
+		QuoteUnit:       params.QuoteCurrency,
+		AmountPrecision: int(params.AmountPrecision),
+		PricePrecision:  int(params.PricePrecision),
+		State:           withDefault(params.State, "enabled"),
+		Position:        int(params.Position),
+		EngineID:        engineID,
+		CreatedAt:       now(),
+		UpdatedAt:       now(),
+	}
+
+	var err error
+	if m.MinPrice, err = parseDecimal(params.MinPrice); err != nil {
+		return nil, err
+	}
+	if m.MaxPrice, err = parseDecimal(params.MaxPrice); err != nil {
+		return nil, err
+	}
+	if m.MinAmount, err = parseDecimal(params.MinAmount); err != nil {
+		return nil, err
+	}
+
+	p.markets = append(p.markets, m)
+	return m, nil
+}
+
+func (p *Peatio) updateMarket(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.UpdateMarketParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	m := p.market(params.ID)
+	if m == nil {
+		return nil, errorf(http.StatusNotFound, "Couldn't find record.")
+	}
+
+	for _, f := range []struct {
+		value string
+		field *peatio.Decimal
+	}{
+		{params.MinPrice, &m.MinPrice},
+		{params.MaxPrice, &m.MaxPrice},
+		{params.MinAmount, &m.MinAmount},
+	} {
+		if f.value == "" {
+			continue
+		}
+		d, err := parseDecimal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		*f.field = d
+	}
+	if params.EngineID != "" {
+		n, err := strconv.Atoi(params.EngineID)
+		if err != nil || n < 1 || n > len(p.engines) {
+			return nil, errorf(http.StatusUnprocessableEntity, "management.market.engine_not_found")
+		}
+		m.EngineID = n
+	}
+	if params.AmountPrecision > 0 {
+		m.AmountPrecision = int(params.AmountPrecision)
+	}
+	if params.PricePrecision > 0 {
+		m.PricePrecision = int(params.PricePrecision)
+	}
+	m.UpdatedAt = now()
+
+	return m, nil
+}
+
+func (p *Peatio) listMarkets(_ []string, _ json.RawMessage) (interface{}, error) {
+	return append([]*peatio.Market{}, p.markets...), nil
+}
+
+func (p *Peatio) getMarket(path []string, _ json.RawMessage) (interface{}, error) {
+	m := p.market(path[1])
+	if m == nil {
+		return nil, errorf(http.StatusNotFound, "Couldn't find record.")
+	}
+
+	return m, nil
+}
+
+func (p *Peatio) createMember(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.CreateMemberParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	if params.UID == "" {
+		return nil, errorf(http.StatusUnprocessableEntity, "management.member.missing_uid")
+	}
+	if p.member(params.UID) != nil {
+		return nil, errorf(http.StatusUnprocessableEntity, "management.member.already_exists")
+	}
+
+	m := &peatio.Member{
+		UID:   params.UID,
+		Email: params.Email,
+		Level: params.Level,
+		Role:  withDefault(params.Role, "member"),
+		Group: withDefault(params.Group, "vip-0"),
+		State: withDefault(params.State, "active"),
+	}
+
+	p.members = append(p.members, m)
+	return m, nil
+}
+
+func (p *Peatio) setMemberGroup(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.SetMemberGroupParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	m := p.member(params.UID)
+	if m == nil {
+		return nil, errorf(http.StatusNotFound, "Couldn't find record.")
+	}
+
+	m.Group = params.Group
+	return m, nil
+}
+
+func (p *Peatio) listMembers(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.GetMembersParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	members := []*peatio.Member{}
+	for _, m := range p.members {
+		if matches(params.UID, m.UID) && matches(params.Email, m.Email) &&
+			matches(params.Group, m.Group) && matches(params.State, m.State) {
+			members = append(members, m)
+		}
+	}
+
+	return paginate(members, params.Page, params.Limit), nil
+}
+
+func (p *Peatio) createWallet(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.CreateWalletParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	for _, code := range params.Currencies {
+		if p.currency(code) == nil {
+			return nil, errorf(http.StatusUnprocessableEntity, "management.wallet.currency_not_found")
+		}
+	}
+
+	maxBalance, err := parseDecimal(params.MaxBalance)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &peatio.Wallet{
+		ID:            len(p.wallets) + 1,
+		Name:          params.Name,
+		Kind:          params.Kind,
+		Currencies:    append([]string{}, params.Currencies...),
+		Address:       params.Address,
+		Gateway:       params.Gateway,
+		MaxBalance:    maxBalance,
+		BlockchainKey: params.BlockchainKey,
+		Status:        withDefault(params.Status, "active"),
+	}
+
+	p.wallets = append(p.wallets, w)
+	return w, nil
+}
+
+func (p *Peatio) updateWallet(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.UpdateWalletParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	w, err := p.wallet(params.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Name != "" {
+		w.Name = params.Name
+	}
+	if params.Address != "" {
+		w.Address = params.Address
+	}
+	if params.Gateway != "" {
+		w.Gateway = params.Gateway
+	}
+	if params.Kind != "" {
+		w.Kind = params.Kind
+	}
+	if params.BlockchainKey != "" {
+		w.BlockchainKey = params.BlockchainKey
+	}
+	if params.Currencies != nil {
+		w.Currencies = append([]string{}, params.Currencies...)
+	}
+	if params.Status != "" {
+		w.Status = params.Status
+	}
+	if params.MaxBalance != "" {
+		if w.MaxBalance, err = parseDecimal(params.MaxBalance); err != nil {
+			return nil, err
+		}
+	}
+
+	return w, nil
+}
+
+func (p *Peatio) listWallets(_ []string, _ json.RawMessage) (interface{}, error) {
+	return append([]*peatio.Wallet{}, p.wallets...), nil
+}
+
+func (p *Peatio) getWallet(path []string, _ json.RawMessage) (interface{}, error) {
+	return p.wallet(path[1])
+}
+
+func (p *Peatio) createDeposit(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.CreateDepositParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	c, err := p.validateTransfer(params.UID, params.Currency, params.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	tid := params.TID
+	if tid == "" {
+		tid = fmt.Sprintf("TID%010X", len(p.deposits)+1)
+	}
+	if p.deposit(tid) != nil {
+		return nil, errorf(http.StatusUnprocessableEntity, "management.deposit.tid_already_exists")
+	}
+
+	d := &peatio.Deposit{
+		ID:           uint64(len(p.deposits) + 1),
+		TID:          tid,
+		Currency:     c.ID,
+		UID:          params.UID,
+		Type:         c.Type,
+		Amount:       params.Amount,
+		State:        "submitted",
+		CreatedAt:    now(),
+		TransferType: params.TransferType,
+	}
+
+	switch params.State {
+	case "", "submitted":
+	case "accepted":
+		p.acceptDeposit(d)
+	default:
+		return nil, errorf(http.StatusUnprocessableEntity, "management.deposit.invalid_state")
+	}
+
+	p.deposits = append(p.deposits, d)
+	return d, nil
+}
+
+func (p *Peatio) updateDepositState(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.UpdateDepositStateParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	d := p.deposit(params.TID)
+	if d == nil {
+		return nil, errorf(http.StatusNotFound, "Couldn't find record.")
+	}
+
+	if d.State != "submitted" {
+		return nil, errorf(http.StatusUnprocessableEntity, "management.deposit.cannot_%s", params.State)
+	}
+
+	switch params.State {
+	case "accepted":
+		p.acceptDeposit(d)
+	case "rejected", "canceled":
+		d.State = params.State
+	default:
+		return nil, errorf(http.StatusUnprocessableEntity, "management.deposit.invalid_state")
+	}
+
+	return d, nil
+}
+
+func (p *Peatio) acceptDeposit(d *peatio.Deposit) {
+	a := p.account(d.UID, d.Currency)
+	a.balance = a.balance.Add(d.Amount.Decimal)
+
+	completedAt := now()
+	d.State = "accepted"
+	d.CompletedAt = &completedAt
+}
+
+func (p *Peatio) getDeposit(_ []string, data json.RawMessage) (interface{}, error) {
+	params := struct {
+		TID string `json:"tid"`
+	}{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	d := p.deposit(params.TID)
+	if d == nil {
+		return nil, errorf(http.StatusNotFound, "Couldn't find record.")
+	}
+
+	return d, nil
+}
+
+func (p *Peatio) listDeposits(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.GetDepositsParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	deposits := []*peatio.Deposit{}
+	for _, d := range p.deposits {
+		if matches(params.UID, d.UID) && matches(params.Currency, d.Currency) &&
+			matches(params.State, d.State) && int64(d.ID) > params.FromID {
+			deposits = append(deposits, d)
+		}
+	}
+
+	return paginate(deposits, params.Page, params.Limit), nil
+}
+
+func (p *Peatio) createWithdraw(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.CreateWithdrawParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	c, err := p.validateTransfer(params.UID, params.Currency, params.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	tid := params.TID
+	if tid == "" {
+		tid = fmt.Sprintf("TID%010X", len(p.withdraws)+1)
+	}
+	if p.withdraw(tid) != nil {
+		return nil, errorf(http.StatusUnprocessableEntity, "management.withdraw.tid_already_exists")
+	}
+
+	a := p.account(params.UID, c.ID)
+	if a.balance.LessThan(params.Amount.Decimal) {
+		return nil, errorf(http.StatusUnprocessableEntity, "account.withdraw.insufficient_balance")
+	}
+
+	state := "prepared"
+	switch params.Action {
+	case "":
+	case "process":
+		state = "accepted"
+	default:
+		return nil, errorf(http.StatusUnprocessableEntity, "management.withdraw.invalid_action")
+	}
+
+	a.balance = a.balance.Sub(params.Amount.Decimal)
+	a.locked = a.locked.Add(params.Amount.Decimal)
+
+	w := &peatio.Withdraw{
+		ID:           uint64(len(p.withdraws) + 1),
+		TID:          tid,
+		UID:          params.UID,
+		Currency:     c.ID,
+		Note:         params.Note,
+		Type:         c.Type,
+		Amount:       params.Amount,
+		Fee:          peatio.MustDecimal("0.0"),
+		RID:          params.RID,
+		State:        state,
+		CreatedAt:    now(),
+		TransferType: params.TransferType,
+	}
+
+	p.withdraws = append(p.withdraws, w)
+	return w, nil
+}
+
+// withdrawTransitions lists the withdraw states allowed for every action, with the resulting state
+var withdrawTransitions = map[string]struct {
+	from []string
+	to   string
+}{
+	"process": {from: []string{"prepared"}, to: "accepted"},
+	"cancel":  {from: []string{"prepared", "accepted"}, to: "canceled"},
+	"reject":  {from: []string{"accepted"}, to: "rejected"},
+	"success": {from: []string{"accepted"}, to: "succeed"},
+}
+
+func (p *Peatio) withdrawAction(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.WithdrawActionParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	w := p.withdraw(params.TID)
+	if w == nil {
+		return nil, errorf(http.StatusNotFound, "Couldn't find record.")
+	}
+
+	transition, ok := withdrawTransitions[params.Action]
+	if !ok {
+		return nil, errorf(http.StatusUnprocessableEntity, "management.withdraw.invalid_action")
+	}
+	if !contains(transition.from, w.State) {
+		return nil, errorf(http.StatusUnprocessableEntity, "management.withdraw.cannot_%s", params.Action)
+	}
+
+	a := p.account(w.UID, w.Currency)
+	a.locked = a.locked.Sub(w.Amount.Decimal)
+	switch transition.to {
+	case "accepted":
+		// Funds stay locked until the withdraw succeeds
+		a.locked = a.locked.Add(w.Amount.Decimal)
+	case "canceled", "rejected":
+		a.balance = a.balance.Add(w.Amount.Decimal)
+	case "succeed":
+		w.BlockchainTxID = params.TxID
+	}
+
+	w.State = transition.to
+	return w, nil
+}
+
+func (p *Peatio) getWithdraw(_ []string, data json.RawMessage) (interface{}, error) {
+	params := struct {
+		TID string `json:"tid"`
+	}{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	w := p.withdraw(params.TID)
+	if w == nil {
+		return nil, errorf(http.StatusNotFound, "Couldn't find record.")
+	}
+
+	return w, nil
+}
+
+func (p *Peatio) listWithdraws(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.GetWithdrawsParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	withdraws := []*peatio.Withdraw{}
+	for _, w := range p.withdraws {
+		if matches(params.UID, w.UID) && matches(params.Currency, w.Currency) &&
+			matches(params.State, w.State) && matches(params.RID, w.RID) {
+			withdraws = append(withdraws, w)
+		}
+	}
+
+	return paginate(withdraws, params.Page, params.Limit), nil
+}
+
+func (p *Peatio) getBalance(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.GetAccountBalanceParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	if p.member(params.UID) == nil || p.currency(params.Currency) == nil {
+		return nil, errorf(http.StatusNotFound, "Couldn't find record.")
+	}
+
+	return p.balance(params.UID, params.Currency), nil
+}
+
+func (p *Peatio) listBalances(_ []string, data json.RawMessage) (interface{}, error) {
+	params := peatio.GetAccountBalancesParams{}
+	if err := decode(data, &params); err != nil {
+		return nil, err
+	}
+
+	if p.currency(params.Currency) == nil {
+		return nil, errorf(http.StatusNotFound, "Couldn't find record.")
+	}
+
+	balances := []*peatio.Balance{}
+	for _, m := range p.members {
+		balances = append(balances, p.balance(m.UID, params.Currency))
+	}
+
+	return paginate(balances, params.Page, params.Limit), nil
+}
+
+// validateTransfer checks the member and currency of a deposit or withdraw and the amount precision
+func (p *Peatio) validateTransfer(uid, currency string, amount peatio.Decimal) (*peatio.Currency, error) {
+	if p.member(uid) == nil {
+		return nil, errorf(http.StatusUnprocessableEntity, "management.member.doesnt_exist")
+	}
+
+	c := p.currency(currency)
+	if c == nil {
+		return nil, errorf(http.StatusUnprocessableEntity, "management.currency.doesnt_exist")
+	}
+
+	if !amount.IsPositive() {
+		return nil, errorf(http.StatusUnprocessableEntity, "management.amount.non_positive")
+	}
+	if err := amount.ValidatePrecision(int32(c.Precision)); err != nil {
+		return nil, errorf(http.StatusUnprocessableEntity, "management.amount.invalid_precision")
+	}
+
+	return c, nil
+}
+
+func (p *Peatio) balance(uid, currency string) *peatio.Balance {
+	a := p.account(uid, currency)
+	return &peatio.Balance{
+		UID:     uid,
+		Balance: peatio.Decimal{Decimal: a.balance},
+		Locked:  peatio.Decimal{Decimal: a.locked},
+	}
+}
+
+func (p *Peatio) account(uid, currency string) *account {
+	key := uid + "/" + currency
+	a, ok := p.accounts[key]
+	if !ok {
+		a = &account{}
+		p.accounts[key] = a
+	}
+
+	return a
+}
+
+func (p *Peatio) currency(code string) *peatio.Currency {
+	for _, c := range p.currencies {
+		if c.ID == code {
+			return c
+		}
+	}
+
+	return nil
+}
+
+func (p *Peatio) network(id string) *peatio.BlockchainCurrency {
+	for _, n := range p.networks {
+		if n.ID == id {
+			return n
+		}
+	}
+
+	return nil
+}
+
+func (p *Peatio) engine(name string) *peatio.Engine {
+	for _, e := range p.engines {
+		if e.Name == name {
+			return e
+		}
+	}
+
+	return nil
+}
+
+func (p *Peatio) market(id string) *peatio.Market {
+	for _, m := range p.markets {
+		if m.ID == id {
+			return m
+		}
+	}
+
+	return nil
+}
+
+func (p *Peatio) member(uid string) *peatio.Member {
+	for _, m := range p.members {
+		if m.UID == uid {
+			return m
+		}
+	}
+
+	return nil
+}
+
+func (p *Peatio) wallet(id string) (*peatio.Wallet, error) {
+	n, err := strconv.Atoi(id)
+	if err != nil || n < 1 || n > len(p.wallets) {
+		return nil, errorf(http.StatusNotFound, "Couldn't find record.")
+	}
+
+	return p.wallets[n-1], nil
+}
+
+func (p *Peatio) deposit(tid string) *peatio.Deposit {
+	for _, d := range p.deposits {
+		if d.TID == tid {
+			return d
+		}
+	}
+
+	return nil
+}
+
+func (p *Peatio) withdraw(tid string) *peatio.Withdraw {
+	for _, w := range p.withdraws {
+		if w.TID == tid {
+			return w
+		}
+	}
+
+	return nil
+}
+
+func engineState(state int) string {
+	if state == 0 {
+		return "offline"
+	}
+
+	return "online"
+}
+
+func parseDecimal(value string) (peatio.Decimal, error) {
+	if value == "" {
+		return peatio.Decimal{}, nil
+	}
+
+	d, err := peatio.NewDecimal(value)
+	if err != nil {
+		return peatio.Decimal{}, errorf(http.StatusUnprocessableEntity, "invalid decimal %q", value)
+	}
+
+	return d, nil
+}
+
+func withDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
+
+// matches reports whether value matches an optional filter
+func matches(filter, value string) bool {
+	return filter == "" || filter == value
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,291 @@
+package mngapitest_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openware/pkg/mngapi"
+	"github.com/openware/pkg/mngapi/mngapitest"
+	"github.com/openware/pkg/mngapi/peatio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generateKey returns a verifier trusting a new RSA key under the applogic key id,
+// and the base64 encoded PEM private key expected by the clients
+func generateKey(t *testing.T) (*mngapi.Verifier, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	verifier := mngapi.NewVerifier()
+	verifier.AddRSAKey("applogic", &key.PublicKey)
+
+	block := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return verifier, base64.StdEncoding.EncodeToString(block)
+}
+
+func newPeatio(t *testing.T) (*peatio.Client, *mngapitest.Peatio) {
+	verifier, privateKey := generateKey(t)
+
+	fake := mngapitest.NewPeatio(verifier)
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	client, err := peatio.New(srv.URL, "applogic", "RS256", privateKey)
+	require.NoError(t, err)
+
+	return client, fake
+}
+
+func seedPeatio(t *testing.T, client *peatio.Client) {
+	ctx := context.Background()
+
+	_, err := client.CreateCurrencyContext(ctx, peatio.CreateCurrencyParams{Code: "eth", Name: "Ethereum", Precision: 8})
+	require.NoError(t, err)
+	_, err = client.CreateCurrencyContext(ctx, peatio.CreateCurrencyParams{Code: "usdt", Name: "Tether", Type: "fiat", Precision: 2})
+	require.NoError(t, err)
+	_, err = client.CreateMemberContext(ctx, peatio.CreateMemberParams{UID: "ID873B710D88", Email: "john@barong.io"})
+	require.NoError(t, err)
+}
+
+func requireStatus(t *testing.T, err error, status int, errors ...string) {
+	apiError := mngapi.ToAPIError(err)
+	require.NotNil(t, apiError)
+	assert.Equal(t, status, apiError.StatusCode)
+	if len(errors) > 0 {
+		assert.Equal(t, errors, apiError.Errors)
+	}
+}
+
+func TestPeatioAuthentication(t *testing.T) {
+	_, fake := newPeatio(t)
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	t.Run("Unsigned request", func(t *testing.T) {
+		res, err := http.Post(srv.URL+"/currencies/list", "application/json", nil)
+		require.NoError(t, err)
+		res.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	})
+
+	t.Run("Unknown signer", func(t *testing.T) {
+		_, privateKey := generateKey(t)
+		client, err := peatio.New(srv.URL, "applogic", "RS256", privateKey)
+		require.NoError(t, err)
+
+		_, err = client.GetCurrenciesListContext(context.Background(), peatio.CurrenciesListParams{})
+		requireStatus(t, err, http.StatusUnauthorized)
+	})
+}
+
+func TestPeatioCurrenciesAndMarkets(t *testing.T) {
+	client, _ := newPeatio(t)
+	seedPeatio(t, client)
+	ctx := context.Background()
+
+	currency, err := client.GetCurrencyByCodeContext(ctx, "eth")
+	require.NoError(t, err)
+	assert.Equal(t, "Ethereum", currency.Name)
+
+	_, err = client.CreateCurrencyContext(ctx, peatio.CreateCurrencyParams{Code: "eth"})
+	requireStatus(t, err, http.StatusUnprocessableEntity, "management.currency.already_exists")
+
+	currencies, err := client.GetCurrenciesListContext(ctx, peatio.CurrenciesListParams{Type: "fiat"})
+	require.NoError(t, err)
+	require.Len(t, *currencies, 1)
+	assert.Equal(t, "usdt", (*currencies)[0].ID)
+
+	_, err = client.CreateMarketContext(ctx, peatio.CreateMarketParams{BaseCurrency: "btc", QuoteCurrency: "usdt"})
+	requireStatus(t, err, http.StatusUnprocessableEntity, "management.market.currency_not_found")
+
+	market, err := client.CreateMarketContext(ctx, peatio.CreateMarketParams{
+		BaseCurrency:    "eth",
+		QuoteCurrency:   "usdt",
+		MinPrice:        "0.01",
+		MinAmount:       "0.0001",
+		AmountPrecision: 4,
+		PricePrecision:  2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ethusdt", market.ID)
+	assert.Equal(t, "0.01", market.MinPrice.String())
+
+	market, err = client.UpdateMarketContext(ctx, peatio.UpdateMarketParams{ID: "ethusdt", MinPrice: "0.1"})
+	require.NoError(t, err)
+	assert.Equal(t, "0.1", market.MinPrice.String())
+
+	markets, err := client.GetMarketsContext(ctx)
+	require.NoError(t, err)
+	assert.Len(t, markets, 1)
+
+	_, err = client.GetMarketByIDContext(ctx, "btcusdt")
+	requireStatus(t, err, http.StatusNotFound)
+}
+
+func TestPeatioDepositsAndWithdraws(t *testing.T) {
+	client, _ := newPeatio(t)
+	seedPeatio(t, client)
+	ctx := context.Background()
+	uid := "ID873B710D88"
+
+	balance := func() (string, string) {
+		b, err := client.GetAccountBalanceContext(ctx, peatio.GetAccountBalanceParams{UID: uid, Currency: "eth"})
+		require.NoError(t, err)
+		return b.Balance.Decimal.String(), b.Locked.Decimal.String()
+	}
+
+	t.Run("Deposits credit accepted amounts", func(t *testing.T) {
+		_, err := client.CreateDepositContext(ctx, peatio.CreateDepositParams{UID: "unknown", Currency: "eth", Amount: peatio.MustDecimal("1")})
+		requireStatus(t, err, http.StatusUnprocessableEntity, "management.member.doesnt_exist")
+
+		deposit, err := client.CreateDepositContext(ctx, peatio.CreateDepositParams{UID: uid, Currency: "eth", Amount: peatio.MustDecimal("2.5")})
+		require.NoError(t, err)
+		assert.Equal(t, "submitted", deposit.State)
+		balanceValue, _ := balance()
+		assert.Equal(t, "0", balanceValue)
+
+		deposit, err = client.UpdateDepositStateContext(ctx, peatio.UpdateDepositStateParams{TID: deposit.TID, State: "accepted"})
+		require.NoError(t, err)
+		assert.Equal(t, "accepted", deposit.State)
+		assert.NotNil(t, deposit.CompletedAt)
+		balanceValue, _ = balance()
+		assert.Equal(t, "2.5", balanceValue)
+
+		_, err = client.CreateDepositContext(ctx, peatio.CreateDepositParams{UID: uid, Currency: "eth", Amount: peatio.MustDecimal("0.5"), State: "accepted"})
+		require.NoError(t, err)
+		balanceValue, _ = balance()
+		assert.Equal(t, "3", balanceValue)
+	})
+
+	t.Run("Withdraws lock funds", func(t *testing.T) {
+		_, err := client.CreateWithdrawContext(ctx, peatio.CreateWithdrawParams{UID: uid, Currency: "eth", Amount: peatio.MustDecimal("10")})
+		requireStatus(t, err, http.StatusUnprocessableEntity, "account.withdraw.insufficient_balance")
+
+		withdraw, err := client.CreateWithdrawContext(ctx, peatio.CreateWithdrawParams{UID: uid, TID: "TID1", Currency: "eth", Amount: peatio.MustDecimal("1")})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), withdraw.ID)
+		assert.Equal(t, "prepared", withdraw.State)
+		balanceValue, locked := balance()
+		assert.Equal(t, "2", balanceValue)
+		assert.Equal(t, "1", locked)
+
+		_, err = client.WithdrawActionContext(ctx, peatio.WithdrawActionParams{TID: "TID1", Action: "success"})
+		requireStatus(t, err, http.StatusUnprocessableEntity, "management.withdraw.cannot_success")
+
+		withdraw, err = client.WithdrawActionContext(ctx, peatio.WithdrawActionParams{TID: "TID1", Action: "process"})
+		require.NoError(t, err)
+		assert.Equal(t, "accepted", withdraw.State)
+
+		withdraw, err = client.WithdrawActionContext(ctx, peatio.WithdrawActionParams{TID: "TID1", Action: "success", TxID: "0xabc"})
+		require.NoError(t, err)
+		assert.Equal(t, "succeed", withdraw.State)
+		assert.Equal(t, "0xabc", withdraw.BlockchainTxID)
+		balanceValue, locked = balance()
+		assert.Equal(t, "2", balanceValue)
+		assert.Equal(t, "0", locked)
+	})
+
+	t.Run("Canceled withdraws unlock funds", func(t *testing.T) {
+		withdraw, err := client.CreateWithdrawContext(ctx, peatio.CreateWithdrawParams{UID: uid, TID: "TID2", Currency: "eth", Amount: peatio.MustDecimal("2"), Action: "process"})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), withdraw.ID)
+		assert.Equal(t, "accepted", withdraw.State)
+		balanceValue, locked := balance()
+		assert.Equal(t, "0", balanceValue)
+		assert.Equal(t, "2", locked)
+
+		_, err = client.WithdrawActionContext(ctx, peatio.WithdrawActionParams{TID: "TID2", Action: "cancel"})
+		require.NoError(t, err)
+		balanceValue, locked = balance()
+		assert.Equal(t, "2", balanceValue)
+		assert.Equal(t, "0", locked)
+	})
+
+	t.Run("Idempotent withdraws", func(t *testing.T) {
+		params := peatio.CreateWithdrawParams{UID: uid, TID: "TID3", Currency: "eth", Amount: peatio.MustDecimal("0.5")}
+		first, err := client.CreateWithdrawContext(ctx, params)
+		require.NoError(t, err)
+
+		// The retried request is answered from the Idempotency-Key cache
+		second, err := client.CreateWithdrawContext(ctx, params)
+		require.NoError(t, err)
+		assert.Equal(t, first, second)
+
+		withdraws, err := client.GetWithdrawsContext(ctx, peatio.GetWithdrawsParams{UID: uid})
+		require.NoError(t, err)
+		assert.Len(t, withdraws, 3)
+	})
+
+	t.Run("Precision", func(t *testing.T) {
+		// The usdt precision is fetched on first use and the amount rejected before sending
+		_, err := client.CreateDepositContext(ctx, peatio.CreateDepositParams{UID: uid, Currency: "usdt", Amount: peatio.MustDecimal("1.001")})
+		assert.ErrorIs(t, err, peatio.ErrPrecision)
+
+		_, err = client.CreateDepositContext(ctx, peatio.CreateDepositParams{UID: uid, Currency: "xrp", Amount: peatio.MustDecimal("1")})
+		requireStatus(t, err, http.StatusNotFound, "Couldn't find record.")
+	})
+}
+
+func TestPeatioPagination(t *testing.T) {
+	client, _ := newPeatio(t)
+	seedPeatio(t, client)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		_, err := client.CreateDepositContext(ctx, peatio.CreateDepositParams{UID: "ID873B710D88", Currency: "eth", Amount: peatio.MustDecimal("1")})
+		require.NoError(t, err)
+	}
+
+	deposits, err := client.IterateDeposits(peatio.GetDepositsParams{Limit: 2}).All(ctx)
+	require.NoError(t, err)
+	require.Len(t, deposits, 5)
+	assert.Equal(t, uint64(5), deposits[4].ID)
+
+	currencies, err := client.IterateCurrencies(peatio.CurrenciesListParams{Limit: 1}).All(ctx)
+	require.NoError(t, err)
+	require.Len(t, currencies, 2)
+	assert.Equal(t, "usdt", currencies[1].ID)
+}
+
+func TestLoadCurrencyPrecisions(t *testing.T) {
+	verifier, privateKey := generateKey(t)
+	fake := mngapitest.NewPeatio(verifier)
+
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		fake.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := peatio.New(srv.URL, "applogic", "RS256", privateKey)
+	require.NoError(t, err)
+	seedPeatio(t, client)
+	ctx := context.Background()
+
+	// Currencies beyond the first page are loaded too
+	for i := 0; i < int(mngapi.DefaultPageLimit); i++ {
+		_, err := client.CreateCurrencyContext(ctx, peatio.CreateCurrencyParams{Code: fmt.Sprintf("c%d", i), Precision: 4})
+		require.NoError(t, err)
+	}
+	_, err = client.CreateCurrencyContext(ctx, peatio.CreateCurrencyParams{Code: "trx", Precision: 1})
+	require.NoError(t, err)
+
+	paths = nil
+	require.NoError(t, client.LoadCurrencyPrecisions(ctx))
+	assert.Equal(t, []string{"/currencies/list", "/currencies/list"}, paths)
+
+	paths = nil
+	_, err = client.CreateDepositContext(ctx, peatio.CreateDepositParams{UID: "ID873B710D88", Currency: "trx", Amount: peatio.MustDecimal("0.01")})
+	assert.ErrorIs(t, err, peatio.ErrPrecision)
+	assert.Empty(t, paths)
+}
@@ -0,0 +1,197 @@
+package mngapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/openware/pkg/mngapi"
+)
+
+// httpError is returned by routes to respond with an error status
+type httpError struct {
+	StatusCode int
+	Errors     []string
+}
+
+func (e *httpError) Error() string {
+	return strings.Join(e.Errors, ", ")
+}
+
+func errorf(code int, format string, args ...interface{}) *httpError {
+	return &httpError{StatusCode: code, Errors: []string{fmt.Sprintf(format, args...)}}
+}
+
+// route handles a verified request, data holds the JWT data claim
+type route func(path []string, data json.RawMessage) (interface{}, error)
+
+type response struct {
+	status int
+	body   []byte
+}
+
+// server verifies the JWS envelopes of management API requests and dispatches them to the routes,
+// responses of requests with an Idempotency-Key header are replayed for the same key, method and path
+type server struct {
+	verifier *mngapi.Verifier
+	required []string
+	routes   map[string]route
+
+	mu          sync.Mutex
+	idempotency map[string]response
+}
+
+func newServer(verifier *mngapi.Verifier, required []string) *server {
+	return &server{
+		verifier:    verifier,
+		required:    required,
+		routes:      make(map[string]route),
+		idempotency: make(map[string]response),
+	}
+}
+
+// handle registers a route by method and path, "*" matches any path segment
+func (s *server) handle(method, pattern string, r route) {
+	s.routes[method+" "+strings.Trim(pattern, "/")] = r
+}
+
+func (s *server) match(method, path string) (route, []string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	// Literal segments take precedence over wildcards, e.g. currencies/list over currencies/*
+	var best route
+	bestWildcards := -1
+	for key, r := range s.routes {
+		parts := strings.SplitN(key, " ", 2)
+		if parts[0] != method {
+			continue
+		}
+
+		pattern := strings.Split(parts[1], "/")
+		if len(pattern) != len(segments) {
+			continue
+		}
+
+		wildcards := 0
+		for i := range pattern {
+			if pattern[i] == "*" {
+				wildcards++
+			} else if pattern[i] != segments[i] {
+				wildcards = -1
+				break
+			}
+		}
+		if wildcards >= 0 && (bestWildcards < 0 || wildcards < bestWildcards) {
+			best, bestWildcards = r, wildcards
+		}
+	}
+
+	return best, segments
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := r.Header.Get("Idempotency-Key")
+	cacheKey := r.Method + " " + r.URL.Path + " " + key
+	if res, ok := s.idempotency[cacheKey]; ok && key != "" {
+		write(w, res)
+		return
+	}
+
+	res := s.serve(r)
+	if key != "" && res.status < 500 {
+		s.idempotency[cacheKey] = res
+	}
+
+	write(w, res)
+}
+
+func (s *server) serve(r *http.Request) response {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return errorResponse(errorf(http.StatusBadRequest, "%s", err.Error()))
+	}
+
+	claims, _, err := s.verifier.Verify(body, s.required...)
+	if err != nil {
+		return errorResponse(errorf(http.StatusUnauthorized, "%s", err.Error()))
+	}
+
+	handler, path := s.match(r.Method, r.URL.Path)
+	if handler == nil {
+		return errorResponse(errorf(http.StatusNotFound, "404 Not Found"))
+	}
+
+	result, err := handler(path, claims.Data)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	status := http.StatusOK
+	if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/new") {
+		status = http.StatusCreated
+	}
+
+	payload, err := json.Marshal(result)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response{status: status, body: payload}
+}
+
+func errorResponse(err error) response {
+	httpErr, ok := err.(*httpError)
+	if !ok {
+		httpErr = errorf(http.StatusInternalServerError, "%s", err.Error())
+	}
+
+	payload, _ := json.Marshal(map[string][]string{"errors": httpErr.Errors})
+	return response{status: httpErr.StatusCode, body: payload}
+}
+
+func write(w http.ResponseWriter, res response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(res.status)
+	w.Write(res.body)
+}
+
+// decode unmarshals the JWT data claim into params
+func decode(data json.RawMessage, params interface{}) error {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, params); err != nil {
+		return errorf(http.StatusUnprocessableEntity, "invalid params: %s", err.Error())
+	}
+
+	return nil
+}
+
+// paginate returns a page of items, pages are numbered from 1
+func paginate[T any](items []T, page, limit int64) []T {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = mngapi.DefaultPageLimit
+	}
+
+	from := (page - 1) * limit
+	if from >= int64(len(items)) {
+		return []T{}
+	}
+
+	to := from + limit
+	if to > int64(len(items)) {
+		to = int64(len(items))
+	}
+
+	return items[from:to]
+}
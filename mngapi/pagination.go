@@ -0,0 +1,97 @@
+package mngapi
+
+import "context"
+
+// DefaultPageLimit is the page size used by iterators when the list params have no limit
+const DefaultPageLimit = 100
+
+// PageFunc fetches a page of a list endpoint, pages are numbered from 1
+type PageFunc[T any] func(ctx context.Context, page, limit int64) ([]T, error)
+
+// Iterator walks through every item of a paginated list endpoint, fetching pages on demand:
+//
+//	it := client.IterateDeposits(peatio.GetDepositsParams{Currency: "eth"})
+//	for it.Next(ctx) {
+//		deposit := it.Item()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	fetch PageFunc[T]
+	page  int64
+	limit int64
+
+	items []T
+	index int
+	last  bool
+	err   error
+}
+
+// NewIterator returns an Iterator starting at the given page, limit is the page size
+func NewIterator[T any](page, limit int64, fetch PageFunc[T]) *Iterator[T] {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = DefaultPageLimit
+	}
+
+	return &Iterator[T]{
+		fetch: fetch,
+		page:  page,
+		limit: limit,
+		index: -1,
+	}
+}
+
+// Next advances to the next item, fetching the next page if needed.
+// It returns false at the end of the list or on error, see Err.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	if it.index+1 < len(it.items) {
+		it.index++
+		return true
+	}
+
+	if it.last {
+		return false
+	}
+
+	items, err := it.fetch(ctx, it.page, it.limit)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	// A short page is the last one
+	it.last = int64(len(items)) < it.limit
+	it.items = items
+	it.index = 0
+	it.page++
+
+	return len(items) > 0
+}
+
+// Item returns the current item
+func (it *Iterator[T]) Item() T {
+	return it.items[it.index]
+}
+
+// Err returns the error which stopped the iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All fetches the remaining items of every page
+func (it *Iterator[T]) All(ctx context.Context) ([]T, error) {
+	var items []T
+	for it.Next(ctx) {
+		items = append(items, it.Item())
+	}
+
+	return items, it.Err()
+}
@@ -0,0 +1,66 @@
+package mngapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterator(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	var pages []int64
+	fetch := func(ctx context.Context, page, limit int64) ([]int, error) {
+		pages = append(pages, page)
+		from := (page - 1) * limit
+		if from >= int64(len(items)) {
+			return nil, nil
+		}
+		to := from + limit
+		if to > int64(len(items)) {
+			to = int64(len(items))
+		}
+		return items[from:to], nil
+	}
+
+	t.Run("Short last page", func(t *testing.T) {
+		pages = nil
+		all, err := NewIterator(0, 2, fetch).All(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, items, all)
+		assert.Equal(t, []int64{1, 2, 3}, pages)
+	})
+
+	t.Run("Empty last page", func(t *testing.T) {
+		pages = nil
+		all, err := NewIterator(1, 5, fetch).All(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, items, all)
+		assert.Equal(t, []int64{1, 2}, pages)
+	})
+
+	t.Run("Start page", func(t *testing.T) {
+		all, err := NewIterator(2, 2, fetch).All(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []int{3, 4, 5}, all)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		it := NewIterator(1, 2, func(ctx context.Context, page, limit int64) ([]int, error) {
+			if page > 1 {
+				return nil, errors.New("unavailable")
+			}
+			return fetch(ctx, page, limit)
+		})
+
+		var got []int
+		for it.Next(context.Background()) {
+			got = append(got, it.Item())
+		}
+		assert.Equal(t, []int{1, 2}, got)
+		assert.EqualError(t, it.Err(), "unavailable")
+		assert.False(t, it.Next(context.Background()))
+	})
+}
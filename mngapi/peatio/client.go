@@ -1,16 +1,25 @@
 package peatio
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 
 	"github.com/openware/pkg/mngapi"
 )
 
-// Client is peatio management api client instance
+// Client is peatio management api client instance.
+//
+// Peatio declares the list and read endpoints of its management api as POST routes,
+// their params are read from the signed JWT body, so these calls are sent with POST
+// even though mngapi.Client supports GET and DELETE.
 type Client struct {
 	mngapiClient mngapi.DefaultClient
+
+	precisionsMutex sync.RWMutex
+	precisions      map[string]int32
 }
 
 // New return peatio management api client
@@ -25,231 +34,205 @@ func New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey string) (*Client, error) {
 	}, nil
 }
 
-// GetCurrencyByCode call peatio management api to get currency information by code name
-func (p *Client) GetCurrencyByCode(code string) (*Currency, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPost, fmt.Sprintf("currencies/%v", code), nil)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	currency := &Currency{}
-	err := json.Unmarshal([]byte(res), currency)
+// NewWithSigners return peatio management api client signing requests with every signer
+func NewWithSigners(URL, jwtIssuer string, signers ...mngapi.JWSSigner) (*Client, error) {
+	client, err := mngapi.NewWithSigners(URL, jwtIssuer, signers...)
 	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
+		return nil, err
 	}
 
-	return currency, nil
+	return &Client{
+		mngapiClient: client,
+	}, nil
+}
+
+// GetCurrencyByCode call peatio management api to get currency information by code name
+func (p *Client) GetCurrencyByCode(code string) (*Currency, *mngapi.APIError) {
+	currency, err := p.GetCurrencyByCodeContext(context.Background(), code)
+	return currency, mngapi.ToAPIError(err)
+}
+
+// GetCurrencyByCodeContext is like GetCurrencyByCode, the request is cancelled with the context
+func (p *Client) GetCurrencyByCodeContext(ctx context.Context, code string) (*Currency, error) {
+	return mngapi.Do[*Currency](ctx, p.mngapiClient, http.MethodPost, fmt.Sprintf("currencies/%v", code), nil, mngapi.Idempotent())
 }
 
 // GetBlockchainCurrencyByID call peatio management api to get blockchain currency information by id
 func (p *Client) GetBlockchainCurrencyByID(id string) (*BlockchainCurrency, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPost, fmt.Sprintf("blockchain_currencies/%v", id), nil)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	blockchainCurrency := &BlockchainCurrency{}
-	err := json.Unmarshal([]byte(res), blockchainCurrency)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
+	blockchainCurrency, err := p.GetBlockchainCurrencyByIDContext(context.Background(), id)
+	return blockchainCurrency, mngapi.ToAPIError(err)
+}
 
-	return blockchainCurrency, nil
+// GetBlockchainCurrencyByIDContext is like GetBlockchainCurrencyByID, the request is cancelled with the context
+func (p *Client) GetBlockchainCurrencyByIDContext(ctx context.Context, id string) (*BlockchainCurrency, error) {
+	return mngapi.Do[*BlockchainCurrency](ctx, p.mngapiClient, http.MethodPost, fmt.Sprintf("blockchain_currencies/%v", id), nil, mngapi.Idempotent())
 }
 
 // GetCurrenciesList call peatio management api to get currency information by code name
 func (p *Client) GetCurrenciesList(params CurrenciesListParams) (*[]Currency, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPost, "currencies/list", params)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	currencies := []Currency{}
-	err := json.Unmarshal([]byte(res), &currencies)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
+	currencies, err := p.GetCurrenciesListContext(context.Background(), params)
+	return currencies, mngapi.ToAPIError(err)
+}
 
-	return &currencies, nil
+// GetCurrenciesListContext is like GetCurrenciesList, the request is cancelled with the context
+func (p *Client) GetCurrenciesListContext(ctx context.Context, params CurrenciesListParams) (*[]Currency, error) {
+	return mngapi.Do[*[]Currency](ctx, p.mngapiClient, http.MethodPost, "currencies/list", params, mngapi.Idempotent())
 }
 
 func (p *Client) CreateCurrency(params CreateCurrencyParams) (*Currency, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPost, "currencies/create", params)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	currency := &Currency{}
-	err := json.Unmarshal([]byte(res), currency)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
+	currency, err := p.CreateCurrencyContext(context.Background(), params)
+	return currency, mngapi.ToAPIError(err)
+}
 
-	return currency, nil
+// CreateCurrencyContext is like CreateCurrency, the request is cancelled with the context
+func (p *Client) CreateCurrencyContext(ctx context.Context, params CreateCurrencyParams) (*Currency, error) {
+	return mngapi.Do[*Currency](ctx, p.mngapiClient, http.MethodPost, "currencies/create", params)
 }
 
 func (p *Client) CreateBlockchainCurrency(params CreateBlockchainCurrencyParams) (*BlockchainCurrency, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPost, "blockchain_currencies/new", params)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	blockchainCurrency := &BlockchainCurrency{}
-	err := json.Unmarshal([]byte(res), blockchainCurrency)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
+	blockchainCurrency, err := p.CreateBlockchainCurrencyContext(context.Background(), params)
+	return blockchainCurrency, mngapi.ToAPIError(err)
+}
 
-	return blockchainCurrency, nil
+// CreateBlockchainCurrencyContext is like CreateBlockchainCurrency, the request is cancelled with the context
+func (p *Client) CreateBlockchainCurrencyContext(ctx context.Context, params CreateBlockchainCurrencyParams) (*BlockchainCurrency, error) {
+	return mngapi.Do[*BlockchainCurrency](ctx, p.mngapiClient, http.MethodPost, "blockchain_currencies/new", params)
 }
 
 func (p *Client) UpdateCurrency(params UpdateCurrencyParams) (*Currency, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPut, "currencies/update", params)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	currency := &Currency{}
-	err := json.Unmarshal([]byte(res), currency)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
+	currency, err := p.UpdateCurrencyContext(context.Background(), params)
+	return currency, mngapi.ToAPIError(err)
+}
 
-	return currency, nil
+// UpdateCurrencyContext is like UpdateCurrency, the request is cancelled with the context
+func (p *Client) UpdateCurrencyContext(ctx context.Context, params UpdateCurrencyParams) (*Currency, error) {
+	return mngapi.Do[*Currency](ctx, p.mngapiClient, http.MethodPut, "currencies/update", params, mngapi.Idempotent())
 }
 
 func (p *Client) UpdateBlockchainCurrency(params UpdateBlockchainCurrencyParams) (*BlockchainCurrency, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPut, "blockchain_currencies/update", params)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	blockchainCurrency := &BlockchainCurrency{}
-	err := json.Unmarshal([]byte(res), blockchainCurrency)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
+	blockchainCurrency, err := p.UpdateBlockchainCurrencyContext(context.Background(), params)
+	return blockchainCurrency, mngapi.ToAPIError(err)
+}
 
-	return blockchainCurrency, nil
+// UpdateBlockchainCurrencyContext is like UpdateBlockchainCurrency, the request is cancelled with the context
+func (p *Client) UpdateBlockchainCurrencyContext(ctx context.Context, params UpdateBlockchainCurrencyParams) (*BlockchainCurrency, error) {
+	return mngapi.Do[*BlockchainCurrency](ctx, p.mngapiClient, http.MethodPut, "blockchain_currencies/update", params, mngapi.Idempotent())
 }
 
-// CreateWithdraw call peatio management api to create new withdraw
+// CreateWithdraw call peatio management api to create new withdraw,
+// the amount is validated against the currency precision, see CreateWithdrawContext
 func (p *Client) CreateWithdraw(params CreateWithdrawParams) (*Withdraw, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPost, "withdraws/new", params)
-	if apiError != nil {
-		return nil, apiError
+	withdraw, err := p.CreateWithdrawContext(context.Background(), params)
+	return withdraw, mngapi.ToAPIError(err)
+}
+
+// CreateWithdrawContext is like CreateWithdraw, the request is cancelled with the context.
+// The TID is sent as idempotency key, so that a withdraw with a TID is safely retried.
+//
+// The amount is validated against the currency precision before sending, the currency
+// is fetched on first use and its precision cached, see LoadCurrencyPrecisions.
+func (p *Client) CreateWithdrawContext(ctx context.Context, params CreateWithdrawParams) (*Withdraw, error) {
+	if err := p.validatePrecision(ctx, params.Currency, params.Amount); err != nil {
+		return nil, err
 	}
 
-	withdraw := &Withdraw{}
-	err := json.Unmarshal([]byte(res), withdraw)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
+	var opts []mngapi.RequestOption
+	if params.TID != "" {
+		opts = append(opts, mngapi.IdempotencyKey(params.TID))
 	}
 
-	return withdraw, nil
+	return mngapi.Do[*Withdraw](ctx, p.mngapiClient, http.MethodPost, "withdraws/new", params, opts...)
 }
 
 // GetWithdrawByID call peatio management api to get withdraw information by transaction ID
 func (p *Client) GetWithdrawByID(tid string) (*Withdraw, *mngapi.APIError) {
+	withdraw, err := p.GetWithdrawByIDContext(context.Background(), tid)
+	return withdraw, mngapi.ToAPIError(err)
+}
+
+// GetWithdrawByIDContext is like GetWithdrawByID, the request is cancelled with the context
+func (p *Client) GetWithdrawByIDContext(ctx context.Context, tid string) (*Withdraw, error) {
 	// Build parameters
 	params := map[string]interface{}{
 		"tid": tid,
 	}
 
-	res, apiError := p.mngapiClient.Request(http.MethodPost, "withdraws/get", params)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	withdraw := &Withdraw{}
-	err := json.Unmarshal([]byte(res), withdraw)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
-
-	return withdraw, nil
+	return mngapi.Do[*Withdraw](ctx, p.mngapiClient, http.MethodPost, "withdraws/get", params, mngapi.Idempotent())
 }
 
 // GetAccountBalance call peatio management api to get account balance
 func (p *Client) GetAccountBalance(params GetAccountBalanceParams) (*Balance, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPost, "accounts/balance", params)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	balance := &Balance{}
-	err := json.Unmarshal([]byte(res), balance)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
+	balance, err := p.GetAccountBalanceContext(context.Background(), params)
+	return balance, mngapi.ToAPIError(err)
+}
 
-	return balance, nil
+// GetAccountBalanceContext is like GetAccountBalance, the request is cancelled with the context
+func (p *Client) GetAccountBalanceContext(ctx context.Context, params GetAccountBalanceParams) (*Balance, error) {
+	return mngapi.Do[*Balance](ctx, p.mngapiClient, http.MethodPost, "accounts/balance", params, mngapi.Idempotent())
 }
 
 // GenerateDepositAddress call peatio management api to generate new deposit address
 func (p *Client) GenerateDepositAddress(params GenerateDepositAddressParams) (*PaymentAddress, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPost, "deposit_address/new", params)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	paymentAddress := &PaymentAddress{}
-	err := json.Unmarshal([]byte(res), paymentAddress)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
+	paymentAddress, err := p.GenerateDepositAddressContext(context.Background(), params)
+	return paymentAddress, mngapi.ToAPIError(err)
+}
 
-	return paymentAddress, nil
+// GenerateDepositAddressContext is like GenerateDepositAddress, the request is cancelled with the context
+func (p *Client) GenerateDepositAddressContext(ctx context.Context, params GenerateDepositAddressParams) (*PaymentAddress, error) {
+	return mngapi.Do[*PaymentAddress](ctx, p.mngapiClient, http.MethodPost, "deposit_address/new", params)
 }
 
-// CreateDeposit call peatio management api to create new deposit
+// CreateDeposit call peatio management api to create new deposit,
+// the amount is validated against the currency precision, see CreateDepositContext
 func (p *Client) CreateDeposit(params CreateDepositParams) (*Deposit, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPost, "deposits/new", params)
-	if apiError != nil {
-		return nil, apiError
-	}
+	deposit, err := p.CreateDepositContext(context.Background(), params)
+	return deposit, mngapi.ToAPIError(err)
+}
 
-	deposit := &Deposit{}
-	err := json.Unmarshal([]byte(res), deposit)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
+// CreateDepositContext is like CreateDeposit, the request is cancelled with the context.
+//
+// The amount is validated against the currency precision before sending, the currency
+// is fetched on first use and its precision cached, see LoadCurrencyPrecisions.
+func (p *Client) CreateDepositContext(ctx context.Context, params CreateDepositParams) (*Deposit, error) {
+	if err := p.validatePrecision(ctx, params.Currency, params.Amount); err != nil {
+		return nil, err
 	}
 
-	return deposit, nil
+	return mngapi.Do[*Deposit](ctx, p.mngapiClient, http.MethodPost, "deposits/new", params)
 }
 
 // GetDepositByID call peatio management api to get deposit information by transaction ID
 func (p *Client) GetDepositByID(tid string) (*Deposit, *mngapi.APIError) {
+	deposit, err := p.GetDepositByIDContext(context.Background(), tid)
+	return deposit, mngapi.ToAPIError(err)
+}
+
+// GetDepositByIDContext is like GetDepositByID, the request is cancelled with the context
+func (p *Client) GetDepositByIDContext(ctx context.Context, tid string) (*Deposit, error) {
 	// Build parameters
 	params := map[string]interface{}{
 		"tid": tid,
 	}
 
-	res, apiError := p.mngapiClient.Request(http.MethodPost, "deposits/get", params)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	deposit := &Deposit{}
-	err := json.Unmarshal([]byte(res), deposit)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
-
-	return deposit, nil
+	return mngapi.Do[*Deposit](ctx, p.mngapiClient, http.MethodPost, "deposits/get", params, mngapi.Idempotent())
 }
 
 // GetDeposits call peatio management api to get deposits as paginated collection
 func (p *Client) GetDeposits(params GetDepositsParams) ([]*Deposit, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPost, "deposits", params)
-	if apiError != nil {
-		return nil, apiError
+	deposits, err := p.GetDepositsContext(context.Background(), params)
+	return deposits, mngapi.ToAPIError(err)
+}
+
+// GetDepositsContext is like GetDeposits, the request is cancelled with the context
+func (p *Client) GetDepositsContext(ctx context.Context, params GetDepositsParams) ([]*Deposit, error) {
+	res, err := mngapi.Do[json.RawMessage](ctx, p.mngapiClient, http.MethodPost, "deposits", params, mngapi.Idempotent())
+	if err != nil {
+		return nil, err
 	}
 
 	deposits := make([]*Deposit, 0)
-	err := json.Unmarshal([]byte(res), &deposits)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: fmt.Sprintf("payload: %s; error: %s", res, err.Error())}
+	if err := json.Unmarshal(res, &deposits); err != nil {
+		return nil, fmt.Errorf("payload: %s; error: %s", res, err.Error())
 	}
 
 	return deposits, nil
@@ -257,191 +240,332 @@ func (p *Client) GetDeposits(params GetDepositsParams) ([]*Deposit, *mngapi.APIE
 
 // CreateEngine call peatio management api to create new engine
 func (p *Client) CreateEngine(params CreateEngineParams) (*Engine, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPost, "engines/new", params)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	engine := &Engine{}
-	err := json.Unmarshal([]byte(res), engine)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
+	engine, err := p.CreateEngineContext(context.Background(), params)
+	return engine, mngapi.ToAPIError(err)
+}
 
-	return engine, nil
+// CreateEngineContext is like CreateEngine, the request is cancelled with the context
+func (p *Client) CreateEngineContext(ctx context.Context, params CreateEngineParams) (*Engine, error) {
+	return mngapi.Do[*Engine](ctx, p.mngapiClient, http.MethodPost, "engines/new", params)
 }
 
 // UpdateEngine call peatio management api to update engine
 func (p *Client) UpdateEngine(params UpdateEngineParams) (*Engine, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPost, "engines/update", params)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	engine := &Engine{}
-	err := json.Unmarshal([]byte(res), engine)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
+	engine, err := p.UpdateEngineContext(context.Background(), params)
+	return engine, mngapi.ToAPIError(err)
+}
 
-	return engine, nil
+// UpdateEngineContext is like UpdateEngine, the request is cancelled with the context
+func (p *Client) UpdateEngineContext(ctx context.Context, params UpdateEngineParams) (*Engine, error) {
+	return mngapi.Do[*Engine](ctx, p.mngapiClient, http.MethodPost, "engines/update", params, mngapi.Idempotent())
 }
 
 // GetEngines call peatio management api to get engines
 func (p *Client) GetEngines(params GetEngineParams) ([]*Engine, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPost, "engines/get", params)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	engines := make([]*Engine, 0)
-
-	err := json.Unmarshal([]byte(res), &engines)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
+	engines, err := p.GetEnginesContext(context.Background(), params)
+	return engines, mngapi.ToAPIError(err)
+}
 
-	return engines, nil
+// GetEnginesContext is like GetEngines, the request is cancelled with the context
+func (p *Client) GetEnginesContext(ctx context.Context, params GetEngineParams) ([]*Engine, error) {
+	return mngapi.Do[[]*Engine](ctx, p.mngapiClient, http.MethodPost, "engines/get", params, mngapi.Idempotent())
 }
 
 // GetMarkets call peatio management api to get all markets
 func (p *Client) GetMarkets() ([]*Market, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPost, "/markets/list", nil)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	markets := make([]*Market, 0)
-
-	err := json.Unmarshal([]byte(res), &markets)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
+	markets, err := p.GetMarketsContext(context.Background())
+	return markets, mngapi.ToAPIError(err)
+}
 
-	return markets, nil
+// GetMarketsContext is like GetMarkets, the request is cancelled with the context.
+// Peatio serves the list with POST /markets/list
+func (p *Client) GetMarketsContext(ctx context.Context) ([]*Market, error) {
+	return mngapi.Do[[]*Market](ctx, p.mngapiClient, http.MethodPost, "markets/list", nil, mngapi.Idempotent())
 }
 
 // UpdateMarket call peatio management api to update market
 func (p *Client) UpdateMarket(params UpdateMarketParams) (*Market, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPut, "/markets/update", params)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	market := &Market{}
-	err := json.Unmarshal([]byte(res), market)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
+	market, err := p.UpdateMarketContext(context.Background(), params)
+	return market, mngapi.ToAPIError(err)
+}
 
-	return market, nil
+// UpdateMarketContext is like UpdateMarket, the request is cancelled with the context
+func (p *Client) UpdateMarketContext(ctx context.Context, params UpdateMarketParams) (*Market, error) {
+	return mngapi.Do[*Market](ctx, p.mngapiClient, http.MethodPut, "markets/update", params, mngapi.Idempotent())
 }
 
 func (p *Client) CreateMarket(params CreateMarketParams) (*Market, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPost, "markets/new", params)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	market := &Market{}
-	err := json.Unmarshal([]byte(res), market)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
+	market, err := p.CreateMarketContext(context.Background(), params)
+	return market, mngapi.ToAPIError(err)
+}
 
-	return market, nil
+// CreateMarketContext is like CreateMarket, the request is cancelled with the context
+func (p *Client) CreateMarketContext(ctx context.Context, params CreateMarketParams) (*Market, error) {
+	return mngapi.Do[*Market](ctx, p.mngapiClient, http.MethodPost, "markets/new", params)
 }
 
 func (p *Client) GetMarketByID(id string) (*Market, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPost, fmt.Sprintf("markets/%v", id), nil)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	market := &Market{}
-	err := json.Unmarshal([]byte(res), market)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
+	market, err := p.GetMarketByIDContext(context.Background(), id)
+	return market, mngapi.ToAPIError(err)
+}
 
-	return market, nil
+// GetMarketByIDContext is like GetMarketByID, the request is cancelled with the context
+func (p *Client) GetMarketByIDContext(ctx context.Context, id string) (*Market, error) {
+	return mngapi.Do[*Market](ctx, p.mngapiClient, http.MethodPost, fmt.Sprintf("markets/%v", id), nil, mngapi.Idempotent())
 }
 
 func (p *Client) CreateMember(params CreateMemberParams) (*Member, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPost, "members", params)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	member := &Member{}
-	err := json.Unmarshal([]byte(res), member)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
+	member, err := p.CreateMemberContext(context.Background(), params)
+	return member, mngapi.ToAPIError(err)
+}
 
-	return member, nil
+// CreateMemberContext is like CreateMember, the request is cancelled with the context
+func (p *Client) CreateMemberContext(ctx context.Context, params CreateMemberParams) (*Member, error) {
+	return mngapi.Do[*Member](ctx, p.mngapiClient, http.MethodPost, "members", params)
 }
 
 // CreateWallet call peatio management api to create wallet
 func (p *Client) CreateWallet(params CreateWalletParams) (*Wallet, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPost, "wallets/new", params)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	wallet := &Wallet{}
-	err := json.Unmarshal([]byte(res), wallet)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
+	wallet, err := p.CreateWalletContext(context.Background(), params)
+	return wallet, mngapi.ToAPIError(err)
+}
 
-	return wallet, nil
+// CreateWalletContext is like CreateWallet, the request is cancelled with the context
+func (p *Client) CreateWalletContext(ctx context.Context, params CreateWalletParams) (*Wallet, error) {
+	return mngapi.Do[*Wallet](ctx, p.mngapiClient, http.MethodPost, "wallets/new", params)
 }
 
 // UpdateWallet call peatio management api to update wallet
 func (p *Client) UpdateWallet(params UpdateWalletParams) (*Wallet, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPost, "wallets/update", params)
-	if apiError != nil {
-		return nil, apiError
-	}
-
-	wallet := &Wallet{}
-	err := json.Unmarshal([]byte(res), wallet)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
-	}
+	wallet, err := p.UpdateWalletContext(context.Background(), params)
+	return wallet, mngapi.ToAPIError(err)
+}
 
-	return wallet, nil
+// UpdateWalletContext is like UpdateWallet, the request is cancelled with the context
+func (p *Client) UpdateWalletContext(ctx context.Context, params UpdateWalletParams) (*Wallet, error) {
+	return mngapi.Do[*Wallet](ctx, p.mngapiClient, http.MethodPost, "wallets/update", params, mngapi.Idempotent())
 }
 
 // GetWallets call peatio management api to get wallets
 func (p *Client) GetWallets() ([]*Wallet, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPost, "wallets", nil)
-	if apiError != nil {
-		return nil, apiError
-	}
+	wallets, err := p.GetWalletsContext(context.Background())
+	return wallets, mngapi.ToAPIError(err)
+}
 
-	wallets := make([]*Wallet, 0)
+// GetWalletsContext is like GetWallets, the request is cancelled with the context.
+// Peatio serves the list with POST /wallets
+func (p *Client) GetWalletsContext(ctx context.Context) ([]*Wallet, error) {
+	return mngapi.Do[[]*Wallet](ctx, p.mngapiClient, http.MethodPost, "wallets", nil, mngapi.Idempotent())
+}
 
-	err := json.Unmarshal([]byte(res), &wallets)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
+func (p *Client) GetWalletByID(id int) (*Wallet, *mngapi.APIError) {
+	wallet, err := p.GetWalletByIDContext(context.Background(), id)
+	return wallet, mngapi.ToAPIError(err)
+}
+
+// GetWalletByIDContext is like GetWalletByID, the request is cancelled with the context
+func (p *Client) GetWalletByIDContext(ctx context.Context, id int) (*Wallet, error) {
+	return mngapi.Do[*Wallet](ctx, p.mngapiClient, http.MethodPost, fmt.Sprintf("wallets/%v", id), nil, mngapi.Idempotent())
+}
+
+// GetAccountBalances call peatio management api to get account balances of every member for a currency
+func (p *Client) GetAccountBalances(params GetAccountBalancesParams) ([]*Balance, *mngapi.APIError) {
+	balances, err := p.GetAccountBalancesContext(context.Background(), params)
+	return balances, mngapi.ToAPIError(err)
+}
+
+// GetAccountBalancesContext is like GetAccountBalances, the request is cancelled with the context
+func (p *Client) GetAccountBalancesContext(ctx context.Context, params GetAccountBalancesParams) ([]*Balance, error) {
+	return mngapi.Do[[]*Balance](ctx, p.mngapiClient, http.MethodPost, "accounts/balances", params, mngapi.Idempotent())
+}
+
+// GetWithdraws call peatio management api to get withdraws as paginated collection
+func (p *Client) GetWithdraws(params GetWithdrawsParams) ([]*Withdraw, *mngapi.APIError) {
+	withdraws, err := p.GetWithdrawsContext(context.Background(), params)
+	return withdraws, mngapi.ToAPIError(err)
+}
+
+// GetWithdrawsContext is like GetWithdraws, the request is cancelled with the context
+func (p *Client) GetWithdrawsContext(ctx context.Context, params GetWithdrawsParams) ([]*Withdraw, error) {
+	return mngapi.Do[[]*Withdraw](ctx, p.mngapiClient, http.MethodPost, "withdraws", params, mngapi.Idempotent())
+}
+
+// WithdrawAction call peatio management api to perform an action (process, cancel...) on a withdraw
+func (p *Client) WithdrawAction(params WithdrawActionParams) (*Withdraw, *mngapi.APIError) {
+	withdraw, err := p.WithdrawActionContext(context.Background(), params)
+	return withdraw, mngapi.ToAPIError(err)
+}
+
+// WithdrawActionContext is like WithdrawAction, the request is cancelled with the context
+func (p *Client) WithdrawActionContext(ctx context.Context, params WithdrawActionParams) (*Withdraw, error) {
+	return mngapi.Do[*Withdraw](ctx, p.mngapiClient, http.MethodPut, "withdraws/action", params)
+}
+
+// UpdateDepositState call peatio management api to update the state of a deposit
+func (p *Client) UpdateDepositState(params UpdateDepositStateParams) (*Deposit, *mngapi.APIError) {
+	deposit, err := p.UpdateDepositStateContext(context.Background(), params)
+	return deposit, mngapi.ToAPIError(err)
+}
+
+// UpdateDepositStateContext is like UpdateDepositState, the request is cancelled with the context
+func (p *Client) UpdateDepositStateContext(ctx context.Context, params UpdateDepositStateParams) (*Deposit, error) {
+	return mngapi.Do[*Deposit](ctx, p.mngapiClient, http.MethodPut, "deposits/state", params)
+}
+
+// GetTrades call peatio management api to get trades as paginated collection
+func (p *Client) GetTrades(params GetTradesParams) ([]*Trade, *mngapi.APIError) {
+	trades, err := p.GetTradesContext(context.Background(), params)
+	return trades, mngapi.ToAPIError(err)
+}
+
+// GetTradesContext is like GetTrades, the request is cancelled with the context
+func (p *Client) GetTradesContext(ctx context.Context, params GetTradesParams) ([]*Trade, error) {
+	return mngapi.Do[[]*Trade](ctx, p.mngapiClient, http.MethodPost, "trades", params, mngapi.Idempotent())
+}
+
+// GetOrders call peatio management api to get orders as paginated collection
+func (p *Client) GetOrders(params GetOrdersParams) ([]*Order, *mngapi.APIError) {
+	orders, err := p.GetOrdersContext(context.Background(), params)
+	return orders, mngapi.ToAPIError(err)
+}
+
+// GetOrdersContext is like GetOrders, the request is cancelled with the context
+func (p *Client) GetOrdersContext(ctx context.Context, params GetOrdersParams) ([]*Order, error) {
+	return mngapi.Do[[]*Order](ctx, p.mngapiClient, http.MethodPost, "orders", params, mngapi.Idempotent())
+}
+
+// CancelOrder call peatio management api to cancel an order by id
+func (p *Client) CancelOrder(id int64) (*Order, *mngapi.APIError) {
+	order, err := p.CancelOrderContext(context.Background(), id)
+	return order, mngapi.ToAPIError(err)
+}
+
+// CancelOrderContext is like CancelOrder, the request is cancelled with the context
+func (p *Client) CancelOrderContext(ctx context.Context, id int64) (*Order, error) {
+	return mngapi.Do[*Order](ctx, p.mngapiClient, http.MethodPost, fmt.Sprintf("orders/%v/cancel", id), nil, mngapi.Idempotent())
+}
+
+// CancelOrders call peatio management api to cancel the open orders matching the params
+func (p *Client) CancelOrders(params CancelOrdersParams) ([]*Order, *mngapi.APIError) {
+	orders, err := p.CancelOrdersContext(context.Background(), params)
+	return orders, mngapi.ToAPIError(err)
+}
+
+// CancelOrdersContext is like CancelOrders, the request is cancelled with the context
+func (p *Client) CancelOrdersContext(ctx context.Context, params CancelOrdersParams) ([]*Order, error) {
+	return mngapi.Do[[]*Order](ctx, p.mngapiClient, http.MethodPost, "orders/cancel", params, mngapi.Idempotent())
+}
+
+// GetMembers call peatio management api to get members as paginated collection
+func (p *Client) GetMembers(params GetMembersParams) ([]*Member, *mngapi.APIError) {
+	members, err := p.GetMembersContext(context.Background(), params)
+	return members, mngapi.ToAPIError(err)
+}
+
+// GetMembersContext is like GetMembers, the request is cancelled with the context
+func (p *Client) GetMembersContext(ctx context.Context, params GetMembersParams) ([]*Member, error) {
+	return mngapi.Do[[]*Member](ctx, p.mngapiClient, http.MethodPost, "members/list", params, mngapi.Idempotent())
+}
+
+// SetMemberGroup call peatio management api to set the group of a member
+func (p *Client) SetMemberGroup(params SetMemberGroupParams) (*Member, *mngapi.APIError) {
+	member, err := p.SetMemberGroupContext(context.Background(), params)
+	return member, mngapi.ToAPIError(err)
+}
+
+// SetMemberGroupContext is like SetMemberGroup, the request is cancelled with the context
+func (p *Client) SetMemberGroupContext(ctx context.Context, params SetMemberGroupParams) (*Member, error) {
+	return mngapi.Do[*Member](ctx, p.mngapiClient, http.MethodPost, "members/group", params, mngapi.Idempotent())
+}
+
+// GetBeneficiaries call peatio management api to get beneficiaries as paginated collection
+func (p *Client) GetBeneficiaries(params GetBeneficiariesParams) ([]*Beneficiary, *mngapi.APIError) {
+	beneficiaries, err := p.GetBeneficiariesContext(context.Background(), params)
+	return beneficiaries, mngapi.ToAPIError(err)
+}
+
+// GetBeneficiariesContext is like GetBeneficiaries, the request is cancelled with the context
+func (p *Client) GetBeneficiariesContext(ctx context.Context, params GetBeneficiariesParams) ([]*Beneficiary, error) {
+	return mngapi.Do[[]*Beneficiary](ctx, p.mngapiClient, http.MethodPost, "beneficiaries/list", params, mngapi.Idempotent())
+}
+
+// CreateBeneficiary call peatio management api to create new beneficiary
+func (p *Client) CreateBeneficiary(params CreateBeneficiaryParams) (*Beneficiary, *mngapi.APIError) {
+	beneficiary, err := p.CreateBeneficiaryContext(context.Background(), params)
+	return beneficiary, mngapi.ToAPIError(err)
+}
+
+// CreateBeneficiaryContext is like CreateBeneficiary, the request is cancelled with the context
+func (p *Client) CreateBeneficiaryContext(ctx context.Context, params CreateBeneficiaryParams) (*Beneficiary, error) {
+	return mngapi.Do[*Beneficiary](ctx, p.mngapiClient, http.MethodPost, "beneficiaries", params)
+}
+
+// GetOperations call peatio management api to get accounting operations of the given type as paginated collection
+func (p *Client) GetOperations(kind OperationType, params GetOperationsParams) ([]*Operation, *mngapi.APIError) {
+	operations, err := p.GetOperationsContext(context.Background(), kind, params)
+	return operations, mngapi.ToAPIError(err)
+}
+
+// GetOperationsContext is like GetOperations, the request is cancelled with the context
+func (p *Client) GetOperationsContext(ctx context.Context, kind OperationType, params GetOperationsParams) ([]*Operation, error) {
+	return mngapi.Do[[]*Operation](ctx, p.mngapiClient, http.MethodPost, string(kind), params, mngapi.Idempotent())
+}
+
+// CreateOperation call peatio management api to create new accounting operation of the given type
+func (p *Client) CreateOperation(kind OperationType, params CreateOperationParams) (*Operation, *mngapi.APIError) {
+	operation, err := p.CreateOperationContext(context.Background(), kind, params)
+	return operation, mngapi.ToAPIError(err)
+}
+
+// CreateOperationContext is like CreateOperation, the request is cancelled with the context
+func (p *Client) CreateOperationContext(ctx context.Context, kind OperationType, params CreateOperationParams) (*Operation, error) {
+	return mngapi.Do[*Operation](ctx, p.mngapiClient, http.MethodPost, fmt.Sprintf("%s/new", kind), params)
+}
+
+// SetCurrencyPrecision sets the precision of a currency, withdraw and deposit amounts
+// of the currency are validated against it before sending requests.
+// Currencies without precision are fetched from peatio on first use
+func (p *Client) SetCurrencyPrecision(currency string, precision int32) {
+	p.precisionsMutex.Lock()
+	defer p.precisionsMutex.Unlock()
+
+	if p.precisions == nil {
+		p.precisions = make(map[string]int32)
+	}
+	p.precisions[currency] = precision
+}
+
+// LoadCurrencyPrecisions sets the precision of every currency configured in peatio,
+// so that withdraws and deposits don't fetch their currency on first use
+func (p *Client) LoadCurrencyPrecisions(ctx context.Context) error {
+	it := p.IterateCurrencies(CurrenciesListParams{})
+	for it.Next(ctx) {
+		c := it.Item()
+		p.SetCurrencyPrecision(c.ID, int32(c.Precision))
 	}
 
-	return wallets, nil
+	return it.Err()
 }
 
-func (p *Client) GetWalletByID(id int) (*Wallet, *mngapi.APIError) {
-	res, apiError := p.mngapiClient.Request(http.MethodPost, fmt.Sprintf("wallets/%v", id), nil)
-	if apiError != nil {
-		return nil, apiError
+// validatePrecision checks the amount against the currency precision,
+// the currency is fetched and its precision cached if unknown
+func (p *Client) validatePrecision(ctx context.Context, currency string, amount Decimal) error {
+	p.precisionsMutex.RLock()
+	precision, ok := p.precisions[currency]
+	p.precisionsMutex.RUnlock()
+
+	if !ok {
+		c, err := p.GetCurrencyByCodeContext(ctx, currency)
+		if err != nil {
+			return err
+		}
+		precision = int32(c.Precision)
+		p.SetCurrencyPrecision(currency, precision)
 	}
 
-	wallet := &Wallet{}
-	err := json.Unmarshal([]byte(res), wallet)
-	if err != nil {
-		return nil, &mngapi.APIError{StatusCode: 500, Error: err.Error()}
+	if err := amount.ValidatePrecision(precision); err != nil {
+		return &mngapi.RequestError{Err: err}
 	}
 
-	return wallet, nil
+	return nil
 }
@@ -1,8 +1,13 @@
 package peatio
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/openware/pkg/mngapi"
 	"github.com/stretchr/testify/assert"
@@ -19,6 +24,8 @@ const (
 type MockClient struct {
 	response []byte
 	apiError *mngapi.APIError
+	method   string
+	path     string
 }
 
 // Mock request function
@@ -26,6 +33,15 @@ func (m *MockClient) Request(method string, path string, body interface{}) ([]by
 	return m.response, m.apiError
 }
 
+// Mock request function with context
+func (m *MockClient) RequestContext(ctx context.Context, method string, path string, body interface{}, opts ...mngapi.RequestOption) ([]byte, error) {
+	m.method, m.path = method, path
+	if m.apiError != nil {
+		return nil, &mngapi.ResponseError{APIError: m.apiError}
+	}
+	return m.response, nil
+}
+
 func TestCreateNewClient(t *testing.T) {
 	t.Run("Success creation", func(t *testing.T) {
 		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
@@ -416,6 +432,7 @@ func TestCreateWithdraw(t *testing.T) {
 	t.Run("Success response", func(t *testing.T) {
 		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
 		assert.NoError(t, err)
+		client.SetCurrencyPrecision("bnb", 8)
 
 		expected := `{"tid":"TIDE54B7D229E","uid":"ID16421C020A","currency":"btc","blockchain_key":"btc-testnet","note":"","type":"coin","amount":"0.1195","fee":"0.0005","rid":"1CzSHQnuwp52ErrrtM169FW4FuuRhEksMR","state":"skipped","created_at":"2021-01-12T07:27:41+01:00","blockchain_txid":"","transfer_type":"crypto"}`
 		client.mngapiClient = &MockClient{
@@ -426,7 +443,7 @@ func TestCreateWithdraw(t *testing.T) {
 		params := CreateWithdrawParams{
 			UID:      "IDCA2AC08296",
 			Currency: "bnb",
-			Amount:   10.0,
+			Amount:   MustDecimal("10.0"),
 		}
 		withdraw, apiError := client.CreateWithdraw(params)
 		assert.Nil(t, apiError)
@@ -439,6 +456,7 @@ func TestCreateWithdraw(t *testing.T) {
 	t.Run("Error response", func(t *testing.T) {
 		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
 		assert.NoError(t, err)
+		client.SetCurrencyPrecision("bnb", 8)
 
 		client.mngapiClient = &MockClient{
 			response: nil,
@@ -451,7 +469,7 @@ func TestCreateWithdraw(t *testing.T) {
 		params := CreateWithdrawParams{
 			UID:      "IDCA2AC08296",
 			Currency: "bnb",
-			Amount:   10.0,
+			Amount:   MustDecimal("10.0"),
 		}
 		withdraw, apiError := client.CreateWithdraw(params)
 
@@ -464,6 +482,7 @@ func TestCreateWithdraw(t *testing.T) {
 	t.Run("Error mismatch data type during unmarshal", func(t *testing.T) {
 		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
 		assert.NoError(t, err)
+		client.SetCurrencyPrecision("bnb", 8)
 
 		expected := `{"tid":1234}`
 		client.mngapiClient = &MockClient{
@@ -474,7 +493,7 @@ func TestCreateWithdraw(t *testing.T) {
 		params := CreateWithdrawParams{
 			UID:      "IDCA2AC08296",
 			Currency: "bnb",
-			Amount:   10.0,
+			Amount:   MustDecimal("10.0"),
 		}
 		withdraw, apiError := client.CreateWithdraw(params)
 
@@ -487,6 +506,7 @@ func TestCreateWithdraw(t *testing.T) {
 	t.Run("Error invalid json response during unmarshal", func(t *testing.T) {
 		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
 		assert.NoError(t, err)
+		client.SetCurrencyPrecision("bnb", 8)
 
 		expected := `{"-"}`
 		client.mngapiClient = &MockClient{
@@ -497,7 +517,7 @@ func TestCreateWithdraw(t *testing.T) {
 		params := CreateWithdrawParams{
 			UID:      "IDCA2AC08296",
 			Currency: "bnb",
-			Amount:   10.0,
+			Amount:   MustDecimal("10.0"),
 		}
 		withdraw, apiError := client.CreateWithdraw(params)
 
@@ -795,6 +815,7 @@ func TestCreateDeposit(t *testing.T) {
 	t.Run("Success response", func(t *testing.T) {
 		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
 		assert.NoError(t, err)
+		client.SetCurrencyPrecision("usd", 8)
 
 		expected := `{"id":1,"tid":"TIDBD6B265303","blockchain_key":"","currency":"usd","address":"","uid":"ID732785AC58","type":"fiat","amount":"750.77","state":"submitted","created_at":"2021-03-02T07:33:02+01:00","completed_at":null,"transfer_type":"fiat"}`
 		client.mngapiClient = &MockClient{
@@ -805,7 +826,7 @@ func TestCreateDeposit(t *testing.T) {
 		params := CreateDepositParams{
 			UID:      "ID732785AC58",
 			Currency: "usd",
-			Amount:   10.0,
+			Amount:   MustDecimal("10.0"),
 		}
 		deposit, apiError := client.CreateDeposit(params)
 		assert.Nil(t, apiError)
@@ -818,6 +839,7 @@ func TestCreateDeposit(t *testing.T) {
 	t.Run("Error response", func(t *testing.T) {
 		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
 		assert.NoError(t, err)
+		client.SetCurrencyPrecision("usd", 8)
 
 		client.mngapiClient = &MockClient{
 			response: nil,
@@ -830,7 +852,7 @@ func TestCreateDeposit(t *testing.T) {
 		params := CreateDepositParams{
 			UID:      "ID732785AC58",
 			Currency: "bnb",
-			Amount:   10.0,
+			Amount:   MustDecimal("10.0"),
 		}
 		deposit, apiError := client.CreateDeposit(params)
 
@@ -843,6 +865,7 @@ func TestCreateDeposit(t *testing.T) {
 	t.Run("Error mismatch data type during unmarshal", func(t *testing.T) {
 		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
 		assert.NoError(t, err)
+		client.SetCurrencyPrecision("usd", 8)
 
 		expected := `{"tid":1234}`
 		client.mngapiClient = &MockClient{
@@ -853,7 +876,7 @@ func TestCreateDeposit(t *testing.T) {
 		params := CreateDepositParams{
 			UID:      "ID732785AC58",
 			Currency: "bnb",
-			Amount:   10.0,
+			Amount:   MustDecimal("10.0"),
 		}
 		deposit, apiError := client.CreateDeposit(params)
 
@@ -866,6 +889,7 @@ func TestCreateDeposit(t *testing.T) {
 	t.Run("Error invalid json response during unmarshal", func(t *testing.T) {
 		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
 		assert.NoError(t, err)
+		client.SetCurrencyPrecision("usd", 8)
 
 		expected := `{"-"}`
 		client.mngapiClient = &MockClient{
@@ -876,7 +900,7 @@ func TestCreateDeposit(t *testing.T) {
 		params := CreateDepositParams{
 			UID:      "ID732785AC58",
 			Currency: "bnb",
-			Amount:   10.0,
+			Amount:   MustDecimal("10.0"),
 		}
 		deposit, apiError := client.CreateDeposit(params)
 
@@ -1012,7 +1036,7 @@ func TestGetDeposits(t *testing.T) {
 		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
 		assert.NoError(t, err)
 
-		expected := `[{"id":1,"tid":"TID9119EEAE36","currency":"usd","address":"","uid":"ID9C5C7208EB","type":"fiat","amount":8423.0,"state":"collected","created_at":"2021-03-02T04:40:06+01:00","completed_at":"2021-03-02T04:40:06+01:00","transfer_type":"fiat"},{"id":2,"tid":"TID17505F194C","currency":"btc","address":"","uid":"ID0B0C77487A","type":"coin","amount":"191.0","state":"fee_processing","created_at":"2021-03-02T04:40:06+01:00","completed_at":"2021-03-02T04:40:06+01:00","blockchain_txid":"wfmvae8elj0egr309u9oodl58ypzifdfjz9vd1i82t3ng4uepmokagack0shfsif","blockchain_confirmations":"367597","transfer_type":"crypto"}]`
+		expected := `[{"id":1,"tid":"TID9119EEAE36","currency":"usd","address":"","uid":"ID9C5C7208EB","type":"fiat","amount":true,"state":"collected","created_at":"2021-03-02T04:40:06+01:00","completed_at":"2021-03-02T04:40:06+01:00","transfer_type":"fiat"},{"id":2,"tid":"TID17505F194C","currency":"btc","address":"","uid":"ID0B0C77487A","type":"coin","amount":"191.0","state":"fee_processing","created_at":"2021-03-02T04:40:06+01:00","completed_at":"2021-03-02T04:40:06+01:00","blockchain_txid":"wfmvae8elj0egr309u9oodl58ypzifdfjz9vd1i82t3ng4uepmokagack0shfsif","blockchain_confirmations":"367597","transfer_type":"crypto"}]`
 		client.mngapiClient = &MockClient{
 			response: []byte(expected),
 			apiError: nil,
@@ -1166,6 +1190,8 @@ func TestCreateEngine(t *testing.T) {
 }
 
 func TestUpdateEngine(t *testing.T) {
+	online := 1
+
 	t.Run("Success response", func(t *testing.T) {
 		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
 		assert.NoError(t, err)
@@ -1182,7 +1208,7 @@ func TestUpdateEngine(t *testing.T) {
 			Driver: "opendax",
 			UID:    "UID123123",
 			URL:    "https://example.com",
-			State:  1,
+			State:  &online,
 			Key:    "key",
 			Secret: "secret",
 		}
@@ -1213,7 +1239,7 @@ func TestUpdateEngine(t *testing.T) {
 			Driver: "opendax",
 			UID:    "UID123123",
 			URL:    "https://example.com",
-			State:  1,
+			State:  &online,
 			Key:    "key",
 			Secret: "secret",
 		}
@@ -1242,7 +1268,7 @@ func TestUpdateEngine(t *testing.T) {
 			Driver: "opendax",
 			UID:    "UID123123",
 			URL:    "https://example.com",
-			State:  1,
+			State:  &online,
 			Key:    "key",
 			Secret: "secret",
 		}
@@ -1270,7 +1296,7 @@ func TestUpdateEngine(t *testing.T) {
 			Driver: "opendax",
 			UID:    "UID123123",
 			URL:    "https://example.com",
-			State:  1,
+			State:  &online,
 			Key:    "key",
 			Secret: "secret",
 		}
@@ -2526,3 +2552,320 @@ func TestGetBlockchainCurrencyByID(t *testing.T) {
 		assert.Nil(t, network)
 	})
 }
+
+func TestCreateWithdrawContext(t *testing.T) {
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"tid":"TID9493F6CD41","uid":"ID092B2AF8E87","currency":"eth","amount":"0.1"}`))
+	}))
+	defer srv.Close()
+
+	mngapiClient, err := mngapi.New(srv.URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+	assert.NoError(t, err)
+	mngapiClient.SetRetryPolicy(mngapi.RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond})
+	client := &Client{mngapiClient: mngapiClient}
+	client.SetCurrencyPrecision("eth", 18)
+
+	t.Run("Retry with idempotency key", func(t *testing.T) {
+		withdraw, err := client.CreateWithdrawContext(context.Background(), CreateWithdrawParams{
+			UID:      "ID092B2AF8E87",
+			TID:      "TID9493F6CD41",
+			Currency: "eth",
+			Amount:   MustDecimal("0.1"),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "TID9493F6CD41", withdraw.TID)
+		assert.Equal(t, []string{"TID9493F6CD41", "TID9493F6CD41"}, keys)
+	})
+
+	t.Run("No retry without TID", func(t *testing.T) {
+		keys = nil
+		_, err := client.CreateWithdrawContext(context.Background(), CreateWithdrawParams{
+			UID:      "ID092B2AF8E87",
+			Currency: "eth",
+			Amount:   MustDecimal("0.1"),
+		})
+
+		var responseError *mngapi.ResponseError
+		assert.True(t, errors.As(err, &responseError))
+		assert.Equal(t, http.StatusBadGateway, responseError.StatusCode)
+		assert.Equal(t, []string{""}, keys)
+	})
+}
+
+func TestGetWithdraws(t *testing.T) {
+	t.Run("Success response", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		expected := `[{"tid":"TID9493F6CD41","uid":"ID092B2AF8E87","currency":"eth","blockchain_key":"eth-rinkeby","note":"","type":"coin","amount":"0.1","fee":"0.01","rid":"0x44ee4bb5cc2a2bd8f7fd06ab5a4aa6e1e1e4f3a6","state":"accepted","created_at":"2021-01-28T11:25:28Z","blockchain_txid":"","transfer_type":"crypto"}]`
+		mock := &MockClient{response: []byte(expected)}
+		client.mngapiClient = mock
+
+		withdraws, apiError := client.GetWithdraws(GetWithdrawsParams{State: "accepted"})
+		assert.Nil(t, apiError)
+		assert.Equal(t, "withdraws", mock.path)
+
+		result, err := json.Marshal(withdraws)
+		assert.NoError(t, err)
+		assert.Equal(t, result, []byte(expected))
+	})
+
+	t.Run("Error response", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		client.mngapiClient = &MockClient{
+			apiError: &mngapi.APIError{StatusCode: 422, Error: "management.withdraw.invalid_state"},
+		}
+
+		withdraws, apiError := client.GetWithdraws(GetWithdrawsParams{State: "unknown"})
+		assert.NotNil(t, apiError)
+		assert.Equal(t, apiError.StatusCode, 422)
+		assert.Equal(t, apiError.Error, "management.withdraw.invalid_state")
+		assert.Nil(t, withdraws)
+	})
+}
+
+func TestWithdrawAction(t *testing.T) {
+	client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+	assert.NoError(t, err)
+
+	mock := &MockClient{response: []byte(`{"tid":"TID9493F6CD41","state":"canceled"}`)}
+	client.mngapiClient = mock
+
+	withdraw, apiError := client.WithdrawAction(WithdrawActionParams{TID: "TID9493F6CD41", Action: "cancel"})
+	assert.Nil(t, apiError)
+	assert.Equal(t, "canceled", withdraw.State)
+	assert.Equal(t, "withdraws/action", mock.path)
+	assert.Equal(t, "PUT", mock.method)
+}
+
+func TestGetTrades(t *testing.T) {
+	t.Run("Success response", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		expected := `[{"id":1,"price":"1800.0","amount":"0.5","total":"900.0","market":"ethusd","maker_order_id":10,"taker_order_id":11,"maker_uid":"ID092B2AF8E87","taker_uid":"IDCA2AC08296","taker_type":"buy","created_at":"2021-01-28T11:25:28Z"}]`
+		client.mngapiClient = &MockClient{response: []byte(expected)}
+
+		trades, apiError := client.GetTrades(GetTradesParams{Market: "ethusd"})
+		assert.Nil(t, apiError)
+
+		result, err := json.Marshal(trades)
+		assert.NoError(t, err)
+		assert.Equal(t, result, []byte(expected))
+	})
+
+	t.Run("Error invalid json response during unmarshal", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		client.mngapiClient = &MockClient{response: []byte(`{""}`)}
+
+		trades, apiError := client.GetTrades(GetTradesParams{})
+		assert.NotNil(t, apiError)
+		assert.Equal(t, apiError.StatusCode, 500)
+		assert.NotEmpty(t, apiError.Error)
+		assert.Nil(t, trades)
+	})
+}
+
+func TestOrders(t *testing.T) {
+	expected := `[{"id":10,"uuid":"c3a3e0d4-6b2a-4c53-9d3f-0a0d4e8b4f43","uid":"ID092B2AF8E87","side":"sell","ord_type":"limit","price":"1800.0","avg_price":"0.0","state":"wait","market":"ethusd","origin_volume":"1.0","remaining_volume":"1.0","executed_volume":"0.0","trades_count":0,"created_at":"2021-01-28T11:25:28Z","updated_at":"2021-01-28T11:25:28Z"}]`
+
+	t.Run("Get orders", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		client.mngapiClient = &MockClient{response: []byte(expected)}
+
+		orders, apiError := client.GetOrders(GetOrdersParams{Market: "ethusd", State: "wait"})
+		assert.Nil(t, apiError)
+
+		result, err := json.Marshal(orders)
+		assert.NoError(t, err)
+		assert.Equal(t, result, []byte(expected))
+	})
+
+	t.Run("Cancel order", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		mock := &MockClient{response: []byte(`{"id":10,"state":"cancel"}`)}
+		client.mngapiClient = mock
+
+		order, apiError := client.CancelOrder(10)
+		assert.Nil(t, apiError)
+		assert.Equal(t, "cancel", order.State)
+		assert.Equal(t, "orders/10/cancel", mock.path)
+	})
+
+	t.Run("Cancel orders", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		mock := &MockClient{response: []byte(expected)}
+		client.mngapiClient = mock
+
+		orders, apiError := client.CancelOrders(CancelOrdersParams{Market: "ethusd", Side: "sell"})
+		assert.Nil(t, apiError)
+		assert.Len(t, orders, 1)
+		assert.Equal(t, "orders/cancel", mock.path)
+	})
+}
+
+func TestBeneficiaries(t *testing.T) {
+	expected := `{"id":1,"uid":"ID092B2AF8E87","currency":"eth","blockchain_key":"eth-rinkeby","name":"Cold wallet","description":"","data":{"address":"0x44ee4bb5cc2a2bd8f7fd06ab5a4aa6e1e1e4f3a6"},"state":"active","created_at":"2021-01-28T11:25:28Z"}`
+
+	t.Run("Create beneficiary", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		client.mngapiClient = &MockClient{response: []byte(expected)}
+
+		beneficiary, apiError := client.CreateBeneficiary(CreateBeneficiaryParams{
+			UID:      "ID092B2AF8E87",
+			Currency: "eth",
+			Name:     "Cold wallet",
+			Data:     map[string]interface{}{"address": "0x44ee4bb5cc2a2bd8f7fd06ab5a4aa6e1e1e4f3a6"},
+		})
+		assert.Nil(t, apiError)
+
+		result, err := json.Marshal(beneficiary)
+		assert.NoError(t, err)
+		assert.Equal(t, result, []byte(expected))
+	})
+
+	t.Run("Get beneficiaries", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		client.mngapiClient = &MockClient{response: []byte("[" + expected + "]")}
+
+		beneficiaries, apiError := client.GetBeneficiaries(GetBeneficiariesParams{UID: "ID092B2AF8E87"})
+		assert.Nil(t, apiError)
+		assert.Len(t, beneficiaries, 1)
+	})
+}
+
+func TestOperations(t *testing.T) {
+	expected := `[{"code":202,"currency":"usd","uid":"ID092B2AF8E87","account_kind":"main","credit":"100.0","debit":"0.0","reference_type":"deposit","created_at":"2021-01-28T11:25:28Z"}]`
+
+	t.Run("Get liabilities", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		mock := &MockClient{response: []byte(expected)}
+		client.mngapiClient = mock
+
+		operations, apiError := client.GetOperations(OperationLiabilities, GetOperationsParams{Currency: "usd"})
+		assert.Nil(t, apiError)
+		assert.Equal(t, "liabilities", mock.path)
+
+		result, err := json.Marshal(operations)
+		assert.NoError(t, err)
+		assert.Equal(t, result, []byte(expected))
+	})
+
+	t.Run("Create asset", func(t *testing.T) {
+		client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+		assert.NoError(t, err)
+
+		mock := &MockClient{response: []byte(`{"code":102,"currency":"usd","credit":"0.0","debit":"100.0","reference_type":"operation","created_at":"2021-01-28T11:25:28Z"}`)}
+		client.mngapiClient = mock
+
+		debit := MustDecimal("100.0")
+		operation, apiError := client.CreateOperation(OperationAssets, CreateOperationParams{Currency: "usd", Code: 102, Debit: &debit})
+		assert.Nil(t, apiError)
+		assert.Equal(t, int64(102), operation.Code)
+		assert.Equal(t, "assets/new", mock.path)
+	})
+}
+
+// Mock client serving a collection of deposits by page
+type MockPagedClient struct {
+	MockClient
+	deposits []*Deposit
+	pages    []int64
+}
+
+func (m *MockPagedClient) RequestContext(ctx context.Context, method string, path string, body interface{}, opts ...mngapi.RequestOption) ([]byte, error) {
+	params := body.(GetDepositsParams)
+	m.pages = append(m.pages, params.Page)
+
+	from := (params.Page - 1) * params.Limit
+	to := from + params.Limit
+	if from > int64(len(m.deposits)) {
+		from = int64(len(m.deposits))
+	}
+	if to > int64(len(m.deposits)) {
+		to = int64(len(m.deposits))
+	}
+
+	return json.Marshal(m.deposits[from:to])
+}
+
+func TestIterateDeposits(t *testing.T) {
+	client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+	assert.NoError(t, err)
+
+	mock := &MockPagedClient{}
+	for i := 1; i <= 5; i++ {
+		mock.deposits = append(mock.deposits, &Deposit{ID: uint64(i), Currency: "eth"})
+	}
+	client.mngapiClient = mock
+
+	var ids []uint64
+	it := client.IterateDeposits(GetDepositsParams{Currency: "eth", Limit: 2})
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Item().ID)
+	}
+
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []uint64{1, 2, 3, 4, 5}, ids)
+	assert.Equal(t, []int64{1, 2, 3}, mock.pages)
+}
+
+func TestCreateWithdrawPrecision(t *testing.T) {
+	client, err := New(URL, jwtIssuer, jwtAlgo, jwtPrivateKey)
+	assert.NoError(t, err)
+
+	mock := &MockClient{response: []byte(`[{"id":"btc","precision":8},{"id":"eth","precision":18}]`)}
+	client.mngapiClient = mock
+	assert.NoError(t, client.LoadCurrencyPrecisions(context.Background()))
+
+	mock.response = []byte(`{"tid":"TID9493F6CD41","currency":"btc","amount":"0.00000001"}`)
+	mock.path = ""
+
+	_, apiError := client.CreateWithdraw(CreateWithdrawParams{UID: "ID092B2AF8E87", Currency: "btc", Amount: MustDecimal("0.000000001")})
+	assert.NotNil(t, apiError)
+	assert.Equal(t, 0, apiError.StatusCode)
+	assert.Equal(t, "amount exceeds currency precision: 0.000000001 has more than 8 decimals", apiError.Error)
+	assert.Empty(t, mock.path)
+
+	_, err = client.CreateDepositContext(context.Background(), CreateDepositParams{UID: "ID092B2AF8E87", Currency: "btc", Amount: MustDecimal("1.000000001")})
+	assert.True(t, errors.Is(err, ErrPrecision))
+
+	withdraw, apiError := client.CreateWithdraw(CreateWithdrawParams{UID: "ID092B2AF8E87", Currency: "btc", Amount: MustDecimal("0.00000001")})
+	assert.Nil(t, apiError)
+	assert.Equal(t, "0.00000001", withdraw.Amount.String())
+	assert.Equal(t, "withdraws/new", mock.path)
+
+	// Currencies without known precision are fetched once and cached
+	mock.response = []byte(`{"id":"usdt","precision":6}`)
+	_, apiError = client.CreateWithdraw(CreateWithdrawParams{UID: "ID092B2AF8E87", Currency: "usdt", Amount: MustDecimal("0.000000001")})
+	assert.NotNil(t, apiError)
+	assert.Equal(t, "amount exceeds currency precision: 0.000000001 has more than 6 decimals", apiError.Error)
+	assert.Equal(t, "currencies/usdt", mock.path)
+
+	mock.path = ""
+	_, apiError = client.CreateWithdraw(CreateWithdrawParams{UID: "ID092B2AF8E87", Currency: "usdt", Amount: MustDecimal("0.0000001")})
+	assert.NotNil(t, apiError)
+	assert.Empty(t, mock.path)
+}
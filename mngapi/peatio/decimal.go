@@ -0,0 +1,74 @@
+package peatio
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrPrecision is returned when an amount has more decimals than its currency precision
+var ErrPrecision = errors.New("amount exceeds currency precision")
+
+// Decimal is an exact decimal number used for amounts, prices and fees.
+// It is unmarshaled from JSON strings or numbers and marshaled to a JSON string
+// keeping its scale, so that "0.0" is sent back as "0.0".
+type Decimal struct {
+	decimal.Decimal
+}
+
+// NewDecimal parses a decimal number such as "0.000000000000000001"
+func NewDecimal(value string) (Decimal, error) {
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	return Decimal{d}, nil
+}
+
+// MustDecimal is like NewDecimal but panics if the value can't be parsed
+func MustDecimal(value string) Decimal {
+	d, err := NewDecimal(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// String returns the decimal with its scale, e.g. "280.0"
+func (d Decimal) String() string {
+	if exp := d.Exponent(); exp < 0 {
+		return d.StringFixed(-exp)
+	}
+
+	return d.Decimal.String()
+}
+
+// MarshalJSON implements json.Marshaler
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, null and empty strings are read as zero
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) || bytes.Equal(data, []byte(`""`)) {
+		*d = Decimal{}
+		return nil
+	}
+
+	return d.Decimal.UnmarshalJSON(data)
+}
+
+// ValidatePrecision returns ErrPrecision if the decimal has more than precision decimals,
+// trailing zeros are ignored
+func (d Decimal) ValidatePrecision(precision int32) error {
+	if !d.Truncate(precision).Equal(d.Decimal) {
+		return fmt.Errorf("%w: %s has more than %d decimals", ErrPrecision, d, precision)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package peatio
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecimalJSON(t *testing.T) {
+	t.Run("Round trip string", func(t *testing.T) {
+		for _, value := range []string{`"0.0"`, `"280.0"`, `"0.000000000000000001"`, `"123"`, `"9916678.1751516791"`} {
+			var d Decimal
+			require.NoError(t, json.Unmarshal([]byte(value), &d))
+
+			result, err := json.Marshal(d)
+			require.NoError(t, err)
+			assert.Equal(t, value, string(result))
+		}
+	})
+
+	t.Run("Number", func(t *testing.T) {
+		var b Balance
+		require.NoError(t, json.Unmarshal([]byte(`{"balance":996.23352165725,"locked":0.0}`), &b))
+		assert.Equal(t, "996.23352165725", b.Balance.String())
+		assert.Equal(t, "0.0", b.Locked.String())
+	})
+
+	t.Run("Null and empty string", func(t *testing.T) {
+		var w Withdraw
+		require.NoError(t, json.Unmarshal([]byte(`{"amount":null,"fee":""}`), &w))
+		assert.True(t, w.Amount.IsZero())
+		assert.True(t, w.Fee.IsZero())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		var d Decimal
+		assert.Error(t, json.Unmarshal([]byte(`"1.2.3"`), &d))
+		assert.Error(t, json.Unmarshal([]byte(`true`), &d))
+	})
+
+	t.Run("Exact params", func(t *testing.T) {
+		result, err := json.Marshal(CreateWithdrawParams{UID: "ID092B2AF8E87", Currency: "eth", Amount: MustDecimal("1.000000000000000001")})
+		require.NoError(t, err)
+		assert.Equal(t, `{"uid":"ID092B2AF8E87","currency":"eth","amount":"1.000000000000000001"}`, string(result))
+	})
+}
+
+func TestDecimalValidatePrecision(t *testing.T) {
+	assert.NoError(t, MustDecimal("1.12").ValidatePrecision(2))
+	assert.NoError(t, MustDecimal("1.1200").ValidatePrecision(2))
+	assert.NoError(t, MustDecimal("100").ValidatePrecision(0))
+
+	err := MustDecimal("0.000000000000000001").ValidatePrecision(8)
+	assert.True(t, errors.Is(err, ErrPrecision))
+	assert.EqualError(t, err, "amount exceeds currency precision: 0.000000000000000001 has more than 8 decimals")
+}
@@ -0,0 +1,157 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChannel records the queues declared and bound, and delivers the messages sent to it
+type fakeChannel struct {
+	queue    string
+	durable  bool
+	bindings []string
+	messages chan amqp.Delivery
+	bindErr  error
+	closed   bool
+}
+
+func (c *fakeChannel) QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error) {
+	c.durable = durable
+	if name == "" {
+		name = "amq.gen-1"
+	}
+	c.queue = name
+	return amqp.Queue{Name: name}, nil
+}
+
+func (c *fakeChannel) QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error {
+	c.bindings = append(c.bindings, exchange+" "+key+" "+name)
+	return c.bindErr
+}
+
+func (c *fakeChannel) Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+	if autoAck {
+		return nil, errors.New("unexpected auto-ack")
+	}
+	return c.messages, nil
+}
+
+func (c *fakeChannel) Close() error {
+	c.closed = true
+	return nil
+}
+
+// fakeAcknowledger records how the deliveries are settled
+type fakeAcknowledger struct {
+	settled []string
+}
+
+func (a *fakeAcknowledger) Ack(tag uint64, multiple bool) error {
+	if multiple {
+		return errors.New("unexpected multiple ack")
+	}
+	a.settled = append(a.settled, "ack")
+	return nil
+}
+
+func (a *fakeAcknowledger) Nack(tag uint64, multiple, requeue bool) error {
+	if multiple {
+		return errors.New("unexpected multiple nack")
+	}
+	if requeue {
+		a.settled = append(a.settled, "requeue")
+	} else {
+		a.settled = append(a.settled, "nack")
+	}
+	return nil
+}
+
+func (a *fakeAcknowledger) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+func newFakeAMQPTransport(ch *fakeChannel, queue string) *AMQPTransport {
+	return &AMQPTransport{
+		channel: func() (amqpChannel, error) {
+			return ch, nil
+		},
+		queue: queue,
+	}
+}
+
+func TestAMQPTransport(t *testing.T) {
+	t.Run("Consume the bound queue", func(t *testing.T) {
+		ch := &fakeChannel{messages: make(chan amqp.Delivery, 3)}
+		transport := newFakeAMQPTransport(ch, "applogic.events")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		deliveries, err := transport.Subscribe(ctx, ModelExchange, []string{DepositCreated, DepositUpdated})
+		require.NoError(t, err)
+		assert.True(t, ch.durable)
+		assert.Equal(t, []string{
+			ModelExchange + " " + DepositCreated + " applogic.events",
+			ModelExchange + " " + DepositUpdated + " applogic.events",
+		}, ch.bindings)
+
+		acknowledger := &fakeAcknowledger{}
+		for i := 0; i < 3; i++ {
+			ch.messages <- amqp.Delivery{
+				Acknowledger: acknowledger,
+				Exchange:     ModelExchange,
+				RoutingKey:   DepositCreated,
+				Body:         []byte("{}"),
+				Redelivered:  i > 0,
+			}
+		}
+
+		d := <-deliveries
+		assert.Equal(t, ModelExchange, d.Exchange)
+		assert.Equal(t, DepositCreated, d.RoutingKey)
+		assert.Equal(t, "{}", string(d.Body))
+		assert.False(t, d.Redelivered)
+		require.NoError(t, d.Ack())
+
+		d = <-deliveries
+		assert.True(t, d.Redelivered)
+		require.NoError(t, d.Nack(true))
+
+		d = <-deliveries
+		require.NoError(t, d.Nack(false))
+		assert.Equal(t, []string{"ack", "requeue", "nack"}, acknowledger.settled)
+
+		// The subscription ends with the AMQP channel
+		close(ch.messages)
+		_, ok := <-deliveries
+		assert.False(t, ok)
+	})
+
+	t.Run("Exclusive queue without name", func(t *testing.T) {
+		ch := &fakeChannel{messages: make(chan amqp.Delivery)}
+		transport := newFakeAMQPTransport(ch, "")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		deliveries, err := transport.Subscribe(ctx, ModelExchange, []string{"#"})
+		require.NoError(t, err)
+		assert.False(t, ch.durable)
+		assert.Equal(t, []string{ModelExchange + " # amq.gen-1"}, ch.bindings)
+
+		cancel()
+		_, ok := <-deliveries
+		assert.False(t, ok)
+	})
+
+	t.Run("Bind error", func(t *testing.T) {
+		ch := &fakeChannel{bindErr: errors.New("NOT_FOUND - no exchange")}
+		transport := newFakeAMQPTransport(ch, "applogic.events")
+
+		_, err := transport.Subscribe(context.Background(), ModelExchange, []string{"#"})
+		assert.EqualError(t, err, "NOT_FOUND - no exchange")
+		assert.True(t, ch.closed)
+	})
+}
@@ -0,0 +1,248 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/openware/pkg/mngapi"
+	"github.com/openware/pkg/mngapi/peatio"
+)
+
+// ModelExchange is the exchange of peatio model events
+const ModelExchange = "peatio.events.model"
+
+// Routing keys of the peatio model events
+const (
+	DepositCreated  = "model.deposit.created"
+	DepositUpdated  = "model.deposit.updated"
+	WithdrawCreated = "model.withdraw.created"
+	WithdrawUpdated = "model.withdraw.updated"
+	TradeCompleted  = "model.trade.completed"
+)
+
+// Event is a peatio model event, Name defaults to the routing key
+// and Changes holds the previous values of the updated fields
+type Event[T any] struct {
+	Name    string                 `json:"name"`
+	Record  T                      `json:"record"`
+	Changes map[string]interface{} `json:"changes"`
+}
+
+// DepositEvent is published when a deposit is created or updated
+type DepositEvent = Event[peatio.Deposit]
+
+// WithdrawEvent is published when a withdraw is created or updated
+type WithdrawEvent = Event[peatio.Withdraw]
+
+// TradeEvent is published when a trade is completed
+type TradeEvent = Event[peatio.Trade]
+
+// Handler handles a decoded event, returning an error to retry it
+type Handler[T any] func(ctx context.Context, event *Event[T]) error
+
+// permanentError is not retried
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent wraps a handler error so that the event is rejected without retry
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// Consumer subscribes to peatio model events and dispatches them to typed handlers.
+// Events are acknowledged once handled, failing handlers are retried following
+// the retry policy and then rejected without requeue, so that the broker dead-letters them.
+//
+//	consumer := events.NewConsumer(transport)
+//	consumer.OnDeposit(func(ctx context.Context, e *events.DepositEvent) error {
+//		...
+//	})
+//	err := consumer.Run(ctx)
+type Consumer struct {
+	transport   Transport
+	exchange    string
+	verifier    *mngapi.Verifier
+	required    []string
+	retryPolicy mngapi.RetryPolicy
+	handlers    map[string]func(ctx context.Context, name string, data []byte) error
+	keys        []string
+	onError     func(d Delivery, err error)
+}
+
+// NewConsumer returns a Consumer of ModelExchange without handler, handlers are retried 3 times
+func NewConsumer(transport Transport) *Consumer {
+	c := &Consumer{
+		transport: transport,
+		exchange:  ModelExchange,
+		handlers:  make(map[string]func(ctx context.Context, name string, data []byte) error),
+		onError:   func(Delivery, error) {},
+	}
+	c.SetRetryPolicy(mngapi.RetryPolicy{MaxRetries: 3})
+
+	return c
+}
+
+// SetExchange sets the exchange to subscribe to, ModelExchange by default
+func (c *Consumer) SetExchange(exchange string) {
+	c.exchange = exchange
+}
+
+// SetVerifier requires the events to be JWS envelopes verified by the verifier,
+// every key id in required must have signed the events.
+// Without verifier the message bodies are the events themselves.
+func (c *Consumer) SetVerifier(verifier *mngapi.Verifier, required ...string) {
+	c.verifier = verifier
+	c.required = required
+}
+
+// SetRetryPolicy sets the retry policy of failing handlers
+func (c *Consumer) SetRetryPolicy(policy mngapi.RetryPolicy) {
+	if policy.MinBackoff <= 0 {
+		policy.MinBackoff = 100 * time.Millisecond
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = 5 * time.Second
+	}
+
+	c.retryPolicy = policy
+}
+
+// OnError registers a function called with the deliveries rejected because of an invalid event
+// or a failing handler, e.g. to log them
+func (c *Consumer) OnError(fn func(d Delivery, err error)) {
+	c.onError = fn
+}
+
+// OnDeposit registers the handler of deposit created and updated events
+func (c *Consumer) OnDeposit(handler Handler[peatio.Deposit]) {
+	handle(c, handler, DepositCreated, DepositUpdated)
+}
+
+// OnWithdraw registers the handler of withdraw created and updated events
+func (c *Consumer) OnWithdraw(handler Handler[peatio.Withdraw]) {
+	handle(c, handler, WithdrawCreated, WithdrawUpdated)
+}
+
+// OnTrade registers the handler of trade completed events
+func (c *Consumer) OnTrade(handler Handler[peatio.Trade]) {
+	handle(c, handler, TradeCompleted)
+}
+
+// handle registers a typed handler for routing keys
+func handle[T any](c *Consumer, handler Handler[T], routingKeys ...string) {
+	decode := func(ctx context.Context, name string, data []byte) error {
+		event := &Event[T]{}
+		if err := json.Unmarshal(data, event); err != nil {
+			return Permanent(fmt.Errorf("Invalid %s event: %w", name, err))
+		}
+		if event.Name == "" {
+			event.Name = name
+		}
+
+		return handler(ctx, event)
+	}
+
+	for _, key := range routingKeys {
+		if _, ok := c.handlers[key]; !ok {
+			c.keys = append(c.keys, key)
+		}
+		c.handlers[key] = decode
+	}
+}
+
+// Run subscribes to the events of the registered handlers and dispatches them one at a time,
+// until the context is done or the transport closes the subscription
+func (c *Consumer) Run(ctx context.Context) error {
+	if len(c.keys) == 0 {
+		return fmt.Errorf("No event handler registered")
+	}
+
+	deliveries, err := c.transport.Subscribe(ctx, c.exchange, c.keys)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case d, ok := <-deliveries:
+			if !ok {
+				return nil
+			}
+			if err := c.dispatch(ctx, d); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// dispatch handles a delivery and settles it, only settlement errors are returned
+func (c *Consumer) dispatch(ctx context.Context, d Delivery) error {
+	handler, ok := c.handlers[d.RoutingKey]
+	if !ok {
+		return d.Ack()
+	}
+
+	data, err := c.decode(d.Body)
+	if err != nil {
+		c.onError(d, err)
+		return d.Nack(false)
+	}
+
+	backoff := c.retryPolicy.MinBackoff
+	for attempt := 0; ; attempt++ {
+		err := handler(ctx, d.RoutingKey, data)
+		if err == nil {
+			return d.Ack()
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) || attempt >= c.retryPolicy.MaxRetries {
+			c.onError(d, err)
+			return d.Nack(false)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			// Leave the event to another consumer
+			return d.Nack(true)
+		}
+
+		if backoff *= 2; backoff > c.retryPolicy.MaxBackoff {
+			backoff = c.retryPolicy.MaxBackoff
+		}
+	}
+}
+
+// decode returns the event of a message body, verifying its signatures if required
+func (c *Consumer) decode(body []byte) ([]byte, error) {
+	if c.verifier == nil {
+		return body, nil
+	}
+
+	claims, _, err := c.verifier.Verify(body, c.required...)
+	if err != nil {
+		return nil, err
+	}
+	if len(claims.Event) == 0 {
+		return nil, fmt.Errorf("JWT has no event claim")
+	}
+
+	return claims.Event, nil
+}
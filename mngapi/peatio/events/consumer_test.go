@@ -0,0 +1,265 @@
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt"
+	"github.com/openware/pkg/mngapi"
+	"github.com/openware/pkg/mngapi/peatio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const depositEvent = `{
+	"name": "model.deposit.updated",
+	"record": {
+		"id": 1,
+		"tid": "TID9C4C2D5E76",
+		"uid": "ID873B710D88",
+		"currency": "eth",
+		"amount": "0.5",
+		"state": "accepted",
+		"created_at": "2021-03-09T13:35:18Z",
+		"completed_at": "2021-03-09T13:40:18Z",
+		"transfer_type": "crypto"
+	},
+	"changes": {"state": "submitted"}
+}`
+
+// run starts the consumer until the test ends
+func run(t *testing.T, consumer *Consumer) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- consumer.Run(ctx)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+}
+
+func TestConsumer(t *testing.T) {
+	t.Run("Dispatch typed events", func(t *testing.T) {
+		transport := NewMemoryTransport()
+		consumer := NewConsumer(transport)
+
+		deposits := make(chan *DepositEvent, 100)
+		consumer.OnDeposit(func(ctx context.Context, e *DepositEvent) error {
+			deposits <- e
+			return nil
+		})
+		run(t, consumer)
+
+		require.Eventually(t, func() bool {
+			require.NoError(t, transport.Publish(ModelExchange, DepositUpdated, []byte(depositEvent)))
+			select {
+			case e := <-deposits:
+				assert.Equal(t, "model.deposit.updated", e.Name)
+				assert.Equal(t, "TID9C4C2D5E76", e.Record.TID)
+				assert.Equal(t, peatio.MustDecimal("0.5"), e.Record.Amount)
+				assert.Equal(t, "accepted", e.Record.State)
+				assert.Equal(t, map[string]interface{}{"state": "submitted"}, e.Changes)
+				return true
+			case <-time.After(10 * time.Millisecond):
+				return false
+			}
+		}, time.Second, time.Millisecond)
+		assert.Eventually(t, func() bool { return transport.Acked() >= 1 }, time.Second, time.Millisecond)
+	})
+
+	t.Run("Retry failing handler and dead-letter", func(t *testing.T) {
+		transport := NewMemoryTransport()
+		consumer := NewConsumer(transport)
+		consumer.SetRetryPolicy(mngapi.RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond})
+
+		var failures []error
+		consumer.OnError(func(d Delivery, err error) {
+			failures = append(failures, err)
+		})
+
+		attempts := make(chan string, 10)
+		consumer.OnWithdraw(func(ctx context.Context, e *WithdrawEvent) error {
+			attempts <- e.Record.TID
+			if e.Record.TID == "TID1" {
+				return errors.New("database is down")
+			}
+			return nil
+		})
+
+		ctx := context.Background()
+		deliveries, err := transport.Subscribe(ctx, ModelExchange, []string{"model.withdraw.*"})
+		require.NoError(t, err)
+
+		require.NoError(t, transport.Publish(ModelExchange, WithdrawCreated, []byte(`{"record":{"tid":"TID1","amount":"1.0"}}`)))
+		require.NoError(t, consumer.dispatch(ctx, <-deliveries))
+		assert.Len(t, attempts, 3)
+		assert.EqualError(t, failures[0], "database is down")
+		require.Len(t, transport.DeadLetters(), 1)
+		assert.Equal(t, WithdrawCreated, transport.DeadLetters()[0].RoutingKey)
+
+		require.NoError(t, transport.Publish(ModelExchange, WithdrawUpdated, []byte(`{"record":{"tid":"TID2","amount":"1.0"}}`)))
+		require.NoError(t, consumer.dispatch(ctx, <-deliveries))
+		assert.Equal(t, 1, transport.Acked())
+	})
+
+	t.Run("Permanent errors and invalid events are not retried", func(t *testing.T) {
+		transport := NewMemoryTransport()
+		consumer := NewConsumer(transport)
+
+		calls := 0
+		consumer.OnTrade(func(ctx context.Context, e *TradeEvent) error {
+			calls++
+			return Permanent(errors.New("unknown market"))
+		})
+
+		ctx := context.Background()
+		deliveries, err := transport.Subscribe(ctx, ModelExchange, []string{"model.#"})
+		require.NoError(t, err)
+
+		require.NoError(t, transport.Publish(ModelExchange, TradeCompleted, []byte(`{"record":{"id":1,"market":"ethusd"}}`)))
+		require.NoError(t, consumer.dispatch(ctx, <-deliveries))
+		require.NoError(t, transport.Publish(ModelExchange, TradeCompleted, []byte(`{"record":{"id":"invalid"}}`)))
+		require.NoError(t, consumer.dispatch(ctx, <-deliveries))
+
+		assert.Equal(t, 1, calls)
+		assert.Len(t, transport.DeadLetters(), 2)
+
+		// Events without handler are acknowledged
+		require.NoError(t, transport.Publish(ModelExchange, DepositCreated, []byte(depositEvent)))
+		require.NoError(t, consumer.dispatch(ctx, <-deliveries))
+		assert.Equal(t, 1, transport.Acked())
+	})
+
+	t.Run("Signed events", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		block := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+		signer, err := mngapi.NewRSASigner("peatio", "RS256", base64.StdEncoding.EncodeToString(block))
+		require.NoError(t, err)
+
+		verifier := mngapi.NewVerifier()
+		verifier.AddRSAKey("peatio", &key.PublicKey)
+
+		transport := NewMemoryTransport()
+		consumer := NewConsumer(transport)
+		consumer.SetVerifier(verifier, "peatio")
+
+		var tids []string
+		consumer.OnDeposit(func(ctx context.Context, e *DepositEvent) error {
+			tids = append(tids, e.Record.TID)
+			return nil
+		})
+
+		ctx := context.Background()
+		deliveries, err := transport.Subscribe(ctx, ModelExchange, consumer.keys)
+		require.NoError(t, err)
+
+		require.NoError(t, transport.Publish(ModelExchange, DepositUpdated, sign(t, signer, depositEvent)))
+		require.NoError(t, consumer.dispatch(ctx, <-deliveries))
+		require.NoError(t, transport.Publish(ModelExchange, DepositUpdated, []byte(depositEvent)))
+		require.NoError(t, consumer.dispatch(ctx, <-deliveries))
+
+		assert.Equal(t, []string{"TID9C4C2D5E76"}, tids)
+		assert.Len(t, transport.DeadLetters(), 1)
+	})
+
+	t.Run("No handler", func(t *testing.T) {
+		err := NewConsumer(NewMemoryTransport()).Run(context.Background())
+		assert.EqualError(t, err, "No event handler registered")
+	})
+}
+
+// sign returns a JWS envelope of the event as published by peatio
+func sign(t *testing.T, signer mngapi.JWSSigner, event string) []byte {
+	payload, err := json.Marshal(map[string]interface{}{
+		"iss":   "peatio",
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"event": json.RawMessage(event),
+	})
+	require.NoError(t, err)
+
+	protected := jwtgo.EncodeSegment([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	encodedPayload := jwtgo.EncodeSegment(payload)
+	sig, err := signer.Sign(protected + "." + encodedPayload)
+	require.NoError(t, err)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"payload": encodedPayload,
+		"signatures": []map[string]interface{}{{
+			"protected": protected,
+			"header":    map[string]string{"kid": signer.KeyID()},
+			"signature": sig,
+		}},
+	})
+	require.NoError(t, err)
+
+	return body
+}
+
+func TestMatchTopic(t *testing.T) {
+	assert.True(t, matchTopic("model.deposit.created", "model.deposit.created"))
+	assert.True(t, matchTopic("model.*.created", "model.deposit.created"))
+	assert.True(t, matchTopic("model.#", "model.deposit.created"))
+	assert.True(t, matchTopic("#", "model"))
+	assert.True(t, matchTopic("model.#.created", "model.created"))
+	assert.False(t, matchTopic("model.*", "model.deposit.created"))
+	assert.False(t, matchTopic("model.withdraw.*", "model.deposit.created"))
+}
+
+func TestMemoryTransport(t *testing.T) {
+	transport := NewMemoryTransport()
+	deliveries, err := transport.Subscribe(context.Background(), ModelExchange, []string{"model.deposit.*"})
+	require.NoError(t, err)
+
+	require.NoError(t, transport.Publish("other", DepositCreated, []byte("{}")))
+	require.NoError(t, transport.Publish(ModelExchange, WithdrawCreated, []byte("{}")))
+	require.NoError(t, transport.Publish(ModelExchange, DepositCreated, []byte(`{"n":1}`)))
+
+	d := <-deliveries
+	assert.Equal(t, `{"n":1}`, string(d.Body))
+	assert.False(t, d.Redelivered)
+	require.NoError(t, d.Nack(true))
+	assert.Error(t, d.Ack())
+
+	d = <-deliveries
+	assert.True(t, d.Redelivered)
+	require.NoError(t, d.Ack())
+	assert.Equal(t, 1, transport.Acked())
+
+	require.NoError(t, transport.Close())
+	_, ok := <-deliveries
+	assert.False(t, ok)
+	assert.Equal(t, ErrClosed, transport.Publish(ModelExchange, DepositCreated, nil))
+}
+
+func TestMemoryTransportUnsubscribe(t *testing.T) {
+	transport := NewMemoryTransport()
+	ctx, cancel := context.WithCancel(context.Background())
+	deliveries, err := transport.Subscribe(ctx, ModelExchange, []string{"#"})
+	require.NoError(t, err)
+	_, err = transport.Subscribe(context.Background(), ModelExchange, []string{"#"})
+	require.NoError(t, err)
+
+	cancel()
+	_, ok := <-deliveries
+	assert.False(t, ok)
+
+	// The subscription is removed once its channel is closed
+	assert.Eventually(t, func() bool {
+		transport.mu.Lock()
+		defer transport.mu.Unlock()
+		return len(transport.subscriptions) == 1
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,230 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrClosed is returned when using a closed transport
+var ErrClosed = errors.New("transport is closed")
+
+// MemoryTransport is an in-memory Transport for tests, published messages are delivered
+// to every matching subscription and rejected messages are requeued or dead-lettered
+type MemoryTransport struct {
+	mu            sync.Mutex
+	subscriptions []*subscription
+	deadLetters   []Delivery
+	acked         int
+	closed        bool
+}
+
+type subscription struct {
+	exchange    string
+	routingKeys []string
+
+	mu     sync.Mutex
+	queue  []Delivery
+	notify chan struct{}
+	done   chan struct{}
+}
+
+// NewMemoryTransport returns a MemoryTransport without subscription
+func NewMemoryTransport() *MemoryTransport {
+	return &MemoryTransport{}
+}
+
+// Subscribe implements Transport, the channel is closed when the context is done or on Close
+// and the subscription removed
+func (t *MemoryTransport) Subscribe(ctx context.Context, exchange string, routingKeys []string) (<-chan Delivery, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return nil, ErrClosed
+	}
+
+	s := &subscription{
+		exchange:    exchange,
+		routingKeys: routingKeys,
+		notify:      make(chan struct{}, 1),
+		done:        make(chan struct{}),
+	}
+	t.subscriptions = append(t.subscriptions, s)
+
+	deliveries := make(chan Delivery)
+	go func() {
+		s.run(ctx, deliveries)
+		t.unsubscribe(s)
+	}()
+
+	return deliveries, nil
+}
+
+// unsubscribe removes a subscription, its messages are no longer queued
+func (t *MemoryTransport) unsubscribe(s *subscription) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, sub := range t.subscriptions {
+		if sub == s {
+			t.subscriptions = append(t.subscriptions[:i], t.subscriptions[i+1:]...)
+			return
+		}
+	}
+}
+
+// Publish sends a message to every subscription bound to the exchange with a matching routing key
+func (t *MemoryTransport) Publish(exchange, routingKey string, body []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return ErrClosed
+	}
+
+	for _, s := range t.subscriptions {
+		if s.exchange != exchange || !s.matches(routingKey) {
+			continue
+		}
+
+		s.push(t.delivery(s, Delivery{
+			Exchange:   exchange,
+			RoutingKey: routingKey,
+			Body:       body,
+		}))
+	}
+
+	return nil
+}
+
+// Acked returns the number of acknowledged deliveries
+func (t *MemoryTransport) Acked() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.acked
+}
+
+// DeadLetters returns the deliveries rejected without requeue
+func (t *MemoryTransport) DeadLetters() []Delivery {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]Delivery{}, t.deadLetters...)
+}
+
+// Close implements Transport, closing the channels of every subscription
+func (t *MemoryTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return nil
+	}
+
+	t.closed = true
+	for _, s := range t.subscriptions {
+		close(s.done)
+	}
+
+	return nil
+}
+
+// delivery binds the Ack and Nack functions of a delivery to the subscription
+func (t *MemoryTransport) delivery(s *subscription, d Delivery) Delivery {
+	var once sync.Once
+	settle := func(fn func()) error {
+		settled := false
+		once.Do(func() {
+			settled = true
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			fn()
+		})
+		if !settled {
+			return errors.New("delivery already acknowledged")
+		}
+		return nil
+	}
+
+	d.Ack = func() error {
+		return settle(func() {
+			t.acked++
+		})
+	}
+	d.Nack = func(requeue bool) error {
+		return settle(func() {
+			if !requeue {
+				t.deadLetters = append(t.deadLetters, d)
+				return
+			}
+
+			redelivery := d
+			redelivery.Redelivered = true
+			s.push(t.delivery(s, redelivery))
+		})
+	}
+
+	return d
+}
+
+func (s *subscription) matches(routingKey string) bool {
+	for _, pattern := range s.routingKeys {
+		if matchTopic(pattern, routingKey) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *subscription) push(d Delivery) {
+	s.mu.Lock()
+	s.queue = append(s.queue, d)
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (s *subscription) pop() (Delivery, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.queue) == 0 {
+		return Delivery{}, false
+	}
+
+	d := s.queue[0]
+	s.queue = s.queue[1:]
+	return d, true
+}
+
+func (s *subscription) run(ctx context.Context, deliveries chan<- Delivery) {
+	defer close(deliveries)
+
+	for {
+		d, ok := s.pop()
+		if !ok {
+			select {
+			case <-s.notify:
+				continue
+			case <-ctx.Done():
+				return
+			case <-s.done:
+				return
+			}
+		}
+
+		select {
+		case deliveries <- d:
+		case <-ctx.Done():
+			return
+		case <-s.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package events
+
+import (
+	"context"
+	"strings"
+)
+
+// Delivery is a message received from a Transport, following AMQP semantics:
+// every delivery must be either acknowledged or rejected
+type Delivery struct {
+	Exchange    string
+	RoutingKey  string
+	Body        []byte
+	Redelivered bool
+
+	// Ack acknowledges the delivery
+	Ack func() error
+	// Nack rejects the delivery, requeued deliveries are delivered again
+	// and the others are discarded or dead-lettered by the broker
+	Nack func(requeue bool) error
+}
+
+// Transport subscribes to the events published by peatio, AMQPTransport binds a queue
+// to the exchange with the routing keys and consumes it without auto-ack
+type Transport interface {
+	Subscribe(ctx context.Context, exchange string, routingKeys []string) (<-chan Delivery, error)
+	Close() error
+}
+
+// matchTopic reports whether a routing key matches an AMQP topic binding key,
+// "*" matches exactly one word and "#" zero or more words
+func matchTopic(pattern, key string) bool {
+	return matchWords(strings.Split(pattern, "."), strings.Split(key, "."))
+}
+
+func matchWords(pattern, key []string) bool {
+	if len(pattern) == 0 {
+		return len(key) == 0
+	}
+
+	switch pattern[0] {
+	case "#":
+		for i := 0; i <= len(key); i++ {
+			if matchWords(pattern[1:], key[i:]) {
+				return true
+			}
+		}
+		return false
+	case "*":
+		return len(key) > 0 && matchWords(pattern[1:], key[1:])
+	default:
+		return len(key) > 0 && pattern[0] == key[0] && matchWords(pattern[1:], key[1:])
+	}
+}
@@ -0,0 +1,83 @@
+package peatio
+
+import (
+	"context"
+
+	"github.com/openware/pkg/mngapi"
+)
+
+// IterateDeposits returns an iterator over every deposit matching the params, from params.Page
+func (p *Client) IterateDeposits(params GetDepositsParams) *mngapi.Iterator[*Deposit] {
+	return mngapi.NewIterator(params.Page, params.Limit, func(ctx context.Context, page, limit int64) ([]*Deposit, error) {
+		params.Page, params.Limit = page, limit
+		return p.GetDepositsContext(ctx, params)
+	})
+}
+
+// IterateWithdraws returns an iterator over every withdraw matching the params, from params.Page
+func (p *Client) IterateWithdraws(params GetWithdrawsParams) *mngapi.Iterator[*Withdraw] {
+	return mngapi.NewIterator(params.Page, params.Limit, func(ctx context.Context, page, limit int64) ([]*Withdraw, error) {
+		params.Page, params.Limit = page, limit
+		return p.GetWithdrawsContext(ctx, params)
+	})
+}
+
+// IterateAccountBalances returns an iterator over every account balance matching the params, from params.Page
+func (p *Client) IterateAccountBalances(params GetAccountBalancesParams) *mngapi.Iterator[*Balance] {
+	return mngapi.NewIterator(params.Page, params.Limit, func(ctx context.Context, page, limit int64) ([]*Balance, error) {
+		params.Page, params.Limit = page, limit
+		return p.GetAccountBalancesContext(ctx, params)
+	})
+}
+
+// IterateTrades returns an iterator over every trade matching the params, from params.Page
+func (p *Client) IterateTrades(params GetTradesParams) *mngapi.Iterator[*Trade] {
+	return mngapi.NewIterator(params.Page, params.Limit, func(ctx context.Context, page, limit int64) ([]*Trade, error) {
+		params.Page, params.Limit = page, limit
+		return p.GetTradesContext(ctx, params)
+	})
+}
+
+// IterateOrders returns an iterator over every order matching the params, from params.Page
+func (p *Client) IterateOrders(params GetOrdersParams) *mngapi.Iterator[*Order] {
+	return mngapi.NewIterator(params.Page, params.Limit, func(ctx context.Context, page, limit int64) ([]*Order, error) {
+		params.Page, params.Limit = page, limit
+		return p.GetOrdersContext(ctx, params)
+	})
+}
+
+// IterateMembers returns an iterator over every member matching the params, from params.Page
+func (p *Client) IterateMembers(params GetMembersParams) *mngapi.Iterator[*Member] {
+	return mngapi.NewIterator(params.Page, params.Limit, func(ctx context.Context, page, limit int64) ([]*Member, error) {
+		params.Page, params.Limit = page, limit
+		return p.GetMembersContext(ctx, params)
+	})
+}
+
+// IterateBeneficiaries returns an iterator over every beneficiary matching the params, from params.Page
+func (p *Client) IterateBeneficiaries(params GetBeneficiariesParams) *mngapi.Iterator[*Beneficiary] {
+	return mngapi.NewIterator(params.Page, params.Limit, func(ctx context.Context, page, limit int64) ([]*Beneficiary, error) {
+		params.Page, params.Limit = page, limit
+		return p.GetBeneficiariesContext(ctx, params)
+	})
+}
+
+// IterateOperations returns an iterator over every accounting operation of the given type matching the params, from params.Page
+func (p *Client) IterateOperations(kind OperationType, params GetOperationsParams) *mngapi.Iterator[*Operation] {
+	return mngapi.NewIterator(params.Page, params.Limit, func(ctx context.Context, page, limit int64) ([]*Operation, error) {
+		params.Page, params.Limit = page, limit
+		return p.GetOperationsContext(ctx, kind, params)
+	})
+}
+
+// IterateCurrencies returns an iterator over every currency matching the params, from params.Page
+func (p *Client) IterateCurrencies(params CurrenciesListParams) *mngapi.Iterator[Currency] {
+	return mngapi.NewIterator(params.Page, params.Limit, func(ctx context.Context, page, limit int64) ([]Currency, error) {
+		params.Page, params.Limit = page, limit
+		currencies, err := p.GetCurrenciesListContext(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return *currencies, nil
+	})
+}
@@ -11,7 +11,7 @@ type CreateWithdrawParams struct {
 	RID           string  `json:"rid,omitempty"`
 	BeneficiaryID string  `json:"beneficiary_id,omitempty"`
 	Currency      string  `json:"currency"`
-	Amount        float64 `json:"amount"`
+	Amount        Decimal `json:"amount"`
 	Note          string  `json:"note,omitempty"`
 	Action        string  `json:"action,omitempty"`
 	TransferType  string  `json:"transfer_type,omitempty"`
@@ -29,7 +29,7 @@ type CreateDepositParams struct {
 	UID          string  `json:"uid"`
 	TID          string  `json:"tid,omitempty"`
 	Currency     string  `json:"currency"`
-	Amount       float64 `json:"amount"`
+	Amount       Decimal `json:"amount"`
 	State        string  `json:"state,omitempty"`
 	TransferType string  `json:"transfer_type,omitempty"`
 }
@@ -58,15 +58,17 @@ type CreateEngineParams struct {
 	Secret string `json:"secret"`
 }
 
+// UpdateEngineParams contain the params of an engine update,
+// State is a pointer so that an engine may be set offline with 0 and left unchanged when nil
 type UpdateEngineParams struct {
 	ID     string `json:"id"`
 	Name   string `json:"name"`
-	Driver string `json:"driver"`
-	UID    string `json:"uid"`
-	URL    string `json:"url"`
-	State  int    `json:"state"`
-	Key    string `json:"key"`
-	Secret string `json:"secret"`
+	Driver string `json:"driver,omitempty"`
+	UID    string `json:"uid,omitempty"`
+	URL    string `json:"url,omitempty"`
+	State  *int   `json:"state,omitempty"`
+	Key    string `json:"key,omitempty"`
+	Secret string `json:"secret,omitempty"`
 }
 
 type CreateMarketParams struct {
@@ -83,17 +85,19 @@ type CreateMarketParams struct {
 }
 
 type UpdateMarketParams struct {
-	ID        string `json:"id"`
-	EngineID  string `json:"engine_id,omitempty"`
-	MinPrice  string `json:"min_price,omitempty"`
-	MaxPrice  string `json:"max_price,omitempty"`
-	MinAmount string `json:"min_amount,omitempty"`
+	ID              string `json:"id"`
+	EngineID        string `json:"engine_id,omitempty"`
+	MinPrice        string `json:"min_price,omitempty"`
+	MaxPrice        string `json:"max_price,omitempty"`
+	MinAmount       string `json:"min_amount,omitempty"`
 	AmountPrecision int64  `json:"amount_precision,omitempty"`
 	PricePrecision  int64  `json:"price_precision,omitempty"`
 }
 
 type CurrenciesListParams struct {
-	Type string `json:"type,omitempty"`
+	Type  string `json:"type,omitempty"`
+	Page  int64  `json:"page,omitempty"`
+	Limit int64  `json:"limit,omitempty"`
 }
 
 type CreateBlockchainCurrencyParams struct {
@@ -134,6 +138,8 @@ type UpdateCurrencyParams struct {
 	IconURL   string `json:"icon_url,omitempty"`
 }
 
+// UpdateBlockchainCurrencyParams contain the params of a blockchain currency update,
+// DepositEnabled and WithdrawEnabled are pointers so that they may be disabled and left unchanged when nil
 type UpdateBlockchainCurrencyParams struct {
 	ID                  string                 `json:"id"`
 	DepositFee          string                 `json:"deposit_fee,omitempty"`
@@ -141,8 +147,8 @@ type UpdateBlockchainCurrencyParams struct {
 	MinCollectionAmount string                 `json:"min_collection_amount,omitempty"`
 	WithdrawFee         string                 `json:"withdraw_fee,omitempty"`
 	MinWithdrawAmount   string                 `json:"min_withdraw_amount,omitempty"`
-	DepositEnabled      bool                   `json:"deposit_enabled,omitempty"`
-	WithdrawEnabled     bool                   `json:"withdrawal_enabled,omitempty"`
+	DepositEnabled      *bool                  `json:"deposit_enabled,omitempty"`
+	WithdrawEnabled     *bool                  `json:"withdrawal_enabled,omitempty"`
 	Status              string                 `json:"status,omitempty"`
 	Options             map[string]interface{} `json:"options"`
 }
@@ -184,3 +190,106 @@ type UpdateWalletParams struct {
 	MaxBalance    string   `json:"max_balance,omitempty"`
 	Status        string   `json:"status,omitempty"`
 }
+
+type GetAccountBalancesParams struct {
+	Currency string `json:"currency"`
+	Page     int64  `json:"page,omitempty"`
+	Limit    int64  `json:"limit,omitempty"`
+}
+
+type GetWithdrawsParams struct {
+	UID           string `json:"uid,omitempty"`
+	Currency      string `json:"currency,omitempty"`
+	BlockchainKey string `json:"blockchain_key,omitempty"`
+	State         string `json:"state,omitempty"`
+	RID           string `json:"rid,omitempty"`
+	Page          int64  `json:"page,omitempty"`
+	Limit         int64  `json:"limit,omitempty"`
+}
+
+type WithdrawActionParams struct {
+	TID    string `json:"tid"`
+	Action string `json:"action"`
+	TxID   string `json:"txid,omitempty"`
+}
+
+type UpdateDepositStateParams struct {
+	TID   string `json:"tid"`
+	State string `json:"state"`
+}
+
+type GetTradesParams struct {
+	Market   string `json:"market,omitempty"`
+	UID      string `json:"uid,omitempty"`
+	OrderID  int64  `json:"order_id,omitempty"`
+	TimeFrom int64  `json:"time_from,omitempty"`
+	TimeTo   int64  `json:"time_to,omitempty"`
+	Page     int64  `json:"page,omitempty"`
+	Limit    int64  `json:"limit,omitempty"`
+}
+
+type GetOrdersParams struct {
+	Market  string `json:"market,omitempty"`
+	State   string `json:"state,omitempty"`
+	OrdType string `json:"ord_type,omitempty"`
+	UID     string `json:"uid,omitempty"`
+	Page    int64  `json:"page,omitempty"`
+	Limit   int64  `json:"limit,omitempty"`
+}
+
+type CancelOrdersParams struct {
+	Market string `json:"market,omitempty"`
+	UID    string `json:"uid,omitempty"`
+	Side   string `json:"side,omitempty"`
+}
+
+type GetMembersParams struct {
+	UID   string `json:"uid,omitempty"`
+	Email string `json:"email,omitempty"`
+	Group string `json:"group,omitempty"`
+	State string `json:"state,omitempty"`
+	Page  int64  `json:"page,omitempty"`
+	Limit int64  `json:"limit,omitempty"`
+}
+
+type SetMemberGroupParams struct {
+	UID   string `json:"uid"`
+	Group string `json:"group"`
+}
+
+type GetBeneficiariesParams struct {
+	UID           string `json:"uid,omitempty"`
+	Currency      string `json:"currency,omitempty"`
+	BlockchainKey string `json:"blockchain_key,omitempty"`
+	State         string `json:"state,omitempty"`
+	Page          int64  `json:"page,omitempty"`
+	Limit         int64  `json:"limit,omitempty"`
+}
+
+type CreateBeneficiaryParams struct {
+	UID           string                 `json:"uid"`
+	Currency      string                 `json:"currency"`
+	BlockchainKey string                 `json:"blockchain_key,omitempty"`
+	Name          string                 `json:"name"`
+	Description   string                 `json:"description,omitempty"`
+	Data          map[string]interface{} `json:"data"`
+	State         string                 `json:"state,omitempty"`
+}
+
+type GetOperationsParams struct {
+	Currency      string `json:"currency,omitempty"`
+	ReferenceType string `json:"reference_type,omitempty"`
+	UID           string `json:"uid,omitempty"`
+	TimeFrom      int64  `json:"time_from,omitempty"`
+	TimeTo        int64  `json:"time_to,omitempty"`
+	Page          int64  `json:"page,omitempty"`
+	Limit         int64  `json:"limit,omitempty"`
+}
+
+type CreateOperationParams struct {
+	Currency string   `json:"currency"`
+	Code     int64    `json:"code"`
+	UID      string   `json:"uid,omitempty"`
+	Debit    *Decimal `json:"debit,omitempty"`
+	Credit   *Decimal `json:"credit,omitempty"`
+}
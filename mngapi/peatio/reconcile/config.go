@@ -0,0 +1,190 @@
+package reconcile
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/openware/pkg/ika"
+)
+
+// Config describes the desired currencies, engines, markets and wallets of an exchange
+//
+//	currencies:
+//	  - code: eth
+//	    name: Ethereum
+//	    precision: 8
+//	    networks:
+//	      - blockchain_key: eth-mainnet
+//	        withdraw_fee: "0.001"
+//	engines:
+//	  - name: peatio-default-engine
+//	    driver: peatio
+//	markets:
+//	  - base: eth
+//	    quote: usdt
+//	    engine: peatio-default-engine
+//	    min_price: "0.01"
+//	wallets:
+//	  - name: Ethereum Deposit Wallet
+//	    kind: deposit
+//	    gateway: geth
+//	    blockchain_key: eth-mainnet
+//	    currencies: [eth]
+type Config struct {
+	Currencies []Currency `yaml:"currencies"`
+	Engines    []Engine   `yaml:"engines"`
+	Markets    []Market   `yaml:"markets"`
+	Wallets    []Wallet   `yaml:"wallets"`
+}
+
+// Currency is identified by its code, Type, Description, Homepage and Price are only set on creation
+type Currency struct {
+	Code        string    `yaml:"code"`
+	Name        string    `yaml:"name"`
+	Type        string    `yaml:"type"`
+	Description string    `yaml:"description"`
+	Homepage    string    `yaml:"homepage"`
+	IconURL     string    `yaml:"icon_url"`
+	Price       string    `yaml:"price"`
+	Status      string    `yaml:"status"`
+	Precision   int64     `yaml:"precision"`
+	Position    int64     `yaml:"position"`
+	Networks    []Network `yaml:"networks"`
+}
+
+// Network is a blockchain currency identified by the currency and its blockchain key,
+// ParentID and BaseFactor are only set on creation. Deposits and withdrawals are left
+// unchanged when DepositEnabled and WithdrawEnabled are not set
+type Network struct {
+	BlockchainKey       string                 `yaml:"blockchain_key"`
+	ParentID            string                 `yaml:"parent_id"`
+	BaseFactor          int64                  `yaml:"base_factor"`
+	DepositFee          string                 `yaml:"deposit_fee"`
+	MinDepositAmount    string                 `yaml:"min_deposit_amount"`
+	MinCollectionAmount string                 `yaml:"min_collection_amount"`
+	WithdrawFee         string                 `yaml:"withdraw_fee"`
+	MinWithdrawAmount   string                 `yaml:"min_withdraw_amount"`
+	DepositEnabled      *bool                  `yaml:"deposit_enabled"`
+	WithdrawEnabled     *bool                  `yaml:"withdraw_enabled"`
+	Status              string                 `yaml:"status"`
+	Options             map[string]interface{} `yaml:"options"`
+}
+
+// Engine is identified by its name, Key and Secret are only set on creation.
+// State is 1 for online and 0 for offline, engines are created online and their state
+// is left unchanged when not set
+type Engine struct {
+	Name   string `yaml:"name"`
+	Driver string `yaml:"driver"`
+	UID    string `yaml:"uid"`
+	URL    string `yaml:"url"`
+	State  *int   `yaml:"state"`
+	Key    string `yaml:"key"`
+	Secret string `yaml:"secret"`
+}
+
+// Market is identified by its base and quote currencies, State and Position are only set on creation
+type Market struct {
+	Base            string `yaml:"base"`
+	Quote           string `yaml:"quote"`
+	Engine          string `yaml:"engine"`
+	State           string `yaml:"state"`
+	AmountPrecision int64  `yaml:"amount_precision"`
+	PricePrecision  int64  `yaml:"price_precision"`
+	MinPrice        string `yaml:"min_price"`
+	MaxPrice        string `yaml:"max_price"`
+	MinAmount       string `yaml:"min_amount"`
+	Position        int64  `yaml:"position"`
+}
+
+// ID returns the market id, e.g. ethusdt
+func (m Market) ID() string {
+	return m.Base + m.Quote
+}
+
+// Wallet is identified by its name, Settings are only set on creation
+type Wallet struct {
+	Name          string   `yaml:"name"`
+	Kind          string   `yaml:"kind"`
+	Gateway       string   `yaml:"gateway"`
+	Address       string   `yaml:"address"`
+	BlockchainKey string   `yaml:"blockchain_key"`
+	Currencies    []string `yaml:"currencies"`
+	MaxBalance    string   `yaml:"max_balance"`
+	Status        string   `yaml:"status"`
+	URI           string   `yaml:"uri"`
+	Secret        string   `yaml:"secret"`
+}
+
+// LoadConfig reads a YAML or JSON configuration file with ika
+func LoadConfig(path string) (*Config, error) {
+	// ika skips missing files, a mistyped path would plan no change
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	if err := ika.ReadConfig(path, cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Validate checks that every resource has an identifier and is described once
+func (cfg *Config) Validate() error {
+	seen := make(map[string]bool)
+	unique := func(kind, id string) error {
+		if id == "" {
+			return fmt.Errorf("%s without identifier", kind)
+		}
+		if seen[kind+" "+id] {
+			return fmt.Errorf("%s %s is described twice", kind, id)
+		}
+		seen[kind+" "+id] = true
+		return nil
+	}
+
+	for _, c := range cfg.Currencies {
+		if err := unique("currency", c.Code); err != nil {
+			return err
+		}
+		for _, n := range c.Networks {
+			if n.BlockchainKey == "" {
+				return fmt.Errorf("blockchain currency of %s without blockchain key", c.Code)
+			}
+			if err := unique("blockchain currency", networkID(c.Code, n.BlockchainKey)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, e := range cfg.Engines {
+		if err := unique("engine", e.Name); err != nil {
+			return err
+		}
+	}
+	for _, m := range cfg.Markets {
+		if m.Base == "" || m.Quote == "" {
+			return fmt.Errorf("market %s without base or quote currency", m.ID())
+		}
+		if err := unique("market", m.ID()); err != nil {
+			return err
+		}
+	}
+	for _, w := range cfg.Wallets {
+		if err := unique("wallet", w.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// networkID identifies a blockchain currency, e.g. eth/eth-mainnet
+func networkID(currency, blockchainKey string) string {
+	return currency + "/" + blockchainKey
+}
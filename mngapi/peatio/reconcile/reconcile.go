@@ -0,0 +1,724 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openware/pkg/mngapi/peatio"
+)
+
+// Client is the part of peatio.Client used by the Reconciler
+type Client interface {
+	GetCurrenciesListContext(ctx context.Context, params peatio.CurrenciesListParams) (*[]peatio.Currency, error)
+	CreateCurrencyContext(ctx context.Context, params peatio.CreateCurrencyParams) (*peatio.Currency, error)
+	UpdateCurrencyContext(ctx context.Context, params peatio.UpdateCurrencyParams) (*peatio.Currency, error)
+	CreateBlockchainCurrencyContext(ctx context.Context, params peatio.CreateBlockchainCurrencyParams) (*peatio.BlockchainCurrency, error)
+	UpdateBlockchainCurrencyContext(ctx context.Context, params peatio.UpdateBlockchainCurrencyParams) (*peatio.BlockchainCurrency, error)
+	GetEnginesContext(ctx context.Context, params peatio.GetEngineParams) ([]*peatio.Engine, error)
+	CreateEngineContext(ctx context.Context, params peatio.CreateEngineParams) (*peatio.Engine, error)
+	UpdateEngineContext(ctx context.Context, params peatio.UpdateEngineParams) (*peatio.Engine, error)
+	GetMarketsContext(ctx context.Context) ([]*peatio.Market, error)
+	CreateMarketContext(ctx context.Context, params peatio.CreateMarketParams) (*peatio.Market, error)
+	UpdateMarketContext(ctx context.Context, params peatio.UpdateMarketParams) (*peatio.Market, error)
+	GetWalletsContext(ctx context.Context) ([]*peatio.Wallet, error)
+	CreateWalletContext(ctx context.Context, params peatio.CreateWalletParams) (*peatio.Wallet, error)
+	UpdateWalletContext(ctx context.Context, params peatio.UpdateWalletParams) (*peatio.Wallet, error)
+}
+
+// Action of a planned change
+type Action string
+
+const (
+	Create Action = "create"
+	Update Action = "update"
+)
+
+// Diff is a field updated by a change
+type Diff struct {
+	Field string
+	From  string
+	To    string
+}
+
+// Change is a resource to create or update
+type Change struct {
+	Action Action
+	Kind   string
+	ID     string
+	Diffs  []Diff
+
+	apply func(ctx context.Context) error
+}
+
+// Plan lists the changes to apply, in dependency order:
+// currencies, blockchain currencies, engines, markets and wallets
+type Plan struct {
+	Changes []Change
+}
+
+// Empty reports whether the exchange already matches the configuration
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String renders the plan for review, one line per change prefixed with + for a creation
+// or ~ for an update followed by the updated fields, e.g. "min_price: 0.01 -> 0.1",
+// and a summary of the number of changes
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "No changes, the exchange matches the configuration.\n"
+	}
+
+	var b strings.Builder
+	created, updated := 0, 0
+	for _, c := range p.Changes {
+		switch c.Action {
+		case Create:
+			created++
+			fmt.Fprintf(&b, "+ %s %s\n", c.Kind, c.ID)
+		case Update:
+			updated++
+			fmt.Fprintf(&b, "~ %s %s\n", c.Kind, c.ID)
+			for _, d := range c.Diffs {
+				fmt.Fprintf(&b, "    %s: %s -> %s\n", d.Field, d.From, d.To)
+			}
+		}
+	}
+	fmt.Fprintf(&b, "\nPlan: %d to create, %d to update.\n", created, updated)
+
+	return b.String()
+}
+
+// Reconciler plans and applies the changes bringing an exchange to a Config.
+// Resources are never deleted, and running it again once applied plans no change.
+type Reconciler struct {
+	client Client
+}
+
+// New returns a Reconciler using a peatio management API client
+func New(client Client) *Reconciler {
+	return &Reconciler{client: client}
+}
+
+// state is the current configuration of the exchange
+type state struct {
+	currencies map[string]peatio.Currency
+	engines    map[string]*peatio.Engine
+	markets    map[string]*peatio.Market
+	wallets    map[string]*peatio.Wallet
+}
+
+func (r *Reconciler) fetch(ctx context.Context) (*state, error) {
+	s := &state{
+		currencies: make(map[string]peatio.Currency),
+		engines:    make(map[string]*peatio.Engine),
+		markets:    make(map[string]*peatio.Market),
+		wallets:    make(map[string]*peatio.Wallet),
+	}
+
+	currencies, err := r.client.GetCurrenciesListContext(ctx, peatio.CurrenciesListParams{})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list currencies: %w", err)
+	}
+	for _, c := range *currencies {
+		s.currencies[c.ID] = c
+	}
+
+	engines, err := r.client.GetEnginesContext(ctx, peatio.GetEngineParams{})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list engines: %w", err)
+	}
+	for _, e := range engines {
+		s.engines[e.Name] = e
+	}
+
+	markets, err := r.client.GetMarketsContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list markets: %w", err)
+	}
+	for _, m := range markets {
+		s.markets[m.ID] = m
+	}
+
+	wallets, err := r.client.GetWalletsContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list wallets: %w", err)
+	}
+	for _, w := range wallets {
+		s.wallets[w.Name] = w
+	}
+
+	return s, nil
+}
+
+// Plan compares the configuration with the exchange and returns the changes to apply
+func (r *Reconciler) Plan(ctx context.Context, cfg *Config) (*Plan, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	s, err := r.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkReferences(cfg, s); err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{}
+	for _, c := range cfg.Currencies {
+		if err := r.planCurrency(plan, s, c); err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range cfg.Currencies {
+		for _, n := range c.Networks {
+			if err := r.planNetwork(plan, s, c.Code, n); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, e := range cfg.Engines {
+		r.planEngine(plan, s, e)
+	}
+	for _, m := range cfg.Markets {
+		if err := r.planMarket(plan, s, m); err != nil {
+			return nil, err
+		}
+	}
+	for _, w := range cfg.Wallets {
+		if err := r.planWallet(plan, s, w); err != nil {
+			return nil, err
+		}
+	}
+
+	return plan, nil
+}
+
+// Apply applies the changes in order, stopping at the first failure.
+// As creations are only planned for missing resources, a failed apply is resumed
+// by planning again.
+func (r *Reconciler) Apply(ctx context.Context, plan *Plan) error {
+	for _, c := range plan.Changes {
+		if err := c.apply(ctx); err != nil {
+			return fmt.Errorf("Failed to %s %s %s: %w", c.Action, c.Kind, c.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// Sync plans and applies the changes, returning the applied plan
+func (r *Reconciler) Sync(ctx context.Context, cfg *Config) (*Plan, error) {
+	plan, err := r.Plan(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, r.Apply(ctx, plan)
+}
+
+// checkReferences ensures that markets and wallets reference known currencies and engines
+func checkReferences(cfg *Config, s *state) error {
+	currencies := make(map[string]bool)
+	for id := range s.currencies {
+		currencies[id] = true
+	}
+	for _, c := range cfg.Currencies {
+		currencies[c.Code] = true
+	}
+
+	engines := make(map[string]bool)
+	for name := range s.engines {
+		engines[name] = true
+	}
+	for _, e := range cfg.Engines {
+		engines[e.Name] = true
+	}
+
+	for _, m := range cfg.Markets {
+		for _, code := range []string{m.Base, m.Quote} {
+			if !currencies[code] {
+				return fmt.Errorf("market %s references unknown currency %s", m.ID(), code)
+			}
+		}
+		if m.Engine != "" && !engines[m.Engine] {
+			return fmt.Errorf("market %s references unknown engine %s", m.ID(), m.Engine)
+		}
+	}
+	for _, w := range cfg.Wallets {
+		for _, code := range w.Currencies {
+			if !currencies[code] {
+				return fmt.Errorf("wallet %s references unknown currency %s", w.Name, code)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *Reconciler) planCurrency(plan *Plan, s *state, c Currency) error {
+	current, ok := s.currencies[c.Code]
+	if !ok {
+		plan.Changes = append(plan.Changes, Change{
+			Action: Create,
+			Kind:   "currency",
+			ID:     c.Code,
+			apply: func(ctx context.Context) error {
+				_, err := r.client.CreateCurrencyContext(ctx, peatio.CreateCurrencyParams{
+					Code:        c.Code,
+					Type:        c.Type,
+					Position:    c.Position,
+					Name:        c.Name,
+					Precision:   c.Precision,
+					Price:       c.Price,
+					Status:      c.Status,
+					IconURL:     c.IconURL,
+					Description: c.Description,
+					Homepage:    c.Homepage,
+				})
+				return err
+			},
+		})
+		return nil
+	}
+
+	d := diffs{}
+	d.string("name", current.Name, c.Name)
+	d.string("status", current.Status, c.Status)
+	d.string("icon_url", current.IconURL, c.IconURL)
+	d.int("precision", int64(current.Precision), c.Precision)
+	d.int("position", int64(current.Position), c.Position)
+	if d.empty() {
+		return nil
+	}
+
+	plan.Changes = append(plan.Changes, Change{
+		Action: Update,
+		Kind:   "currency",
+		ID:     c.Code,
+		Diffs:  d.list,
+		apply: func(ctx context.Context) error {
+			_, err := r.client.UpdateCurrencyContext(ctx, peatio.UpdateCurrencyParams{
+				ID:        c.Code,
+				Name:      c.Name,
+				Position:  c.Position,
+				Status:    c.Status,
+				Precision: c.Precision,
+				IconURL:   c.IconURL,
+			})
+			return err
+		},
+	})
+	return nil
+}
+
+func (r *Reconciler) planNetwork(plan *Plan, s *state, currency string, n Network) error {
+	var current *peatio.BlockchainCurrency
+	if c, ok := s.currencies[currency]; ok {
+		for i := range c.Networks {
+			if c.Networks[i].BlockchainKey == n.BlockchainKey {
+				current = &c.Networks[i]
+			}
+		}
+	}
+
+	if current == nil {
+		plan.Changes = append(plan.Changes, Change{
+			Action: Create,
+			Kind:   "blockchain currency",
+			ID:     networkID(currency, n.BlockchainKey),
+			apply: func(ctx context.Context) error {
+				_, err := r.client.CreateBlockchainCurrencyContext(ctx, peatio.CreateBlockchainCurrencyParams{
+					CurrencyID:          currency,
+					BlockchainKey:       n.BlockchainKey,
+					BaseFactor:          n.BaseFactor,
+					ParentID:            n.ParentID,
+					DepositFee:          n.DepositFee,
+					MinDepositAmount:    n.MinDepositAmount,
+					MinCollectionAmount: n.MinCollectionAmount,
+					WithdrawFee:         n.WithdrawFee,
+					MinWithdrawAmount:   n.MinWithdrawAmount,
+					DepositEnabled:      boolValue(n.DepositEnabled),
+					WithdrawEnabled:     boolValue(n.WithdrawEnabled),
+					Status:              n.Status,
+					Options:             n.Options,
+				})
+				return err
+			},
+		})
+		return nil
+	}
+
+	d := diffs{}
+	d.string("status", current.Status, n.Status)
+	d.bool("deposit_enabled", current.DepositEnabled, n.DepositEnabled)
+	d.bool("withdraw_enabled", current.WithdrawEnabled, n.WithdrawEnabled)
+	for _, f := range []struct {
+		name    string
+		current peatio.Decimal
+		desired string
+	}{
+		{"deposit_fee", current.DepositFee, n.DepositFee},
+		{"min_deposit_amount", current.MinDepositAmount, n.MinDepositAmount},
+		{"min_collection_amount", current.MinCollectionAmount, n.MinCollectionAmount},
+		{"withdraw_fee", current.WithdrawFee, n.WithdrawFee},
+		{"min_withdraw_amount", current.MinWithdrawAmount, n.MinWithdrawAmount},
+	} {
+		if err := d.decimal(f.name, f.current, f.desired); err != nil {
+			return fmt.Errorf("blockchain currency %s: %w", networkID(currency, n.BlockchainKey), err)
+		}
+	}
+	if d.empty() {
+		return nil
+	}
+
+	id := current.ID
+	plan.Changes = append(plan.Changes, Change{
+		Action: Update,
+		Kind:   "blockchain currency",
+		ID:     networkID(currency, n.BlockchainKey),
+		Diffs:  d.list,
+		apply: func(ctx context.Context) error {
+			_, err := r.client.UpdateBlockchainCurrencyContext(ctx, peatio.UpdateBlockchainCurrencyParams{
+				ID:                  id,
+				DepositFee:          n.DepositFee,
+				MinDepositAmount:    n.MinDepositAmount,
+				MinCollectionAmount: n.MinCollectionAmount,
+				WithdrawFee:         n.WithdrawFee,
+				MinWithdrawAmount:   n.MinWithdrawAmount,
+				DepositEnabled:      n.DepositEnabled,
+				WithdrawEnabled:     n.WithdrawEnabled,
+				Status:              n.Status,
+				Options:             n.Options,
+			})
+			return err
+		},
+	})
+	return nil
+}
+
+func (r *Reconciler) planEngine(plan *Plan, s *state, e Engine) {
+	current, ok := s.engines[e.Name]
+	if !ok {
+		state := 1
+		if e.State != nil {
+			state = *e.State
+		}
+
+		plan.Changes = append(plan.Changes, Change{
+			Action: Create,
+			Kind:   "engine",
+			ID:     e.Name,
+			apply: func(ctx context.Context) error {
+				_, err := r.client.CreateEngineContext(ctx, peatio.CreateEngineParams{
+					Name:   e.Name,
+					Driver: e.Driver,
+					UID:    e.UID,
+					URL:    e.URL,
+					State:  state,
+					Key:    e.Key,
+					Secret: e.Secret,
+				})
+				return err
+			},
+		})
+		return
+	}
+
+	d := diffs{}
+	d.string("driver", current.Driver, e.Driver)
+	d.string("uid", current.UID, e.UID)
+	d.string("url", current.URL, e.URL)
+	if e.State != nil {
+		if state := engineState(*e.State); current.State != state {
+			d.add("state", current.State, state)
+		}
+	}
+	if d.empty() {
+		return
+	}
+
+	// The management API does not skip empty values of engines, so the current ones are sent
+	// with the fields set in the configuration. The key and secret are left unchanged
+	params := peatio.UpdateEngineParams{
+		ID:     strconv.Itoa(current.ID),
+		Name:   e.Name,
+		Driver: current.Driver,
+		UID:    current.UID,
+		URL:    current.URL,
+	}
+	if e.Driver != "" {
+		params.Driver = e.Driver
+	}
+	if e.UID != "" {
+		params.UID = e.UID
+	}
+	if e.URL != "" {
+		params.URL = e.URL
+	}
+	state := engineStateValue(current.State)
+	if e.State != nil {
+		state = *e.State
+	}
+	params.State = &state
+
+	plan.Changes = append(plan.Changes, Change{
+		Action: Update,
+		Kind:   "engine",
+		ID:     e.Name,
+		Diffs:  d.list,
+		apply: func(ctx context.Context) error {
+			_, err := r.client.UpdateEngineContext(ctx, params)
+			return err
+		},
+	})
+}
+
+func (r *Reconciler) planMarket(plan *Plan, s *state, m Market) error {
+	current, ok := s.markets[m.ID()]
+	if !ok {
+		plan.Changes = append(plan.Changes, Change{
+			Action: Create,
+			Kind:   "market",
+			ID:     m.ID(),
+			apply: func(ctx context.Context) error {
+				_, err := r.client.CreateMarketContext(ctx, peatio.CreateMarketParams{
+					BaseCurrency:    m.Base,
+					QuoteCurrency:   m.Quote,
+					State:           m.State,
+					EngineName:      m.Engine,
+					AmountPrecision: m.AmountPrecision,
+					PricePrecision:  m.PricePrecision,
+					MinPrice:        m.MinPrice,
+					MaxPrice:        m.MaxPrice,
+					MinAmount:       m.MinAmount,
+					Position:        m.Position,
+				})
+				return err
+			},
+		})
+		return nil
+	}
+
+	d := diffs{}
+	d.int("amount_precision", int64(current.AmountPrecision), m.AmountPrecision)
+	d.int("price_precision", int64(current.PricePrecision), m.PricePrecision)
+	for _, f := range []struct {
+		name    string
+		current peatio.Decimal
+		desired string
+	}{
+		{"min_price", current.MinPrice, m.MinPrice},
+		{"max_price", current.MaxPrice, m.MaxPrice},
+		{"min_amount", current.MinAmount, m.MinAmount},
+	} {
+		if err := d.decimal(f.name, f.current, f.desired); err != nil {
+			return fmt.Errorf("market %s: %w", m.ID(), err)
+		}
+	}
+
+	updateEngine := false
+	if m.Engine != "" {
+		if e, ok := s.engines[m.Engine]; !ok || e.ID != current.EngineID {
+			updateEngine = true
+			d.add("engine", engineName(s, current.EngineID), m.Engine)
+		}
+	}
+	if d.empty() {
+		return nil
+	}
+
+	plan.Changes = append(plan.Changes, Change{
+		Action: Update,
+		Kind:   "market",
+		ID:     m.ID(),
+		Diffs:  d.list,
+		apply: func(ctx context.Context) error {
+			params := peatio.UpdateMarketParams{
+				ID:              m.ID(),
+				MinPrice:        m.MinPrice,
+				MaxPrice:        m.MaxPrice,
+				MinAmount:       m.MinAmount,
+				AmountPrecision: m.AmountPrecision,
+				PricePrecision:  m.PricePrecision,
+			}
+
+			if updateEngine {
+				// The engine may be created by the same plan, its id is only known now
+				engines, err := r.client.GetEnginesContext(ctx, peatio.GetEngineParams{Name: m.Engine})
+				if err != nil {
+					return err
+				}
+				for _, e := range engines {
+					if e.Name == m.Engine {
+						params.EngineID = strconv.Itoa(e.ID)
+					}
+				}
+				if params.EngineID == "" {
+					return fmt.Errorf("engine %s not found", m.Engine)
+				}
+			}
+
+			_, err := r.client.UpdateMarketContext(ctx, params)
+			return err
+		},
+	})
+	return nil
+}
+
+func (r *Reconciler) planWallet(plan *Plan, s *state, w Wallet) error {
+	current, ok := s.wallets[w.Name]
+	if !ok {
+		plan.Changes = append(plan.Changes, Change{
+			Action: Create,
+			Kind:   "wallet",
+			ID:     w.Name,
+			apply: func(ctx context.Context) error {
+				_, err := r.client.CreateWalletContext(ctx, peatio.CreateWalletParams{
+					BlockchainKey: w.BlockchainKey,
+					Name:          w.Name,
+					Kind:          w.Kind,
+					Gateway:       w.Gateway,
+					Address:       w.Address,
+					Currencies:    w.Currencies,
+					Settings:      peatio.Settings{URI: w.URI, Secret: w.Secret},
+					MaxBalance:    w.MaxBalance,
+					Status:        w.Status,
+				})
+				return err
+			},
+		})
+		return nil
+	}
+
+	d := diffs{}
+	d.string("kind", current.Kind, w.Kind)
+	d.string("gateway", current.Gateway, w.Gateway)
+	d.string("address", current.Address, w.Address)
+	d.string("blockchain_key", current.BlockchainKey, w.BlockchainKey)
+	d.string("status", current.Status, w.Status)
+	if w.Currencies != nil {
+		d.string("currencies", sortedList(current.Currencies), sortedList(w.Currencies))
+	}
+	if err := d.decimal("max_balance", current.MaxBalance, w.MaxBalance); err != nil {
+		return fmt.Errorf("wallet %s: %w", w.Name, err)
+	}
+	if d.empty() {
+		return nil
+	}
+
+	id := strconv.Itoa(current.ID)
+	plan.Changes = append(plan.Changes, Change{
+		Action: Update,
+		Kind:   "wallet",
+		ID:     w.Name,
+		Diffs:  d.list,
+		apply: func(ctx context.Context) error {
+			_, err := r.client.UpdateWalletContext(ctx, peatio.UpdateWalletParams{
+				ID:            id,
+				BlockchainKey: w.BlockchainKey,
+				Name:          w.Name,
+				Address:       w.Address,
+				Gateway:       w.Gateway,
+				Kind:          w.Kind,
+				Currencies:    w.Currencies,
+				MaxBalance:    w.MaxBalance,
+				Status:        w.Status,
+			})
+			return err
+		},
+	})
+	return nil
+}
+
+// diffs collects the fields to update, unset desired values are left unchanged
+type diffs struct {
+	list []Diff
+}
+
+func (d *diffs) add(field, from, to string) {
+	d.list = append(d.list, Diff{Field: field, From: from, To: to})
+}
+
+func (d *diffs) empty() bool {
+	return len(d.list) == 0
+}
+
+func (d *diffs) string(field, current, desired string) {
+	if desired != "" && desired != current {
+		d.add(field, current, desired)
+	}
+}
+
+func (d *diffs) int(field string, current, desired int64) {
+	if desired != 0 && desired != current {
+		d.add(field, strconv.FormatInt(current, 10), strconv.FormatInt(desired, 10))
+	}
+}
+
+func (d *diffs) bool(field string, current bool, desired *bool) {
+	if desired != nil && *desired != current {
+		d.add(field, strconv.FormatBool(current), strconv.FormatBool(*desired))
+	}
+}
+
+func (d *diffs) decimal(field string, current peatio.Decimal, desired string) error {
+	if desired == "" {
+		return nil
+	}
+
+	value, err := peatio.NewDecimal(desired)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q", field, desired)
+	}
+
+	if !value.Equal(current.Decimal) {
+		d.add(field, current.String(), value.String())
+	}
+
+	return nil
+}
+
+// engineState returns the state of an engine as listed by the management API
+func engineState(state int) string {
+	if state == 0 {
+		return "offline"
+	}
+
+	return "online"
+}
+
+// engineStateValue returns the state of an engine as set by the management API
+func engineStateValue(state string) int {
+	if state == "online" {
+		return 1
+	}
+
+	return 0
+}
+
+func boolValue(value *bool) bool {
+	return value != nil && *value
+}
+
+func engineName(s *state, id int) string {
+	for name, e := range s.engines {
+		if e.ID == id {
+			return name
+		}
+	}
+
+	return strconv.Itoa(id)
+}
+
+func sortedList(values []string) string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+
+	return strings.Join(sorted, ",")
+}
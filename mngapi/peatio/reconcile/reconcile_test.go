@@ -0,0 +1,220 @@
+package reconcile
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openware/pkg/mngapi"
+	"github.com/openware/pkg/mngapi/mngapitest"
+	"github.com/openware/pkg/mngapi/peatio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newClient(t *testing.T) (*peatio.Client, *mngapitest.Peatio) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	block := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	verifier := mngapi.NewVerifier()
+	verifier.AddRSAKey("applogic", &key.PublicKey)
+	api := mngapitest.NewPeatio(verifier)
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+
+	client, err := peatio.New(srv.URL, "applogic", "RS256", base64.StdEncoding.EncodeToString(block))
+	require.NoError(t, err)
+
+	return client, api
+}
+
+func TestLoadConfig(t *testing.T) {
+	cfg, err := LoadConfig("testdata/exchange.yml")
+	require.NoError(t, err)
+
+	require.Len(t, cfg.Currencies, 2)
+	assert.Equal(t, "eth-mainnet", cfg.Currencies[0].Networks[0].BlockchainKey)
+	assert.Equal(t, "0.001", cfg.Currencies[0].Networks[0].WithdrawFee)
+	assert.Equal(t, "ethusdt", cfg.Markets[0].ID())
+	assert.Equal(t, []string{"eth"}, cfg.Wallets[0].Currencies)
+
+	cfg.Engines = append(cfg.Engines, cfg.Engines[0])
+	assert.EqualError(t, cfg.Validate(), "engine peatio-default-engine is described twice")
+
+	cfg = &Config{Currencies: []Currency{{Code: "eth", Networks: []Network{{}}}}}
+	assert.EqualError(t, cfg.Validate(), "blockchain currency of eth without blockchain key")
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	_, err := LoadConfig("testdata/missing.yml")
+	assert.EqualError(t, err, "stat testdata/missing.yml: no such file or directory")
+}
+
+func TestReconciler(t *testing.T) {
+	ctx := context.Background()
+	client, api := newClient(t)
+	r := New(client)
+
+	cfg, err := LoadConfig("testdata/exchange.yml")
+	require.NoError(t, err)
+
+	t.Run("Create", func(t *testing.T) {
+		plan, err := r.Plan(ctx, cfg)
+		require.NoError(t, err)
+
+		assert.Equal(t, `+ currency eth
++ currency usdt
++ blockchain currency eth/eth-mainnet
++ engine peatio-default-engine
++ market ethusdt
++ wallet Ethereum Deposit Wallet
+
+Plan: 6 to create, 0 to update.
+`, plan.String())
+
+		require.NoError(t, r.Apply(ctx, plan))
+
+		market, err := client.GetMarketByIDContext(ctx, "ethusdt")
+		require.NoError(t, err)
+		assert.Equal(t, 1, market.EngineID)
+		assert.Equal(t, "0.01", market.MinPrice.String())
+
+		currency, err := client.GetCurrencyByCodeContext(ctx, "eth")
+		require.NoError(t, err)
+		require.Len(t, currency.Networks, 1)
+		assert.True(t, currency.Networks[0].WithdrawEnabled)
+	})
+
+	t.Run("Applied configuration plans no change", func(t *testing.T) {
+		plan, err := r.Plan(ctx, cfg)
+		require.NoError(t, err)
+		assert.True(t, plan.Empty())
+		assert.Equal(t, "No changes, the exchange matches the configuration.\n", plan.String())
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		online := 1
+		cfg.Currencies[0].Networks[0].WithdrawFee = "0.002"
+		cfg.Engines = append(cfg.Engines, Engine{Name: "finex", Driver: "finex-spot", State: &online, Key: "key", Secret: "secret"})
+		cfg.Markets[0].Engine = "finex"
+		cfg.Markets[0].MinPrice = "0.10"
+		cfg.Wallets[0].Currencies = []string{"usdt", "eth"}
+
+		plan, err := r.Plan(ctx, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, `~ blockchain currency eth/eth-mainnet
+    withdraw_fee: 0.001 -> 0.002
++ engine finex
+~ market ethusdt
+    min_price: 0.01 -> 0.10
+    engine: peatio-default-engine -> finex
+~ wallet Ethereum Deposit Wallet
+    currencies: eth -> eth,usdt
+
+Plan: 1 to create, 3 to update.
+`, plan.String())
+
+		applied, err := r.Sync(ctx, cfg)
+		require.NoError(t, err)
+		assert.Len(t, applied.Changes, 4)
+
+		market, err := client.GetMarketByIDContext(ctx, "ethusdt")
+		require.NoError(t, err)
+		assert.Equal(t, 2, market.EngineID)
+
+		plan, err = r.Plan(ctx, cfg)
+		require.NoError(t, err)
+		assert.True(t, plan.Empty(), plan.String())
+	})
+
+	t.Run("Engine update keeps the unset fields", func(t *testing.T) {
+		finex := &cfg.Engines[1]
+		finex.URL = "http://finex:8080"
+		finex.Key, finex.Secret = "", ""
+
+		plan, err := r.Plan(ctx, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, `~ engine finex
+    url:  -> http://finex:8080
+
+Plan: 0 to create, 1 to update.
+`, plan.String())
+		require.NoError(t, r.Apply(ctx, plan))
+
+		engines, err := client.GetEnginesContext(ctx, peatio.GetEngineParams{Name: "finex"})
+		require.NoError(t, err)
+		require.Len(t, engines, 1)
+		assert.Equal(t, "finex-spot", engines[0].Driver)
+		assert.Equal(t, "online", engines[0].State)
+
+		key, secret := api.EngineCredentials("finex")
+		assert.Equal(t, "key", key)
+		assert.Equal(t, "secret", secret)
+
+		// Engines without state in the configuration are left as is
+		finex.State = nil
+		plan, err = r.Plan(ctx, cfg)
+		require.NoError(t, err)
+		assert.True(t, plan.Empty(), plan.String())
+	})
+
+	t.Run("Disable deposits and withdrawals", func(t *testing.T) {
+		disabled := false
+		network := &cfg.Currencies[0].Networks[0]
+		network.DepositEnabled, network.WithdrawEnabled = &disabled, &disabled
+
+		plan, err := r.Plan(ctx, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, `~ blockchain currency eth/eth-mainnet
+    deposit_enabled: true -> false
+    withdraw_enabled: true -> false
+
+Plan: 0 to create, 1 to update.
+`, plan.String())
+		require.NoError(t, r.Apply(ctx, plan))
+
+		currency, err := client.GetCurrencyByCodeContext(ctx, "eth")
+		require.NoError(t, err)
+		assert.False(t, currency.Networks[0].DepositEnabled)
+		assert.False(t, currency.Networks[0].WithdrawEnabled)
+
+		// Flags not set in the configuration are left as is
+		network.DepositEnabled, network.WithdrawEnabled = nil, nil
+		plan, err = r.Plan(ctx, cfg)
+		require.NoError(t, err)
+		assert.True(t, plan.Empty(), plan.String())
+	})
+
+	t.Run("Engines are created online", func(t *testing.T) {
+		cfg.Engines = append(cfg.Engines, Engine{Name: "spare", Driver: "peatio"})
+		_, err := r.Sync(ctx, cfg)
+		require.NoError(t, err)
+
+		engines, err := client.GetEnginesContext(ctx, peatio.GetEngineParams{Name: "spare"})
+		require.NoError(t, err)
+		require.Len(t, engines, 1)
+		assert.Equal(t, "online", engines[0].State)
+	})
+
+	t.Run("Unknown references", func(t *testing.T) {
+		_, err := r.Plan(ctx, &Config{Markets: []Market{{Base: "btc", Quote: "usdt"}}})
+		assert.EqualError(t, err, "market btcusdt references unknown currency btc")
+
+		_, err = r.Plan(ctx, &Config{Markets: []Market{{Base: "eth", Quote: "usdt", Engine: "unknown"}}})
+		assert.EqualError(t, err, "market ethusdt references unknown engine unknown")
+
+		_, err = r.Plan(ctx, &Config{Wallets: []Wallet{{Name: "Hot", Currencies: []string{"trx"}}}})
+		assert.EqualError(t, err, "wallet Hot references unknown currency trx")
+	})
+
+	t.Run("Invalid decimal", func(t *testing.T) {
+		_, err := r.Plan(ctx, &Config{Markets: []Market{{Base: "eth", Quote: "usdt", MinPrice: "cheap"}}})
+		assert.EqualError(t, err, `market ethusdt: invalid min_price "cheap"`)
+	})
+}
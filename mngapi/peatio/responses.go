@@ -1,19 +1,20 @@
 package peatio
 
 type Withdraw struct {
-	TID            string `json:"tid"`
-	UID            string `json:"uid"`
-	Currency       string `json:"currency"`
-	BlockchainKey  string `json:"blockchain_key"`
-	Note           string `json:"note"`
-	Type           string `json:"type"`
-	Amount         string `json:"amount"`
-	Fee            string `json:"fee"`
-	RID            string `json:"rid"`
-	State          string `json:"state"`
-	CreatedAt      string `json:"created_at"`
-	BlockchainTxID string `json:"blockchain_txid"`
-	TransferType   string `json:"transfer_type"`
+	ID             uint64  `json:"id,omitempty"`
+	TID            string  `json:"tid"`
+	UID            string  `json:"uid"`
+	Currency       string  `json:"currency"`
+	BlockchainKey  string  `json:"blockchain_key"`
+	Note           string  `json:"note"`
+	Type           string  `json:"type"`
+	Amount         Decimal `json:"amount"`
+	Fee            Decimal `json:"fee"`
+	RID            string  `json:"rid"`
+	State          string  `json:"state"`
+	CreatedAt      string  `json:"created_at"`
+	BlockchainTxID string  `json:"blockchain_txid"`
+	TransferType   string  `json:"transfer_type"`
 }
 
 type Currency struct {
@@ -21,7 +22,7 @@ type Currency struct {
 	Name        string               `json:"name"`
 	Description string               `json:"description"`
 	Homepage    string               `json:"homepage"`
-	Price       string               `json:"price"`
+	Price       Decimal              `json:"price"`
 	Status      string               `json:"status"`
 	Type        string               `json:"type"`
 	Precision   uint64               `json:"precision"`
@@ -39,19 +40,19 @@ type BlockchainCurrency struct {
 	Status              string                 `json:"status"`
 	DepositEnabled      bool                   `json:"deposit_enabled"`
 	WithdrawEnabled     bool                   `json:"withdrawal_enabled"`
-	DepositFee          string                 `json:"deposit_fee"`
-	MinDepositAmount    string                 `json:"min_deposit_amount"`
-	WithdrawFee         string                 `json:"withdraw_fee"`
-	MinWithdrawAmount   string                 `json:"min_withdraw_amount"`
+	DepositFee          Decimal                `json:"deposit_fee"`
+	MinDepositAmount    Decimal                `json:"min_deposit_amount"`
+	WithdrawFee         Decimal                `json:"withdraw_fee"`
+	MinWithdrawAmount   Decimal                `json:"min_withdraw_amount"`
 	BaseFactor          uint64                 `json:"base_factor"`
-	MinCollectionAmount string                 `json:"min_collection_amount"`
+	MinCollectionAmount Decimal                `json:"min_collection_amount"`
 	Options             map[string]interface{} `json:"options"`
 }
 
 type Balance struct {
-	UID     string `json:"uid"`
-	Balance string `json:"balance"`
-	Locked  string `json:"locked"`
+	UID     string  `json:"uid"`
+	Balance Decimal `json:"balance"`
+	Locked  Decimal `json:"locked"`
 }
 
 type PaymentAddress struct {
@@ -71,7 +72,7 @@ type Deposit struct {
 	Address                 string  `json:"address"`
 	UID                     string  `json:"uid"`
 	Type                    string  `json:"type"`
-	Amount                  string  `json:"amount"`
+	Amount                  Decimal `json:"amount"`
 	State                   string  `json:"state"`
 	CreatedAt               string  `json:"created_at"`
 	CompletedAt             *string `json:"completed_at"`
@@ -90,20 +91,20 @@ type Engine struct {
 }
 
 type Market struct {
-	ID              string `json:"id"`
-	Name            string `json:"name"`
-	BaseUnit        string `json:"base_unit"`
-	QuoteUnit       string `json:"quote_unit"`
-	MinPrice        string `json:"min_price"`
-	MaxPrice        string `json:"max_price"`
-	MinAmount       string `json:"min_amount"`
-	AmountPrecision int    `json:"amount_precision"`
-	PricePrecision  int    `json:"price_precision"`
-	State           string `json:"state"`
-	Position        int    `json:"position"`
-	EngineID        int    `json:"engine_id"`
-	CreatedAt       string `json:"created_at"`
-	UpdatedAt       string `json:"updated_at"`
+	ID              string  `json:"id"`
+	Name            string  `json:"name"`
+	BaseUnit        string  `json:"base_unit"`
+	QuoteUnit       string  `json:"quote_unit"`
+	MinPrice        Decimal `json:"min_price"`
+	MaxPrice        Decimal `json:"max_price"`
+	MinAmount       Decimal `json:"min_amount"`
+	AmountPrecision int     `json:"amount_precision"`
+	PricePrecision  int     `json:"price_precision"`
+	State           string  `json:"state"`
+	Position        int     `json:"position"`
+	EngineID        int     `json:"engine_id"`
+	CreatedAt       string  `json:"created_at"`
+	UpdatedAt       string  `json:"updated_at"`
 }
 
 type Member struct {
@@ -122,8 +123,74 @@ type Wallet struct {
 	Currencies    []string               `json:"currencies"`
 	Address       string                 `json:"address"`
 	Gateway       string                 `json:"gateway"`
-	MaxBalance    string                 `json:"max_balance"`
+	MaxBalance    Decimal                `json:"max_balance"`
 	Balance       map[string]interface{} `json:"balance"`
 	BlockchainKey string                 `json:"blockchain_key"`
 	Status        string                 `json:"status"`
 }
+
+type Trade struct {
+	ID           int64   `json:"id"`
+	Price        Decimal `json:"price"`
+	Amount       Decimal `json:"amount"`
+	Total        Decimal `json:"total"`
+	Market       string  `json:"market"`
+	MakerOrderID int64   `json:"maker_order_id"`
+	TakerOrderID int64   `json:"taker_order_id"`
+	MakerUID     string  `json:"maker_uid"`
+	TakerUID     string  `json:"taker_uid"`
+	TakerType    string  `json:"taker_type"`
+	CreatedAt    string  `json:"created_at"`
+}
+
+type Order struct {
+	ID              int64   `json:"id"`
+	UUID            string  `json:"uuid"`
+	UID             string  `json:"uid"`
+	Side            string  `json:"side"`
+	OrdType         string  `json:"ord_type"`
+	Price           Decimal `json:"price"`
+	AvgPrice        Decimal `json:"avg_price"`
+	State           string  `json:"state"`
+	Market          string  `json:"market"`
+	OriginVolume    Decimal `json:"origin_volume"`
+	RemainingVolume Decimal `json:"remaining_volume"`
+	ExecutedVolume  Decimal `json:"executed_volume"`
+	TradesCount     int64   `json:"trades_count"`
+	CreatedAt       string  `json:"created_at"`
+	UpdatedAt       string  `json:"updated_at"`
+}
+
+type Beneficiary struct {
+	ID            int64                  `json:"id"`
+	UID           string                 `json:"uid"`
+	Currency      string                 `json:"currency"`
+	BlockchainKey string                 `json:"blockchain_key"`
+	Name          string                 `json:"name"`
+	Description   string                 `json:"description"`
+	Data          map[string]interface{} `json:"data"`
+	State         string                 `json:"state"`
+	CreatedAt     string                 `json:"created_at"`
+}
+
+// OperationType is the kind of accounting operations, see the Operation* constants
+type OperationType string
+
+const (
+	OperationAssets      OperationType = "assets"
+	OperationExpenses    OperationType = "expenses"
+	OperationLiabilities OperationType = "liabilities"
+	OperationRevenues    OperationType = "revenues"
+)
+
+type Operation struct {
+	Code          int64   `json:"code"`
+	Currency      string  `json:"currency"`
+	UID           string  `json:"uid,omitempty"`
+	AccountKind   string  `json:"account_kind,omitempty"`
+	Credit        Decimal `json:"credit"`
+	Debit         Decimal `json:"debit"`
+	RID           string  `json:"rid,omitempty"`
+	ReferenceType string  `json:"reference_type"`
+	CreatedAt     string  `json:"created_at"`
+}
@@ -0,0 +1,64 @@
+package mngapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// EncodeQuery encodes params as a query string following their JSON representation,
+// so that params structs are reused with their json tags and omitempty options.
+// Arrays and objects use the Rails bracket notation expected by the management APIs,
+// e.g. currencies[]=eth&currencies[]=btc and data[address]=0x00.
+func EncodeQuery(params interface{}) (url.Values, error) {
+	values := url.Values{}
+	if params == nil {
+		return values, nil
+	}
+
+	payload, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields interface{}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+
+	switch fields := fields.(type) {
+	case nil:
+		return values, nil
+	case map[string]interface{}:
+		for key, value := range fields {
+			encodeQueryValue(values, key, value)
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("Query params must be an object, got %s", payload)
+	}
+}
+
+func encodeQueryValue(values url.Values, key string, value interface{}) {
+	switch value := value.(type) {
+	case nil:
+	case string:
+		values.Add(key, value)
+	case json.Number:
+		values.Add(key, value.String())
+	case bool:
+		values.Add(key, strconv.FormatBool(value))
+	case []interface{}:
+		for _, item := range value {
+			encodeQueryValue(values, key+"[]", item)
+		}
+	case map[string]interface{}:
+		for k, v := range value {
+			encodeQueryValue(values, key+"["+k+"]", v)
+		}
+	}
+}
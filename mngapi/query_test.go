@@ -0,0 +1,54 @@
+package mngapi
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeQuery(t *testing.T) {
+	t.Run("Params struct", func(t *testing.T) {
+		params := struct {
+			UID      string                 `json:"uid"`
+			State    string                 `json:"state,omitempty"`
+			Page     int64                  `json:"page"`
+			Amount   float64                `json:"amount"`
+			Enabled  bool                   `json:"enabled"`
+			Markets  []string               `json:"markets"`
+			Data     map[string]interface{} `json:"data"`
+			Optional *string                `json:"optional"`
+		}{
+			UID:     "IDCA2AC08296",
+			Page:    2,
+			Amount:  0.000001,
+			Enabled: true,
+			Markets: []string{"ethusdt", "btcusdt"},
+			Data:    map[string]interface{}{"address": "0x00"},
+		}
+
+		query, err := EncodeQuery(params)
+		require.NoError(t, err)
+		assert.Equal(t, url.Values{
+			"uid":           {"IDCA2AC08296"},
+			"page":          {"2"},
+			"amount":        {"0.000001"},
+			"enabled":       {"true"},
+			"markets[]":     {"ethusdt", "btcusdt"},
+			"data[address]": {"0x00"},
+		}, query)
+		assert.Equal(t, "amount=0.000001&data%5Baddress%5D=0x00&enabled=true&markets%5B%5D=ethusdt&markets%5B%5D=btcusdt&page=2&uid=IDCA2AC08296", query.Encode())
+	})
+
+	t.Run("Nil params", func(t *testing.T) {
+		query, err := EncodeQuery(nil)
+		require.NoError(t, err)
+		assert.Empty(t, query)
+	})
+
+	t.Run("Non object params", func(t *testing.T) {
+		_, err := EncodeQuery([]string{"eth"})
+		assert.EqualError(t, err, `Query params must be an object, got ["eth"]`)
+	})
+}
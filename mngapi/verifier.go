@@ -0,0 +1,218 @@
+package mngapi
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	_ "crypto/sha512" // registers SHA-384 and SHA-512 for RSA signatures
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	secpecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	jwtgo "github.com/golang-jwt/jwt"
+)
+
+// JWTClaims of a management API request, or of a peatio event which carries the event claim
+type JWTClaims struct {
+	Data      json.RawMessage `json:"data"`
+	Event     json.RawMessage `json:"event,omitempty"`
+	IssuedAt  int64           `json:"iat"`
+	ExpiresAt int64           `json:"exp"`
+	Issuer    string          `json:"iss"`
+	JTI       string          `json:"jti"`
+}
+
+// VerifyFunc reports whether sig is a valid signature of the signing input
+type VerifyFunc func(signingInput, sig []byte) bool
+
+// verifierKey verifies the signatures of a key by JWS algorithm
+type verifierKey map[string]VerifyFunc
+
+// Verifier verifies JWS JSON envelopes signed by one or more keys, as sent by Client
+type Verifier struct {
+	keys map[string]verifierKey
+	now  func() time.Time
+}
+
+// NewVerifier returns a Verifier without any key
+func NewVerifier() *Verifier {
+	return &Verifier{
+		keys: make(map[string]verifierKey),
+		now:  time.Now,
+	}
+}
+
+// AddKey registers a key id with its JWS algorithm and verification function,
+// for algorithms without a dedicated Add function
+func (v *Verifier) AddKey(kid string, alg string, verify VerifyFunc) {
+	v.keys[kid] = verifierKey{alg: verify}
+}
+
+// AddRSAKey registers an RSA public key, accepting RS256, RS384 and RS512 signatures
+// verified with the hash of the algorithm in their header
+func (v *Verifier) AddRSAKey(kid string, pub *rsa.PublicKey) {
+	v.keys[kid] = verifierKey{
+		"RS256": rsaVerify(pub, crypto.SHA256),
+		"RS384": rsaVerify(pub, crypto.SHA384),
+		"RS512": rsaVerify(pub, crypto.SHA512),
+	}
+}
+
+// AddECDSAKey registers a P-256 public key for ES256 signatures
+func (v *Verifier) AddECDSAKey(kid string, pub *ecdsa.PublicKey) {
+	v.AddKey(kid, "ES256", func(signingInput, sig []byte) bool {
+		if len(sig) != 64 {
+			return false
+		}
+		digest := sha256.Sum256(signingInput)
+		r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+		return ecdsa.Verify(pub, digest[:], r, s)
+	})
+}
+
+// AddSecp256k1Key registers a secp256k1 public key for ES256K signatures,
+// e.g. the public key of a signer.SignerInterface used with NewDigestSigner
+func (v *Verifier) AddSecp256k1Key(kid string, pub *ecdsa.PublicKey) {
+	var key *secp256k1.PublicKey
+	if pub != nil && pub.X != nil && pub.Y != nil {
+		var x, y secp256k1.FieldVal
+		if !x.SetByteSlice(pub.X.Bytes()) && !y.SetByteSlice(pub.Y.Bytes()) {
+			key = secp256k1.NewPublicKey(&x, &y)
+		}
+	}
+
+	v.AddKey(kid, "ES256K", func(signingInput, sig []byte) bool {
+		if key == nil || !key.IsOnCurve() || len(sig) != 64 {
+			return false
+		}
+		var r, s secp256k1.ModNScalar
+		if r.SetByteSlice(sig[:32]) || s.SetByteSlice(sig[32:]) {
+			return false
+		}
+		digest := sha256.Sum256(signingInput)
+		return secpecdsa.NewSignature(&r, &s).Verify(digest[:], key)
+	})
+}
+
+// AddEd25519Key registers an Ed25519 public key for EdDSA signatures
+func (v *Verifier) AddEd25519Key(kid string, pub ed25519.PublicKey) {
+	v.AddKey(kid, "EdDSA", func(signingInput, sig []byte) bool {
+		// ed25519.Verify panics on keys of another length
+		if len(pub) != ed25519.PublicKeySize {
+			return false
+		}
+		return ed25519.Verify(pub, signingInput, sig)
+	})
+}
+
+// Verify checks every signature of the envelope and its expiration, and returns the claims
+// with the key ids of the signers. Every key id in required must have signed the envelope,
+// and tokens without expiration are rejected.
+func (v *Verifier) Verify(body []byte, required ...string) (*JWTClaims, []string, error) {
+	var envelope struct {
+		Payload    string `json:"payload"`
+		Signatures []struct {
+			Protected string            `json:"protected"`
+			Header    map[string]string `json:"header"`
+			Signature string            `json:"signature"`
+		} `json:"signatures"`
+	}
+
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, nil, fmt.Errorf("Invalid JWS envelope: %w", err)
+	}
+
+	if len(envelope.Signatures) == 0 {
+		return nil, nil, fmt.Errorf("JWS envelope is not signed")
+	}
+
+	kids := make([]string, 0, len(envelope.Signatures))
+	signed := make(map[string]bool, len(envelope.Signatures))
+	for _, s := range envelope.Signatures {
+		kid := s.Header["kid"]
+		key, ok := v.keys[kid]
+		if !ok {
+			return nil, nil, fmt.Errorf("Unknown JWT signer %q", kid)
+		}
+		if signed[kid] {
+			return nil, nil, fmt.Errorf("Duplicate JWT signer %s", kid)
+		}
+
+		alg, err := headerAlgorithm(s.Protected)
+		if err != nil {
+			return nil, nil, err
+		}
+		verify, ok := key[alg]
+		if !ok {
+			return nil, nil, fmt.Errorf("Unexpected signing method %s for %s", alg, kid)
+		}
+
+		sig, err := jwtgo.DecodeSegment(s.Signature)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Invalid signature of %s: %w", kid, err)
+		}
+
+		if !verify([]byte(s.Protected+"."+envelope.Payload), sig) {
+			return nil, nil, fmt.Errorf("Signature verification failed for %s", kid)
+		}
+
+		signed[kid] = true
+		kids = append(kids, kid)
+	}
+
+	for _, kid := range required {
+		if !signed[kid] {
+			return nil, nil, fmt.Errorf("Missing JWT signature of %s", kid)
+		}
+	}
+
+	payload, err := jwtgo.DecodeSegment(envelope.Payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid JWT payload: %w", err)
+	}
+
+	claims := &JWTClaims{}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return nil, nil, fmt.Errorf("Invalid JWT payload: %w", err)
+	}
+
+	if claims.ExpiresAt == 0 {
+		return nil, nil, fmt.Errorf("JWT has no expiration")
+	}
+	if v.now().Unix() > claims.ExpiresAt {
+		return nil, nil, fmt.Errorf("JWT is expired")
+	}
+
+	return claims, kids, nil
+}
+
+// headerAlgorithm returns the alg of a base64url encoded protected header
+func headerAlgorithm(protected string) (string, error) {
+	raw, err := jwtgo.DecodeSegment(protected)
+	if err != nil {
+		return "", fmt.Errorf("Invalid protected header: %w", err)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return "", fmt.Errorf("Invalid protected header: %w", err)
+	}
+
+	return header.Alg, nil
+}
+
+// rsaVerify returns the verification function of PKCS #1 v1.5 signatures with the given hash
+func rsaVerify(pub *rsa.PublicKey, hash crypto.Hash) VerifyFunc {
+	return func(signingInput, sig []byte) bool {
+		h := hash.New()
+		h.Write(signingInput)
+		return rsa.VerifyPKCS1v15(pub, hash, h.Sum(nil), sig) == nil
+	}
+}
@@ -0,0 +1,101 @@
+package signer
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+	"math/big"
+)
+
+// Algorithm of a signing key, named after the matching JWS algorithm
+type Algorithm string
+
+const (
+	// AlgorithmSecp256k1 signatures are R || S || V, V being 27 or 28
+	AlgorithmSecp256k1 Algorithm = "ES256K"
+	// AlgorithmP256 signatures are R || S, as used by JWS
+	AlgorithmP256 Algorithm = "ES256"
+	// AlgorithmEd25519 signatures are 64 bytes long
+	AlgorithmEd25519 Algorithm = "EdDSA"
+)
+
+// parsePublicKey parses an ASN.1 DER Subject Public Key Info and detects the key algorithm
+func parsePublicKey(der []byte) (Algorithm, crypto.PublicKey, error) {
+	// x509 does not support secp256k1 keys, try them first
+	if pk, err := pemToPubkey(der); err == nil {
+		return AlgorithmSecp256k1, pk, nil
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return "", nil, fmt.Errorf("error unmarshaling public key: %w", err)
+	}
+
+	switch pk := pub.(type) {
+	case *ecdsa.PublicKey:
+		if pk.Curve != elliptic.P256() {
+			return "", nil, fmt.Errorf("unsupported curve: %s", pk.Curve.Params().Name)
+		}
+		return AlgorithmP256, pk, nil
+	case ed25519.PublicKey:
+		return AlgorithmEd25519, pk, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported public key type: %T", pub)
+	}
+}
+
+// encodeP256 converts a KMS P-256 signature to R || S and verifies digest
+func encodeP256(digest, signature []byte, pubKey *ecdsa.PublicKey) ([]byte, error) {
+	r, s, err := parseRS(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ecdsa.Verify(pubKey, digest, r, s) {
+		return nil, fmt.Errorf("signer: signature verification failed")
+	}
+
+	sign := make([]byte, 64)
+	r.FillBytes(sign[:32])
+	s.FillBytes(sign[32:])
+	return sign, nil
+}
+
+// VerifyMessage checks that the given public key created signature over message,
+// the message being hashed with SHA-256 for ECDSA algorithms
+func VerifyMessage(alg Algorithm, pub crypto.PublicKey, message, sig []byte) bool {
+	switch alg {
+	case AlgorithmSecp256k1:
+		pk, ok := pub.(*ecdsa.PublicKey)
+		if !ok {
+			return false
+		}
+		digest := sha256.Sum256(message)
+		sigCopy := make([]byte, len(sig))
+		copy(sigCopy, sig)
+		return verifyDigest(*pk, digest[:], sigCopy)
+
+	case AlgorithmP256:
+		pk, ok := pub.(*ecdsa.PublicKey)
+		if !ok || len(sig) != 64 {
+			return false
+		}
+		digest := sha256.Sum256(message)
+		r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+		return ecdsa.Verify(pk, digest[:], r, s)
+
+	case AlgorithmEd25519:
+		pk, ok := pub.(ed25519.PublicKey)
+		if !ok || len(sig) != ed25519.SignatureSize {
+			return false
+		}
+		return ed25519.Verify(pk, message, sig)
+
+	default:
+		return false
+	}
+}
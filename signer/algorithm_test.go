@@ -0,0 +1,132 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func secp256k1SPKI(t *testing.T, pub *ecdsa.PublicKey) []byte {
+	params, err := asn1.Marshal(oidNamedCurveSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := asn1.Marshal(publicKeyInfo{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  oidPublicKeyECDSA,
+			Parameters: asn1.RawValue{FullBytes: params},
+		},
+		PublicKey: asn1.BitString{Bytes: crypto.FromECDSAPub(pub), BitLength: 8 * 65},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return der
+}
+
+func TestParsePublicKey(t *testing.T) {
+	secp := NewDeterministicSigner("secp256k1")
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	edPub, _, _ := ed25519.GenerateKey(rand.Reader)
+
+	p256DER, err := x509.MarshalPKIXPublicKey(&p256.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edDER, err := x509.MarshalPKIXPublicKey(edPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[Algorithm][]byte{
+		AlgorithmSecp256k1: secp256k1SPKI(t, &secp.key.PublicKey),
+		AlgorithmP256:      p256DER,
+		AlgorithmEd25519:   edDER,
+	}
+
+	for expected, der := range tests {
+		alg, pub, err := parsePublicKey(der)
+		if err != nil {
+			t.Fatalf("%s: %s", expected, err)
+		}
+		if alg != expected {
+			t.Fatalf("algorithm mismatch: got %s, want %s", alg, expected)
+		}
+		if pub == nil {
+			t.Fatalf("%s: public key is nil", expected)
+		}
+	}
+
+	if _, _, err := parsePublicKey([]byte("garbage")); err == nil {
+		t.Fatal("expected error with invalid public key")
+	}
+}
+
+func TestEncodeP256(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	message := []byte("bonjour")
+	digest := sha256.Sum256(message)
+
+	der, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig, err := encodeP256(digest[:], der, &key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sig) != 64 {
+		t.Fatalf("unexpected signature length: %d", len(sig))
+	}
+	if !VerifyMessage(AlgorithmP256, &key.PublicKey, message, sig) {
+		t.Fatal("P-256 signature verification failed")
+	}
+	if VerifyMessage(AlgorithmP256, &key.PublicKey, []byte("hello"), sig) {
+		t.Fatal("P-256 signature verified for another message")
+	}
+
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if _, err := encodeP256(digest[:], der, &other.PublicKey); err == nil {
+		t.Fatal("expected error with another public key")
+	}
+}
+
+func TestVerifyMessage(t *testing.T) {
+	message := []byte("bonjour")
+
+	t.Run("secp256k1", func(t *testing.T) {
+		s := NewDeterministicSigner("opendax")
+		sig, err := s.SignMessage(message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !VerifyMessage(s.Algorithm(), s.Public(), message, sig) {
+			t.Fatal("secp256k1 signature verification failed")
+		}
+		if VerifyMessage(AlgorithmEd25519, s.Public(), message, sig) {
+			t.Fatal("secp256k1 signature verified as Ed25519")
+		}
+	})
+
+	t.Run("Ed25519", func(t *testing.T) {
+		pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+		sig := ed25519.Sign(priv, message)
+		if !VerifyMessage(AlgorithmEd25519, pub, message, sig) {
+			t.Fatal("Ed25519 signature verification failed")
+		}
+		if VerifyMessage(AlgorithmEd25519, pub, []byte("hello"), sig) {
+			t.Fatal("Ed25519 signature verified for another message")
+		}
+	})
+}
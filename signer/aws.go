@@ -1,20 +1,30 @@
 package signer
 
 import (
+	"context"
+	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/kms"
 )
 
+// awsSigningAlgorithmEd25519 is not yet defined by the vendored AWS SDK
+const awsSigningAlgorithmEd25519 = "ED25519_SHA_512"
+
 type AWSSigner struct {
 	client    *kms.KMS
 	keyARN    string
+	algorithm Algorithm
+	public    crypto.PublicKey
 	publicKey ecdsa.PublicKey
 }
 
-// NewAWSSigner creates a new AWS signer with the provided signing key
+// NewAWSSigner creates a new AWS signer with the provided signing key,
+// supported key specs are ECC_SECG_P256K1, ECC_NIST_P256 and Ed25519
 func NewAWSSigner(client *kms.KMS, keyARN string) (*AWSSigner, error) {
 	// Get public key from KMS
 	key, err := client.GetPublicKey(&kms.GetPublicKeyInput{KeyId: &keyARN})
@@ -22,36 +32,105 @@ func NewAWSSigner(client *kms.KMS, keyARN string) (*AWSSigner, error) {
 		return nil, fmt.Errorf("signer: unable to get public key: %w", err)
 	}
 
-	// Convert to ecdsa.PublicKey
-	pk, err := pemToPubkey(key.PublicKey)
+	alg, pub, err := parsePublicKey(key.PublicKey)
 	if err != nil {
 		return nil, fmt.Errorf("signer: failed to decode public key: %w", err)
 	}
 
-	return &AWSSigner{
+	s := &AWSSigner{
 		client:    client,
 		keyARN:    keyARN,
-		publicKey: *pk,
-	}, nil
+		algorithm: alg,
+		public:    pub,
+	}
+	if pk, ok := pub.(*ecdsa.PublicKey); ok {
+		s.publicKey = *pk
+	}
+
+	return s, nil
+}
+
+// Algorithm returns the algorithm of the KMS key
+func (c *AWSSigner) Algorithm() Algorithm {
+	return c.algorithm
+}
+
+// Public returns the public key of the KMS key
+func (c *AWSSigner) Public() crypto.PublicKey {
+	return c.public
 }
 
-// GetPublicKey returns a public key
+// GetPublicKey returns a public key, empty for Ed25519 keys
 func (c *AWSSigner) GetPublicKey() ecdsa.PublicKey {
 	return c.publicKey
 }
 
-// Sign the given digest using a KMS key and return ECDSA signature
+// Sign the given digest using a secp256k1 KMS key and return ECDSA signature
 func (c *AWSSigner) Sign(digest []byte) (SignatureECDSA, error) {
+	return c.SignContext(context.Background(), digest)
+}
+
+// SignContext signs the given digest like Sign, the KMS call is cancelled with the context
+func (c *AWSSigner) SignContext(ctx context.Context, digest []byte) (SignatureECDSA, error) {
+	if c.algorithm != AlgorithmSecp256k1 {
+		return nil, fmt.Errorf("signer: digest signing is not supported for %s keys, use SignMessage", c.algorithm)
+	}
+
+	res, err := c.signDigest(ctx, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return recoverAndVerify(digest, res.Signature, c.publicKey)
+}
+
+// SignMessage signs the message using the KMS key
+func (c *AWSSigner) SignMessage(message []byte) ([]byte, error) {
+	return c.SignMessageContext(context.Background(), message)
+}
+
+// SignMessageContext signs the message like SignMessage, the KMS call is cancelled with the context
+func (c *AWSSigner) SignMessageContext(ctx context.Context, message []byte) ([]byte, error) {
+	switch c.algorithm {
+	case AlgorithmSecp256k1:
+		digest := sha256.Sum256(message)
+		return c.SignContext(ctx, digest[:])
+
+	case AlgorithmP256:
+		digest := sha256.Sum256(message)
+		res, err := c.signDigest(ctx, digest[:])
+		if err != nil {
+			return nil, err
+		}
+		return encodeP256(digest[:], res.Signature, &c.publicKey)
+
+	case AlgorithmEd25519:
+		// Call the API
+		res, err := c.client.SignWithContext(ctx, &kms.SignInput{
+			KeyId:            aws.String(c.keyARN),
+			Message:          message,
+			MessageType:      aws.String(kms.MessageTypeRaw),
+			SigningAlgorithm: aws.String(awsSigningAlgorithmEd25519),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !ed25519.Verify(c.public.(ed25519.PublicKey), message, res.Signature) {
+			return nil, fmt.Errorf("signer: signature verification failed")
+		}
+		return res.Signature, nil
+
+	default:
+		return nil, fmt.Errorf("signer: unsupported algorithm %s", c.algorithm)
+	}
+}
+
+func (c *AWSSigner) signDigest(ctx context.Context, digest []byte) (*kms.SignOutput, error) {
 	// Call the API
-	res, err := c.client.Sign(&kms.SignInput{
+	return c.client.SignWithContext(ctx, &kms.SignInput{
 		KeyId:            aws.String(c.keyARN),
 		Message:          digest,
 		MessageType:      aws.String(kms.MessageTypeDigest),
 		SigningAlgorithm: aws.String(kms.SigningAlgorithmSpecEcdsaSha256),
 	})
-	if err != nil {
-		return nil, err
-	}
-
-	return recoverAndVerify(digest, res.Signature, c.publicKey)
 }
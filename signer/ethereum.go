@@ -0,0 +1,98 @@
+package signer
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Address returns the Ethereum address of the signer public key,
+// only secp256k1 keys have one, e.g. not the P-256 and Ed25519 KMS keys
+func Address(s SignerInterface) (common.Address, error) {
+	pub := s.GetPublicKey()
+	if pub.Curve == crypto.S256() {
+		return crypto.PubkeyToAddress(pub), nil
+	}
+
+	if ks, ok := s.(KeySigner); ok {
+		return common.Address{}, fmt.Errorf("signer: %s keys have no Ethereum address", ks.Algorithm())
+	}
+	return common.Address{}, fmt.Errorf("signer: only secp256k1 keys have an Ethereum address")
+}
+
+// SignTx signs a legacy, EIP-2930 or EIP-1559 transaction for the given chain
+// and returns the signed transaction
+func SignTx(s SignerInterface, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	address, err := Address(s)
+	if err != nil {
+		return nil, err
+	}
+
+	txSigner := types.LatestSignerForChainID(chainID)
+	hash := txSigner.Hash(tx)
+
+	sig, err := s.Sign(hash[:])
+	if err != nil {
+		return nil, err
+	}
+
+	signedTx, err := tx.WithSignature(txSigner, toRecoveryID(sig))
+	if err != nil {
+		return nil, fmt.Errorf("signer: unable to apply signature: %w", err)
+	}
+
+	sender, err := types.Sender(txSigner, signedTx)
+	if err != nil {
+		return nil, fmt.Errorf("signer: unable to recover sender: %w", err)
+	}
+	if sender != address {
+		return nil, fmt.Errorf("signer: recovered sender %s does not match %s", sender, address)
+	}
+
+	return signedTx, nil
+}
+
+// SignTypedData signs an EIP-712 typed data payload, the returned signature
+// is R || S || V with V being 27 or 28 as expected by eth_signTypedData
+func SignTypedData(s SignerInterface, typedData apitypes.TypedData) (SignatureECDSA, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("signer: unable to hash typed data: %w", err)
+	}
+
+	return s.Sign(hash)
+}
+
+// RecoverTypedData returns the address which signed the EIP-712 typed data payload
+func RecoverTypedData(typedData apitypes.TypedData, sig SignatureECDSA) (common.Address, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("signer: unable to hash typed data: %w", err)
+	}
+
+	if len(sig) != 65 {
+		return common.Address{}, fmt.Errorf("signer: invalid signature length: %d", len(sig))
+	}
+
+	pubKey, err := crypto.SigToPub(hash, toRecoveryID(sig))
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
+
+// toRecoveryID returns a copy of the signature with V transformed from Ethereum-legacy 27/28 to 0/1
+func toRecoveryID(sig SignatureECDSA) []byte {
+	res := make([]byte, len(sig))
+	copy(res, sig)
+	if len(res) == 65 && (res[64] == 27 || res[64] == 28) {
+		res[64] -= 27
+	}
+
+	return res
+}
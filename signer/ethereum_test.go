@@ -0,0 +1,140 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+func newKeySigner(t *testing.T) *LocalSigner {
+	key, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewLocalSigner(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+// address returns the Ethereum address of a secp256k1 signer
+func address(t *testing.T, s SignerInterface) common.Address {
+	t.Helper()
+	addr, err := Address(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return addr
+}
+
+func TestAddress(t *testing.T) {
+	s := newKeySigner(t)
+
+	if got, want := address(t, s), crypto.PubkeyToAddress(s.key.PublicKey); got != want {
+		t.Fatalf("address mismatch: got %s, want %s", got, want)
+	}
+
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Address(&AWSSigner{algorithm: AlgorithmP256, publicKey: p256.PublicKey}); err == nil || err.Error() != "signer: ES256 keys have no Ethereum address" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := Address(&GCPSigner{algorithm: AlgorithmEd25519}); err == nil || err.Error() != "signer: EdDSA keys have no Ethereum address" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSignTx(t *testing.T) {
+	s := newKeySigner(t)
+	chainID := big.NewInt(1337)
+	to := common.HexToAddress("0x8ba1f109551bD432803012645Ac136ddd64DBA72")
+
+	txs := map[string]*types.Transaction{
+		"legacy": types.NewTx(&types.LegacyTx{
+			Nonce: 1, GasPrice: big.NewInt(1e9), Gas: 21000, To: &to, Value: big.NewInt(1),
+		}),
+		"eip-2930": types.NewTx(&types.AccessListTx{
+			ChainID: chainID, Nonce: 2, GasPrice: big.NewInt(1e9), Gas: 21000, To: &to, Value: big.NewInt(1),
+			AccessList: types.AccessList{{Address: to, StorageKeys: []common.Hash{{0x01}}}},
+		}),
+		"eip-1559": types.NewTx(&types.DynamicFeeTx{
+			ChainID: chainID, Nonce: 3, GasTipCap: big.NewInt(1e9), GasFeeCap: big.NewInt(2e9), Gas: 21000, To: &to, Value: big.NewInt(1),
+		}),
+	}
+
+	for name, tx := range txs {
+		t.Run(name, func(t *testing.T) {
+			signed, err := SignTx(s, tx, chainID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sender != address(t, s) {
+				t.Fatalf("sender mismatch: got %s, want %s", sender, address(t, s))
+			}
+			if signed.Type() != tx.Type() {
+				t.Fatalf("type mismatch: got %d, want %d", signed.Type(), tx.Type())
+			}
+		})
+	}
+}
+
+func TestSignTypedData(t *testing.T) {
+	s := newKeySigner(t)
+
+	typedData := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+			},
+			"Withdraw": {
+				{Name: "to", Type: "address"},
+				{Name: "amount", Type: "uint256"},
+			},
+		},
+		PrimaryType: "Withdraw",
+		Domain: apitypes.TypedDataDomain{
+			Name:    "opendax",
+			ChainId: math.NewHexOrDecimal256(1337),
+		},
+		Message: apitypes.TypedDataMessage{
+			"to":     "0x8ba1f109551bD432803012645Ac136ddd64DBA72",
+			"amount": "1000",
+		},
+	}
+
+	sig, err := SignTypedData(s, typedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig[64] != 27 && sig[64] != 28 {
+		t.Fatalf("unexpected V: %d", sig[64])
+	}
+
+	addr, err := RecoverTypedData(typedData, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr != address(t, s) {
+		t.Fatalf("address mismatch: got %s, want %s", addr, address(t, s))
+	}
+}
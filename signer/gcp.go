@@ -2,7 +2,10 @@ package signer
 
 import (
 	"context"
+	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
 	"encoding/pem"
 	"fmt"
 
@@ -15,10 +18,13 @@ import (
 type GCPSigner struct {
 	client    *api.KeyManagementClient
 	keyPath   string
+	algorithm Algorithm
+	public    crypto.PublicKey
 	publicKey ecdsa.PublicKey
 }
 
-// NewGCPSigner creates a new GCP signer with the provided signing key
+// NewGCPSigner creates a new GCP signer with the provided signing key,
+// supported algorithms are secp256k1, P-256 and Ed25519
 func NewGCPSigner(client *api.KeyManagementClient, keyPath string) (*GCPSigner, error) {
 	// Get public key from KMS
 	key, err := client.GetPublicKey(context.Background(), &kms.GetPublicKeyRequest{
@@ -28,26 +34,104 @@ func NewGCPSigner(client *api.KeyManagementClient, keyPath string) (*GCPSigner,
 		return nil, fmt.Errorf("signer: unable to get public key: %w", err)
 	}
 
-	// Convert to ecdsa.PublicKey
-	pem, _ := pem.Decode([]byte(key.Pem))
-	pk, err := pemToPubkey(pem.Bytes)
+	block, _ := pem.Decode([]byte(key.Pem))
+	if block == nil {
+		return nil, fmt.Errorf("signer: failed to decode public key: invalid PEM")
+	}
+
+	alg, pub, err := parsePublicKey(block.Bytes)
 	if err != nil {
 		return nil, fmt.Errorf("signer: failed to decode public key: %w", err)
 	}
-	return &GCPSigner{
+
+	s := &GCPSigner{
 		client:    client,
 		keyPath:   keyPath,
-		publicKey: *pk,
-	}, nil
+		algorithm: alg,
+		public:    pub,
+	}
+	if pk, ok := pub.(*ecdsa.PublicKey); ok {
+		s.publicKey = *pk
+	}
+
+	return s, nil
+}
+
+// Algorithm returns the algorithm of the KMS key
+func (c *GCPSigner) Algorithm() Algorithm {
+	return c.algorithm
 }
 
-// GetPublicKey returns a public key
+// Public returns the public key of the KMS key
+func (c *GCPSigner) Public() crypto.PublicKey {
+	return c.public
+}
+
+// GetPublicKey returns a public key, empty for Ed25519 keys
 func (c *GCPSigner) GetPublicKey() ecdsa.PublicKey {
 	return c.publicKey
 }
 
-// Sign the given digest using a KMS key and return ECDSA signature
+// Sign the given digest using a secp256k1 KMS key and return ECDSA signature
 func (c *GCPSigner) Sign(digest []byte) (SignatureECDSA, error) {
+	return c.SignContext(context.Background(), digest)
+}
+
+// SignContext signs the given digest like Sign, the KMS call is cancelled with the context
+func (c *GCPSigner) SignContext(ctx context.Context, digest []byte) (SignatureECDSA, error) {
+	if c.algorithm != AlgorithmSecp256k1 {
+		return nil, fmt.Errorf("signer: digest signing is not supported for %s keys, use SignMessage", c.algorithm)
+	}
+
+	signature, err := c.signDigest(ctx, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return recoverAndVerify(digest, signature, c.publicKey)
+}
+
+// SignMessage signs the message using the KMS key
+func (c *GCPSigner) SignMessage(message []byte) ([]byte, error) {
+	return c.SignMessageContext(context.Background(), message)
+}
+
+// SignMessageContext signs the message like SignMessage, the KMS call is cancelled with the context
+func (c *GCPSigner) SignMessageContext(ctx context.Context, message []byte) ([]byte, error) {
+	switch c.algorithm {
+	case AlgorithmSecp256k1:
+		digest := sha256.Sum256(message)
+		return c.SignContext(ctx, digest[:])
+
+	case AlgorithmP256:
+		digest := sha256.Sum256(message)
+		signature, err := c.signDigest(ctx, digest[:])
+		if err != nil {
+			return nil, err
+		}
+		return encodeP256(digest[:], signature, &c.publicKey)
+
+	case AlgorithmEd25519:
+		// Ed25519 keys sign the message itself
+		signature, err := c.asymmetricSign(ctx, &kms.AsymmetricSignRequest{
+			Name:       c.keyPath,
+			Data:       message,
+			DataCrc32C: wrapperspb.Int64(crc32c(message)),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !ed25519.Verify(c.public.(ed25519.PublicKey), message, signature) {
+			return nil, fmt.Errorf("signer: signature verification failed")
+		}
+		return signature, nil
+
+	default:
+		return nil, fmt.Errorf("signer: unsupported algorithm %s", c.algorithm)
+	}
+}
+
+func (c *GCPSigner) signDigest(ctx context.Context, digest []byte) ([]byte, error) {
 	req := &kms.AsymmetricSignRequest{
 		Name: c.keyPath,
 		Digest: &kms.Digest{
@@ -58,12 +142,19 @@ func (c *GCPSigner) Sign(digest []byte) (SignatureECDSA, error) {
 		DigestCrc32C: wrapperspb.Int64(crc32c(digest)),
 	}
 
+	return c.asymmetricSign(ctx, req)
+}
+
+func (c *GCPSigner) asymmetricSign(ctx context.Context, req *kms.AsymmetricSignRequest) ([]byte, error) {
 	// Call the API
-	res, err := c.client.AsymmetricSign(context.Background(), req)
+	res, err := c.client.AsymmetricSign(ctx, req)
 	if err != nil {
 		return nil, err
 	}
-	if !res.VerifiedDigestCrc32C {
+	if req.Digest != nil && !res.VerifiedDigestCrc32C {
+		return nil, fmt.Errorf("signer: request corrupted in-transit")
+	}
+	if req.Data != nil && !res.VerifiedDataCrc32C {
 		return nil, fmt.Errorf("signer: request corrupted in-transit")
 	}
 
@@ -71,5 +162,5 @@ func (c *GCPSigner) Sign(digest []byte) (SignatureECDSA, error) {
 		return nil, fmt.Errorf("signer: response corrupted in-transit")
 	}
 
-	return recoverAndVerify(digest, res.Signature, c.publicKey)
+	return res.Signature, nil
 }
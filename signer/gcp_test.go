@@ -0,0 +1,102 @@
+package signer
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"testing"
+
+	api "cloud.google.com/go/kms/apiv1"
+	"google.golang.org/api/option"
+	"google.golang.org/genproto/googleapis/cloud/kms/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// fakeKMS signs with an Ed25519 key, verifying the CRC32C of the data unless corrupt is set
+type fakeKMS struct {
+	kms.UnimplementedKeyManagementServiceServer
+	key     ed25519.PrivateKey
+	corrupt bool
+}
+
+func (f *fakeKMS) GetPublicKey(ctx context.Context, req *kms.GetPublicKeyRequest) (*kms.PublicKey, error) {
+	der, err := x509.MarshalPKIXPublicKey(f.key.Public())
+	if err != nil {
+		return nil, err
+	}
+	return &kms.PublicKey{Pem: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))}, nil
+}
+
+func (f *fakeKMS) AsymmetricSign(ctx context.Context, req *kms.AsymmetricSignRequest) (*kms.AsymmetricSignResponse, error) {
+	signature := ed25519.Sign(f.key, req.Data)
+	return &kms.AsymmetricSignResponse{
+		Signature:          signature,
+		SignatureCrc32C:    wrapperspb.Int64(crc32c(signature)),
+		VerifiedDataCrc32C: !f.corrupt && req.DataCrc32C != nil && req.DataCrc32C.Value == crc32c(req.Data),
+	}, nil
+}
+
+func newFakeGCPSigner(t *testing.T, fake *fakeKMS) *GCPSigner {
+	listener := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	kms.RegisterKeyManagementServiceServer(srv, fake)
+	go srv.Serve(listener)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := api.NewKeyManagementClient(context.Background(), option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	s, err := NewGCPSigner(client, "projects/opendax/locations/global/keyRings/finex/cryptoKeys/ed25519/cryptoKeyVersions/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestGCPSignerEd25519(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	message := []byte("bonjour")
+
+	t.Run("Sign the data", func(t *testing.T) {
+		s := newFakeGCPSigner(t, &fakeKMS{key: key})
+		if s.Algorithm() != AlgorithmEd25519 {
+			t.Fatalf("unexpected algorithm %s", s.Algorithm())
+		}
+
+		sig, err := s.SignMessageContext(context.Background(), message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !VerifyMessage(AlgorithmEd25519, s.Public(), message, sig) {
+			t.Fatal("Ed25519 signature verification failed")
+		}
+	})
+
+	t.Run("Data CRC32C not verified", func(t *testing.T) {
+		s := newFakeGCPSigner(t, &fakeKMS{key: key, corrupt: true})
+
+		_, err := s.SignMessage(message)
+		if err == nil || err.Error() != "signer: request corrupted in-transit" {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+}
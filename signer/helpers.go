@@ -34,8 +34,8 @@ func crc32c(data []byte) int64 {
 	return int64(crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli)))
 }
 
-// recoverRS recovers R and S from KMS signature
-func recoverRS(signature []byte) (r *big.Int, s *big.Int, err error) {
+// parseRS parses R and S from an ASN.1 DER KMS signature
+func parseRS(signature []byte) (r *big.Int, s *big.Int, err error) {
 	r, s = &big.Int{}, &big.Int{}
 	var inner cryptobyte.String
 	input := cryptobyte.String(signature)
@@ -46,6 +46,15 @@ func recoverRS(signature []byte) (r *big.Int, s *big.Int, err error) {
 		!inner.Empty() {
 		return nil, nil, errors.New("invalid signature")
 	}
+	return r, s, nil
+}
+
+// recoverRS recovers R and S from KMS secp256k1 signature
+func recoverRS(signature []byte) (r *big.Int, s *big.Int, err error) {
+	r, s, err = parseRS(signature)
+	if err != nil {
+		return nil, nil, err
+	}
 	// Google may have already encured that the signature is valid, but we
 	// can't assume that.
 	if s.Cmp(secp256k1halfN) > 0 {
@@ -64,6 +73,10 @@ func pemToPubkey(publicKey []byte) (*ecdsa.PublicKey, error) {
 	if !pub.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) {
 		return nil, errors.New("not a ECDSA public key")
 	}
+	var curve asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(pub.Algorithm.Parameters.FullBytes, &curve); err == nil && !curve.Equal(oidNamedCurveSecp256k1) {
+		return nil, errors.New("not a secp256k1 public key")
+	}
 
 	// Convert to ecdsa.PublicKey
 	pk, err := crypto.UnmarshalPubkey(pub.PublicKey.Bytes)
@@ -104,8 +117,8 @@ func recoverAndVerify(digest, signature []byte, pubKey ecdsa.PublicKey) (Signatu
 
 	// Reconstruct the eth signature R || S || V
 	sign := make([]byte, 65)
-	copy(sign[:32], r.Bytes())
-	copy(sign[32:64], s.Bytes())
+	r.FillBytes(sign[:32])
+	s.FillBytes(sign[32:64])
 	sign[64] = 0x1b
 
 	if !verifyDigest(pubKey, digest, sign) {
@@ -1,10 +1,36 @@
 package signer
 
 import (
+	"context"
+	"crypto"
 	"crypto/ecdsa"
 )
 
+// SignerInterface signs digests with a secp256k1 key, producing Ethereum compatible signatures
 type SignerInterface interface {
 	Sign(digest []byte) (SignatureECDSA, error)
 	GetPublicKey() ecdsa.PublicKey
 }
+
+// ContextSigner is implemented by signers supporting cancellation of the signing request
+type ContextSigner interface {
+	SignerInterface
+	SignContext(ctx context.Context, digest []byte) (SignatureECDSA, error)
+}
+
+// KeySigner signs messages with a key of any supported algorithm
+type KeySigner interface {
+	// Algorithm returns the algorithm of the signing key
+	Algorithm() Algorithm
+	// Public returns the public key, *ecdsa.PublicKey or ed25519.PublicKey
+	Public() crypto.PublicKey
+	// SignMessage signs the message, which is hashed with SHA-256 for ECDSA keys.
+	// See Algorithm constants for the signature formats.
+	SignMessage(message []byte) ([]byte, error)
+}
+
+// ContextKeySigner is implemented by key signers supporting cancellation of the signing request
+type ContextKeySigner interface {
+	KeySigner
+	SignMessageContext(ctx context.Context, message []byte) ([]byte, error)
+}
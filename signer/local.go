@@ -0,0 +1,184 @@
+package signer
+
+import (
+	"context"
+	gocrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/cryptobyte"
+	cb_asn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+var oidNamedCurveSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+
+// ecPrivateKey is an ASN.1 encoded SEC1 EC private key, defined here: https://tools.ietf.org/html/rfc5915
+type ecPrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+// Decrypter is implemented by encryptor drivers (see encryptor/types.Encryptor)
+type Decrypter interface {
+	Decrypt(ciphertext string, appName string) (string, error)
+}
+
+// LocalSigner signs digests with a secp256k1 private key held in memory
+type LocalSigner struct {
+	key *ecdsa.PrivateKey
+}
+
+// NewLocalSigner creates a new local signer with the provided private key
+func NewLocalSigner(key *ecdsa.PrivateKey) (*LocalSigner, error) {
+	if key == nil || key.Curve != crypto.S256() {
+		return nil, errors.New("signer: private key is not a secp256k1 key")
+	}
+
+	return &LocalSigner{key: key}, nil
+}
+
+// NewLocalSignerFromKeystore creates a new local signer from an encrypted keystore JSON file
+func NewLocalSignerFromKeystore(path, passphrase string) (*LocalSigner, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("signer: unable to read keystore: %w", err)
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("signer: unable to decrypt keystore: %w", err)
+	}
+
+	return NewLocalSigner(key.PrivateKey)
+}
+
+// NewLocalSignerFromEncryptedPEM creates a new local signer from a SEC1 PEM private key
+// encrypted with the given decrypter
+func NewLocalSignerFromEncryptedPEM(ciphertext string, decrypter Decrypter, appName string) (*LocalSigner, error) {
+	data, err := decrypter.Decrypt(ciphertext, appName)
+	if err != nil {
+		return nil, fmt.Errorf("signer: unable to decrypt private key: %w", err)
+	}
+
+	key, err := pemToPrivkey([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("signer: failed to decode private key: %w", err)
+	}
+
+	return NewLocalSigner(key)
+}
+
+// NewDeterministicSigner creates a local signer with a key derived from the seed, for tests only
+func NewDeterministicSigner(seed string) *LocalSigner {
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte(seed)))
+	if err != nil {
+		// Keccak256 output is a valid secp256k1 key with overwhelming probability
+		panic(fmt.Sprintf("signer: invalid deterministic seed %q: %s", seed, err))
+	}
+
+	return &LocalSigner{key: key}
+}
+
+// GetPublicKey returns a public key
+func (c *LocalSigner) GetPublicKey() ecdsa.PublicKey {
+	return c.key.PublicKey
+}
+
+// Algorithm returns the algorithm of the private key
+func (c *LocalSigner) Algorithm() Algorithm {
+	return AlgorithmSecp256k1
+}
+
+// Public returns the public key
+func (c *LocalSigner) Public() gocrypto.PublicKey {
+	return &c.key.PublicKey
+}
+
+// SignMessage signs the SHA-256 digest of the message
+func (c *LocalSigner) SignMessage(message []byte) ([]byte, error) {
+	digest := sha256.Sum256(message)
+	return c.Sign(digest[:])
+}
+
+// SignMessageContext signs the message like SignMessage, unless the context is already done
+func (c *LocalSigner) SignMessageContext(ctx context.Context, message []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return c.SignMessage(message)
+}
+
+// SignContext signs the given digest like Sign, unless the context is already done
+func (c *LocalSigner) SignContext(ctx context.Context, digest []byte) (SignatureECDSA, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return c.Sign(digest)
+}
+
+// Sign the given digest using the private key and return ECDSA signature
+func (c *LocalSigner) Sign(digest []byte) (SignatureECDSA, error) {
+	sig, err := crypto.Sign(digest, c.key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Encode as ASN.1 like KMS providers do, so the signature goes through the same checks
+	var b cryptobyte.Builder
+	b.AddASN1(cb_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1BigInt(new(big.Int).SetBytes(sig[:32]))
+		b.AddASN1BigInt(new(big.Int).SetBytes(sig[32:64]))
+	})
+	der, err := b.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	return recoverAndVerify(digest, der, c.key.PublicKey)
+}
+
+// EncodePEM encodes a secp256k1 private key to SEC1 PEM
+func EncodePEM(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := asn1.Marshal(ecPrivateKey{
+		Version:       1,
+		PrivateKey:    crypto.FromECDSA(key),
+		NamedCurveOID: oidNamedCurveSecp256k1,
+		PublicKey:     asn1.BitString{Bytes: crypto.FromECDSAPub(&key.PublicKey)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
+// pemToPrivkey SEC1 PEM private key to ecdsa.PrivateKey
+func pemToPrivkey(data []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "EC PRIVATE KEY" {
+		return nil, errors.New("no EC PRIVATE KEY block found")
+	}
+
+	var priv ecPrivateKey
+	rest, err := asn1.Unmarshal(block.Bytes, &priv)
+	if err != nil || len(rest) > 0 {
+		return nil, fmt.Errorf("error unmarshaling private key: %w", err)
+	}
+	if len(priv.NamedCurveOID) != 0 && !priv.NamedCurveOID.Equal(oidNamedCurveSecp256k1) {
+		return nil, errors.New("not a secp256k1 private key")
+	}
+
+	return crypto.ToECDSA(priv.PrivateKey)
+}
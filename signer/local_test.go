@@ -0,0 +1,100 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+)
+
+type base64Decrypter struct{}
+
+func (base64Decrypter) Decrypt(ciphertext, appName string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	return string(data), err
+}
+
+func TestLocalSignerSign(t *testing.T) {
+	s := NewDeterministicSigner("opendax")
+	digest := crypto.Keccak256([]byte("bonjour"))
+
+	sig, err := s.Sign(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sig) != 65 || (sig[64] != 27 && sig[64] != 28) {
+		t.Fatalf("unexpected signature format: %x", sig)
+	}
+	if !verifyDigest(s.GetPublicKey(), digest, sig) {
+		t.Fatal("signature verification failed")
+	}
+
+	again, err := NewDeterministicSigner("opendax").Sign(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sig, again) {
+		t.Fatal("deterministic signer produced different signatures")
+	}
+
+	if address(t, s) == address(t, NewDeterministicSigner("finex")) {
+		t.Fatal("different seeds produced the same key")
+	}
+}
+
+func TestNewLocalSignerFromKeystore(t *testing.T) {
+	expected := NewDeterministicSigner("keystore")
+	key := &keystore.Key{
+		Id:         uuid.New(),
+		Address:    address(t, expected),
+		PrivateKey: expected.key,
+	}
+
+	keyJSON, err := keystore.EncryptKey(key, "changeme", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	if err := os.WriteFile(path, keyJSON, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewLocalSignerFromKeystore(path, "changeme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address(t, s) != address(t, expected) {
+		t.Fatalf("address mismatch: got %s, want %s", address(t, s), address(t, expected))
+	}
+
+	if _, err := NewLocalSignerFromKeystore(path, "wrong"); err == nil {
+		t.Fatal("expected error with wrong passphrase")
+	}
+}
+
+func TestNewLocalSignerFromEncryptedPEM(t *testing.T) {
+	expected := NewDeterministicSigner("pem")
+
+	data, err := EncodePEM(expected.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewLocalSignerFromEncryptedPEM(base64.StdEncoding.EncodeToString(data), base64Decrypter{}, "opendax")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address(t, s) != address(t, expected) {
+		t.Fatalf("address mismatch: got %s, want %s", address(t, s), address(t, expected))
+	}
+
+	if _, err := NewLocalSignerFromEncryptedPEM("", base64Decrypter{}, "opendax"); err == nil {
+		t.Fatal("expected error with empty PEM")
+	}
+}
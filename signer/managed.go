@@ -0,0 +1,379 @@
+package signer
+
+import (
+	"container/list"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+	"golang.org/x/time/rate"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const instrumentationName = "github.com/openware/pkg/signer"
+
+// NoRetry disables the retries of a ManagedSigner when set as MaxRetries
+const NoRetry = -1
+
+// ManagedOptions configures a ManagedSigner, zero values select the defaults
+type ManagedOptions struct {
+	// MaxConcurrency is the maximum number of in-flight signing requests, 10 by default
+	MaxConcurrency int
+	// RateLimit is the maximum number of signing requests per second, unlimited by default
+	RateLimit float64
+	// Timeout of a single signing attempt, 10 seconds by default
+	Timeout time.Duration
+	// MaxRetries is the maximum number of retries of a failed signing request, 5 by default
+	// as the zero value selects the default, set it to NoRetry to sign in a single attempt
+	MaxRetries int
+	// MinBackoff is the delay before the first retry, doubled on every retry, 100ms by default
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between retries, 5 seconds by default
+	MaxBackoff time.Duration
+	// Retryable reports whether a signing error is worth retrying, IsThrottlingError by default
+	Retryable func(error) bool
+	// CacheSize is the number of signatures kept by digest, caching is disabled by default
+	CacheSize int
+	// TracerProvider used for signing spans, usually the one returned by tracing.InitOTEL, spans are disabled by default
+	TracerProvider trace.TracerProvider
+	// MeterProvider used for signing metrics, metrics are disabled by default
+	MeterProvider metric.MeterProvider
+}
+
+// ManagedSigner decorates one or more signers sharing the same public key with
+// bounded concurrency, rate limiting, retries on throttling errors, caching and telemetry.
+// Signing requests are load-balanced across the signers in a round-robin fashion.
+type ManagedSigner struct {
+	signers   []SignerInterface
+	publicKey ecdsa.PublicKey
+	opts      ManagedOptions
+	next      uint32
+
+	slots   chan struct{}
+	limiter *rate.Limiter
+	cache   *signatureCache
+
+	tracer   trace.Tracer
+	requests metric.Int64Counter
+	retries  metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+// NewManagedSigner creates a new managed signer in front of the provided signers
+func NewManagedSigner(opts ManagedOptions, signers ...SignerInterface) (*ManagedSigner, error) {
+	if len(signers) == 0 {
+		return nil, errors.New("signer: at least one signer is required")
+	}
+
+	publicKey := signers[0].GetPublicKey()
+	for i, s := range signers[1:] {
+		pk := s.GetPublicKey()
+		if !publicKey.Equal(&pk) {
+			return nil, fmt.Errorf("signer: public key of signer %d does not match", i+1)
+		}
+	}
+
+	if opts.MaxConcurrency <= 0 {
+		opts.MaxConcurrency = 10
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	switch {
+	case opts.MaxRetries == 0:
+		opts.MaxRetries = 5
+	case opts.MaxRetries < 0:
+		opts.MaxRetries = 0
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = 100 * time.Millisecond
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = 5 * time.Second
+	}
+	if opts.Retryable == nil {
+		opts.Retryable = IsThrottlingError
+	}
+	if opts.TracerProvider == nil {
+		opts.TracerProvider = tracenoop.NewTracerProvider()
+	}
+	if opts.MeterProvider == nil {
+		opts.MeterProvider = metricnoop.NewMeterProvider()
+	}
+
+	m := &ManagedSigner{
+		signers:   signers,
+		publicKey: publicKey,
+		opts:      opts,
+		slots:     make(chan struct{}, opts.MaxConcurrency),
+		tracer:    opts.TracerProvider.Tracer(instrumentationName),
+	}
+
+	if opts.RateLimit > 0 {
+		m.limiter = rate.NewLimiter(rate.Limit(opts.RateLimit), opts.MaxConcurrency)
+	}
+	if opts.CacheSize > 0 {
+		m.cache = newSignatureCache(opts.CacheSize)
+	}
+
+	meter := opts.MeterProvider.Meter(instrumentationName)
+	var err error
+	if m.requests, err = meter.Int64Counter("signer.sign.requests",
+		metric.WithDescription("Number of signing requests by result")); err != nil {
+		return nil, err
+	}
+	if m.retries, err = meter.Int64Counter("signer.sign.retries",
+		metric.WithDescription("Number of retried signing attempts")); err != nil {
+		return nil, err
+	}
+	if m.duration, err = meter.Float64Histogram("signer.sign.duration",
+		metric.WithDescription("Duration of signing attempts"), metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// GetPublicKey returns a public key
+func (m *ManagedSigner) GetPublicKey() ecdsa.PublicKey {
+	return m.publicKey
+}
+
+// Sign the given digest and return ECDSA signature
+func (m *ManagedSigner) Sign(digest []byte) (SignatureECDSA, error) {
+	return m.SignContext(context.Background(), digest)
+}
+
+// SignContext signs the given digest, waiting for a free slot and retrying on throttling
+// errors until the context is done
+func (m *ManagedSigner) SignContext(ctx context.Context, digest []byte) (SignatureECDSA, error) {
+	ctx, span := m.tracer.Start(ctx, "signer.Sign", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	if sig, ok := m.cache.get(digest); ok {
+		span.SetAttributes(attribute.Bool("signer.cache_hit", true))
+		m.requests.Add(ctx, 1, metric.WithAttributes(attribute.String("result", "cached")))
+		return sig, nil
+	}
+
+	sig, err := m.sign(ctx, span, digest)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		m.requests.Add(ctx, 1, metric.WithAttributes(attribute.String("result", "error")))
+		return nil, err
+	}
+
+	m.cache.add(digest, sig)
+	m.requests.Add(ctx, 1, metric.WithAttributes(attribute.String("result", "ok")))
+	return sig, nil
+}
+
+// SignBatch signs the given digests concurrently, within the concurrency limit,
+// and returns the signatures in the same order
+func (m *ManagedSigner) SignBatch(ctx context.Context, digests [][]byte) ([]SignatureECDSA, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sigs := make([]SignatureECDSA, len(digests))
+	errs := make([]error, len(digests))
+
+	var wg sync.WaitGroup
+	for i, digest := range digests {
+		wg.Add(1)
+		go func(i int, digest []byte) {
+			defer wg.Done()
+			if sigs[i], errs[i] = m.SignContext(ctx, digest); errs[i] != nil {
+				cancel()
+			}
+		}(i, digest)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return nil, fmt.Errorf("signer: digest %d: %w", i, err)
+		}
+	}
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("signer: digest %d: %w", i, err)
+		}
+	}
+
+	return sigs, nil
+}
+
+func (m *ManagedSigner) sign(ctx context.Context, span trace.Span, digest []byte) (SignatureECDSA, error) {
+	select {
+	case m.slots <- struct{}{}:
+		defer func() { <-m.slots }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	backoff := m.opts.MinBackoff
+	for attempt := 0; ; attempt++ {
+		if m.limiter != nil {
+			if err := m.limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		idx := int(atomic.AddUint32(&m.next, 1)-1) % len(m.signers)
+		span.SetAttributes(attribute.Int("signer.key_index", idx), attribute.Int("signer.attempt", attempt))
+
+		start := time.Now()
+		sig, err := m.signAttempt(ctx, m.signers[idx], digest)
+		m.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attribute.Int("signer.key_index", idx)))
+		if err == nil {
+			return sig, nil
+		}
+
+		// An attempt timeout is retryable as long as the caller context is not done
+		retryable := m.opts.Retryable(err) || errors.Is(err, context.DeadlineExceeded)
+		if attempt >= m.opts.MaxRetries || !retryable || ctx.Err() != nil {
+			return nil, err
+		}
+
+		m.retries.Add(ctx, 1)
+		span.AddEvent("retry", trace.WithAttributes(attribute.String("error", err.Error())))
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+
+		if backoff *= 2; backoff > m.opts.MaxBackoff {
+			backoff = m.opts.MaxBackoff
+		}
+	}
+}
+
+func (m *ManagedSigner) signAttempt(ctx context.Context, s SignerInterface, digest []byte) (SignatureECDSA, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.opts.Timeout)
+	defer cancel()
+
+	if cs, ok := s.(ContextSigner); ok {
+		return cs.SignContext(ctx, digest)
+	}
+
+	type result struct {
+		sig SignatureECDSA
+		err error
+	}
+
+	// Signers without context support are abandoned on timeout, their result is discarded
+	done := make(chan result, 1)
+	go func() {
+		sig, err := s.Sign(digest)
+		done <- result{sig, err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.sig, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// IsThrottlingError reports whether the error is a KMS throttling or transient availability error
+func IsThrottlingError(err error) bool {
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		switch awsErr.Code() {
+		case "ThrottlingException", kms.ErrCodeLimitExceededException,
+			kms.ErrCodeDependencyTimeoutException, kms.ErrCodeInternalException:
+			return true
+		}
+		return false
+	}
+
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case grpccodes.ResourceExhausted, grpccodes.Unavailable:
+			return true
+		}
+	}
+
+	return false
+}
+
+// signatureCache is a LRU cache of signatures by digest
+type signatureCache struct {
+	mu    sync.Mutex
+	size  int
+	items map[string]*list.Element
+	order *list.List
+}
+
+type signatureCacheItem struct {
+	digest string
+	sig    SignatureECDSA
+}
+
+func newSignatureCache(size int) *signatureCache {
+	return &signatureCache{
+		size:  size,
+		items: make(map[string]*list.Element, size),
+		order: list.New(),
+	}
+}
+
+func (c *signatureCache) get(digest []byte) (SignatureECDSA, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[string(digest)]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+
+	sig := el.Value.(*signatureCacheItem).sig
+	return append(SignatureECDSA(nil), sig...), true
+}
+
+func (c *signatureCache) add(digest []byte, sig SignatureECDSA) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[string(digest)]; ok {
+		c.order.MoveToFront(el)
+		return
+	}
+
+	item := &signatureCacheItem{digest: string(digest), sig: append(SignatureECDSA(nil), sig...)}
+	c.items[item.digest] = c.order.PushFront(item)
+
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*signatureCacheItem).digest)
+	}
+}
@@ -0,0 +1,216 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/ethereum/go-ethereum/crypto"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// flakySigner fails with the given error the first failures calls
+type flakySigner struct {
+	key      *LocalSigner
+	failures int32
+	err      error
+	delay    time.Duration
+
+	calls    int32
+	inFlight int32
+	peak     int32
+}
+
+func (f *flakySigner) Sign(digest []byte) (SignatureECDSA, error) {
+	n := atomic.AddInt32(&f.inFlight, 1)
+	defer atomic.AddInt32(&f.inFlight, -1)
+	for {
+		peak := atomic.LoadInt32(&f.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(&f.peak, peak, n) {
+			break
+		}
+	}
+
+	time.Sleep(f.delay)
+	if atomic.AddInt32(&f.calls, 1) <= f.failures {
+		return nil, f.err
+	}
+
+	return f.key.Sign(digest)
+}
+
+func (f *flakySigner) GetPublicKey() ecdsa.PublicKey {
+	return f.key.GetPublicKey()
+}
+
+var fastRetries = ManagedOptions{MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+func TestManagedSignerRetry(t *testing.T) {
+	digest := crypto.Keccak256([]byte("bonjour"))
+
+	t.Run("throttling error", func(t *testing.T) {
+		s := &flakySigner{key: NewDeterministicSigner("opendax"), failures: 2, err: awserr.New("ThrottlingException", "rate exceeded", nil)}
+		m, err := NewManagedSigner(fastRetries, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sig, err := m.Sign(digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !verifyDigest(m.GetPublicKey(), digest, sig) {
+			t.Fatal("signature verification failed")
+		}
+		if s.calls != 3 {
+			t.Fatalf("unexpected number of calls: %d", s.calls)
+		}
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		s := &flakySigner{key: NewDeterministicSigner("opendax"), failures: 10, err: status.Error(grpccodes.ResourceExhausted, "quota")}
+		opts := fastRetries
+		opts.MaxRetries = 3
+		m, err := NewManagedSigner(opts, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := m.Sign(digest); err == nil {
+			t.Fatal("expected error")
+		}
+		if s.calls != 4 {
+			t.Fatalf("unexpected number of calls: %d", s.calls)
+		}
+	})
+
+	t.Run("no retry", func(t *testing.T) {
+		s := &flakySigner{key: NewDeterministicSigner("opendax"), failures: 1, err: awserr.New("ThrottlingException", "rate exceeded", nil)}
+		opts := fastRetries
+		opts.MaxRetries = NoRetry
+		m, err := NewManagedSigner(opts, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := m.Sign(digest); err == nil {
+			t.Fatal("expected error")
+		}
+		if s.calls != 1 {
+			t.Fatalf("unexpected number of calls: %d", s.calls)
+		}
+	})
+
+	t.Run("non retryable error", func(t *testing.T) {
+		s := &flakySigner{key: NewDeterministicSigner("opendax"), failures: 1, err: errors.New("access denied")}
+		m, err := NewManagedSigner(fastRetries, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := m.Sign(digest); err == nil {
+			t.Fatal("expected error")
+		}
+		if s.calls != 1 {
+			t.Fatalf("unexpected number of calls: %d", s.calls)
+		}
+	})
+}
+
+func TestManagedSignerContext(t *testing.T) {
+	s := &flakySigner{key: NewDeterministicSigner("opendax"), delay: 100 * time.Millisecond}
+	m, err := NewManagedSigner(ManagedOptions{Timeout: time.Second}, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := m.SignContext(ctx, crypto.Keccak256([]byte("bonjour"))); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestManagedSignerConcurrency(t *testing.T) {
+	s := &flakySigner{key: NewDeterministicSigner("opendax"), delay: 5 * time.Millisecond}
+	m, err := NewManagedSigner(ManagedOptions{MaxConcurrency: 2}, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digests := make([][]byte, 10)
+	for i := range digests {
+		digests[i] = crypto.Keccak256([]byte{byte(i)})
+	}
+
+	sigs, err := m.SignBatch(context.Background(), digests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, sig := range sigs {
+		if !verifyDigest(m.GetPublicKey(), digests[i], sig) {
+			t.Fatalf("signature %d verification failed", i)
+		}
+	}
+	if s.peak > 2 {
+		t.Fatalf("concurrency limit exceeded: %d", s.peak)
+	}
+}
+
+func TestManagedSignerPool(t *testing.T) {
+	key := NewDeterministicSigner("opendax")
+	signers := []*flakySigner{{key: key}, {key: key}, {key: key}}
+
+	m, err := NewManagedSigner(ManagedOptions{}, signers[0], signers[1], signers[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 9; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := m.Sign(crypto.Keccak256([]byte{byte(i)})); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i, s := range signers {
+		if s.calls != 3 {
+			t.Fatalf("signer %d got %d calls, want 3", i, s.calls)
+		}
+	}
+
+	if _, err := NewManagedSigner(ManagedOptions{}, key, NewDeterministicSigner("finex")); err == nil {
+		t.Fatal("expected error with mismatching public keys")
+	}
+}
+
+func TestManagedSignerCache(t *testing.T) {
+	s := &flakySigner{key: NewDeterministicSigner("opendax")}
+	m, err := NewManagedSigner(ManagedOptions{CacheSize: 1}, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, second := crypto.Keccak256([]byte("first")), crypto.Keccak256([]byte("second"))
+	for _, digest := range [][]byte{first, first, second, first} {
+		if _, err := m.Sign(digest); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if s.calls != 3 {
+		t.Fatalf("unexpected number of calls: %d", s.calls)
+	}
+}